@@ -0,0 +1,347 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"openstack-reporter/internal/compliance"
+	"openstack-reporter/internal/config"
+	"openstack-reporter/internal/delta"
+	"openstack-reporter/internal/export"
+	"openstack-reporter/internal/filter"
+	"openstack-reporter/internal/models"
+	"openstack-reporter/internal/openstack"
+	"openstack-reporter/internal/pdf"
+	"openstack-reporter/internal/storage"
+)
+
+// Exit codes for cron and CI. diff follows diff(1) and exits with
+// exitChanges when the reports differ and exitTrouble when it fails.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+	exitChanges = 1
+	exitTrouble = 2
+)
+
+// command is a subcommand of the binary. setup defines the flags of the
+// command and returns the function run once the configuration is loaded.
+type command struct {
+	args    []string
+	summary string
+	setup   func(flags *flag.FlagSet) func(cfg *config.Config, args []string) int
+}
+
+var commands = map[string]command{
+	"serve": {
+		summary: "Run the web server (default)",
+		setup: func(*flag.FlagSet) func(*config.Config, []string) int {
+			return serve
+		},
+	},
+	"collect": {
+		summary: "Collect resources from OpenStack into a report file or the data directory",
+		setup:   collectCommand,
+	},
+	"export": {
+		summary: "Export a report as PDF or CSV",
+		setup:   exportCommand,
+	},
+	"diff": {
+		args:    []string{"old.json", "new.json"},
+		summary: "Show resources added, removed and changed status between two reports",
+		setup:   diffCommand,
+	},
+}
+
+// usage lists the commands
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s [command] [flags]\n\nCommands:\n", filepath.Base(os.Args[0]))
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command.\n", filepath.Base(os.Args[0]))
+	fmt.Fprintf(w, "Exit codes: 0 success, 1 failure or diff found changes, 2 invalid usage or configuration.\n")
+}
+
+// usage describes the command and its flags, configuration flags included
+func (c command) usage(flags *flag.FlagSet) {
+	w := flags.Output()
+	fmt.Fprintf(w, "Usage: %s %s [flags]", filepath.Base(os.Args[0]), flags.Name())
+	for _, arg := range c.args {
+		fmt.Fprintf(w, " %s", arg)
+	}
+	fmt.Fprintf(w, "\n\n%s\n\nFlags:\n", c.summary)
+	flags.PrintDefaults()
+}
+
+// collectCommand collects a report, writing it to --out or saving it to
+// storage like a refresh of the web server
+func collectCommand(flags *flag.FlagSet) func(*config.Config, []string) int {
+	out := flags.String("out", "", "report file, - for stdout, saved to the data directory if empty")
+
+	return func(cfg *config.Config, _ []string) int {
+		// SIGINT, SIGTERM and the refresh timeout abort collection, nothing is written then
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		if cfg.Collection.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.Collection.Timeout)
+			defer cancel()
+		}
+
+		started := time.Now()
+		client, err := openstack.NewClient(ctx, cfg.OpenStack)
+		if err != nil {
+			slog.Error("Failed to create OpenStack client", "error", err)
+			return exitFailure
+		}
+		report, err := client.GetAllResources(ctx)
+		if err != nil {
+			slog.Error("Failed to collect resources", "error", err)
+			return exitFailure
+		}
+		slog.Info("Report collected", "resources", len(report.Resources),
+			"projects", len(report.Projects), "duration", time.Since(started).Round(time.Millisecond))
+
+		if *out != "" {
+			err := writeOutput(*out, func(w io.Writer) error {
+				return storage.WriteReport(w, report)
+			})
+			if err != nil {
+				slog.Error("Failed to write report", "path", *out, "error", err)
+				return exitFailure
+			}
+			return exitOK
+		}
+
+		store := storage.NewStorage(cfg.Storage)
+		if err := store.Initialize(); err != nil {
+			slog.Error("Failed to initialize storage", "error", err)
+			return exitFailure
+		}
+		if err := store.SaveReport(report); err != nil {
+			slog.Error("Failed to save report", "error", err)
+			return exitFailure
+		}
+		if err := store.CleanupBackups(); err != nil {
+			slog.Warn("Failed to clean up backups", "error", err)
+		}
+		slog.Info("Report saved", "data_dir", store.DataDir())
+		return exitOK
+	}
+}
+
+// exportCommand renders a report file, or the saved report, as PDF or CSV
+func exportCommand(flags *flag.FlagSet) func(*config.Config, []string) int {
+	format := flags.String("format", "pdf", "output format, pdf or csv")
+	in := flags.String("in", "", "report file, - for stdin, the saved report if empty")
+	out := flags.String("out", "", "output file, - for stdout, openstack_report_<time>.<format> if empty")
+	query := flags.String("filter", "", "resource filter in the query syntax of the API, e.g. type=server&project=web")
+	groupByTag := flags.String("group-by-tag", "", "tag key to group PDF resources by")
+
+	return func(cfg *config.Config, _ []string) int {
+		if *format != "pdf" && *format != "csv" {
+			slog.Error("Unsupported export format", "format", *format)
+			return exitUsage
+		}
+		values, err := url.ParseQuery(*query)
+		if err != nil {
+			slog.Error("Invalid filter", "filter", *query, "error", err)
+			return exitUsage
+		}
+
+		report, err := readReport(*in, cfg)
+		if err != nil {
+			slog.Error("Failed to load report", "error", err)
+			return exitFailure
+		}
+		criteria := filter.FromQuery(values)
+		report = filter.Apply(report, criteria)
+
+		path := *out
+		if path == "" {
+			path = "openstack_report_" + time.Now().Format("2006-01-02_15-04-05") + "." + *format
+		}
+
+		var write func(w io.Writer) error
+		switch *format {
+		case "csv":
+			write = func(w io.Writer) error {
+				return export.WriteCSV(w, report)
+			}
+		case "pdf":
+			complianceReport, err := evaluateCompliance(report, cfg.Files.CompliancePolicy)
+			if err != nil {
+				slog.Warn("PDF export skipping compliance section", "error", err)
+			}
+			data, err := pdf.NewGenerator().GenerateReportWithOptions(report, pdf.Options{
+				GroupByTag: *groupByTag,
+				Compliance: complianceReport,
+				Scope:      criteria.String(),
+				Language:   cfg.Language,
+			})
+			if err != nil {
+				slog.Error("PDF export failed", "error", err)
+				return exitFailure
+			}
+			write = func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}
+		}
+
+		if err := writeOutput(path, write); err != nil {
+			slog.Error("Failed to write export", "path", path, "error", err)
+			return exitFailure
+		}
+		slog.Info("Report exported", "format", *format, "path", path, "resources", len(report.Resources))
+		return exitOK
+	}
+}
+
+// diffCommand compares two report files
+func diffCommand(flags *flag.FlagSet) func(*config.Config, []string) int {
+	format := flags.String("format", "text", "output format, text or json")
+
+	return func(cfg *config.Config, args []string) int {
+		if *format != "text" && *format != "json" {
+			slog.Error("Unsupported diff format", "format", *format)
+			return exitTrouble
+		}
+		previous, err := readReportFile(args[0])
+		if err != nil {
+			slog.Error("Failed to load report", "path", args[0], "error", err)
+			return exitTrouble
+		}
+		current, err := readReportFile(args[1])
+		if err != nil {
+			slog.Error("Failed to load report", "path", args[1], "error", err)
+			return exitTrouble
+		}
+
+		changes := delta.Compute(previous, current)
+		if *format == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(changes)
+		} else {
+			err = writeDelta(os.Stdout, changes)
+		}
+		if err != nil {
+			slog.Error("Failed to write diff", "error", err)
+			return exitTrouble
+		}
+
+		if changes.IsEmpty() {
+			return exitOK
+		}
+		return exitChanges
+	}
+}
+
+// writeDelta prints one line per change followed by a summary
+func writeDelta(w io.Writer, changes *delta.Delta) error {
+	var b strings.Builder
+	for _, resource := range changes.Added {
+		fmt.Fprintf(&b, "+ %s\n", describeResource(resource))
+	}
+	for _, resource := range changes.Removed {
+		fmt.Fprintf(&b, "- %s\n", describeResource(resource))
+	}
+	for _, change := range changes.StatusChanges {
+		fmt.Fprintf(&b, "~ %s: %s -> %s\n", describeResource(change.Resource), change.From, change.To)
+	}
+	fmt.Fprintf(&b, "%d added, %d removed, %d status changed\n",
+		len(changes.Added), len(changes.Removed), len(changes.StatusChanges))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func describeResource(resource models.Resource) string {
+	name := resource.Name
+	if name == "" {
+		name = resource.ID
+	}
+	return fmt.Sprintf("%s %s (%s) in project %s", resource.Type, name, resource.ID, resource.ProjectName)
+}
+
+// evaluateCompliance evaluates the report against the policy file, a missing
+// file is not an error
+func evaluateCompliance(report *models.ResourceReport, path string) (*compliance.Report, error) {
+	policy, err := compliance.LoadPolicy(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return compliance.Evaluate(report, policy), nil
+}
+
+// readReport reads the report file, or the saved report when path is empty
+func readReport(path string, cfg *config.Config) (*models.ResourceReport, error) {
+	if path == "" {
+		return storage.NewStorage(cfg.Storage).LoadReport()
+	}
+	return readReportFile(path)
+}
+
+// readReportFile reads a report written by collect, - reads stdin
+func readReportFile(path string) (*models.ResourceReport, error) {
+	if path == "-" {
+		return storage.ReadReport(os.Stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return storage.ReadReport(file)
+}
+
+// writeOutput writes to stdout for -, otherwise to a temporary file renamed
+// over path once complete, so a failed run never leaves a truncated file
+func writeOutput(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(file.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,252 @@
+// Package audit keeps an append-only JSON lines log of who refreshed,
+// exported and read the report, rotated by size, and queries it.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFile is used when the configuration sets no file
+const DefaultFile = "data/audit.jsonl"
+
+// Rotation defaults
+const (
+	DefaultMaxSizeMB = 10
+	DefaultMaxFiles  = 5
+)
+
+// Config selects the audit log file and its rotation
+type Config struct {
+	File      string `yaml:"file" json:"file"`
+	MaxSizeMB int    `yaml:"max_size_mb" json:"max_size_mb"`
+	MaxFiles  int    `yaml:"max_files" json:"max_files"`
+}
+
+// Outcomes
+const (
+	OutcomeSuccess = "success"
+	// OutcomeDenied is a request rejected by authentication or permissions
+	OutcomeDenied = "denied"
+	OutcomeFailed = "failed"
+	// OutcomeCompleted is a background refresh that saved a new report
+	OutcomeCompleted = "completed"
+	// OutcomeCancelled is a background refresh cancelled before it saved a report
+	OutcomeCancelled = "cancelled"
+)
+
+// Record is a single audited operation
+type Record struct {
+	Time time.Time `json:"time"`
+	// User and AuthMethod are empty for anonymous clients or without authentication
+	User       string              `json:"user,omitempty"`
+	AuthMethod string              `json:"auth_method,omitempty"`
+	ClientIP   string              `json:"client_ip,omitempty"`
+	Method     string              `json:"method,omitempty"`
+	Path       string              `json:"path"`
+	Params     map[string][]string `json:"params,omitempty"`
+	// Body holds the JSON fields of a state-changing request, credentials redacted
+	Body    map[string]interface{} `json:"body,omitempty"`
+	Status  int                    `json:"status,omitempty"`
+	Outcome string                 `json:"outcome"`
+	Error   string                 `json:"error,omitempty"`
+	// SessionID links a background refresh to the request that started it
+	SessionID  string `json:"session_id,omitempty"`
+	Resources  *int   `json:"resources,omitempty"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Query selects records, empty fields match everything
+type Query struct {
+	User string
+	IP   string
+	// Path matches records whose path starts with it
+	Path    string
+	Outcome string
+	Since   time.Time
+	Until   time.Time
+	Limit   int
+}
+
+// Match checks whether the record satisfies the query
+func (q Query) Match(record Record) bool {
+	switch {
+	case q.User != "" && record.User != q.User:
+		return false
+	case q.IP != "" && record.ClientIP != q.IP:
+		return false
+	case q.Path != "" && !strings.HasPrefix(record.Path, q.Path):
+		return false
+	case q.Outcome != "" && record.Outcome != q.Outcome:
+		return false
+	case !q.Since.IsZero() && record.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !record.Time.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// Log appends records to a JSON lines file. When the file would exceed
+// maxSize it is renamed to <file>.1, older files shift up to maxFiles.
+type Log struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Open opens the configured audit log, empty settings use the defaults
+func Open(config Config) (*Log, error) {
+	if config.File == "" {
+		config.File = DefaultFile
+	}
+	if config.MaxSizeMB == 0 {
+		config.MaxSizeMB = DefaultMaxSizeMB
+	}
+	if config.MaxFiles == 0 {
+		config.MaxFiles = DefaultMaxFiles
+	}
+	if config.MaxSizeMB < 0 || config.MaxFiles < 0 {
+		return nil, fmt.Errorf("audit max_size_mb and max_files must be positive")
+	}
+	return NewLog(config.File, int64(config.MaxSizeMB)<<20, config.MaxFiles)
+}
+
+// NewLog opens the file at path for appending
+func NewLog(path string, maxSize int64, maxFiles int) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	l := &Log{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Path returns the file records are appended to
+func (l *Log) Path() string {
+	return l.path
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// Write appends a record, rotating the file first if it is full
+func (l *Log) Write(record Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+	record.Time = record.Time.UTC()
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts <file>.N to <file>.N+1, dropping the oldest, and starts a new file
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit log: %w", err)
+	}
+	os.Remove(l.rotated(l.maxFiles))
+	for i := l.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(l.rotated(i), l.rotated(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.rotated(1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return l.open()
+}
+
+func (l *Log) rotated(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Close closes the current file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// Search returns matching records, newest first, from the current and
+// rotated files
+func (l *Log) Search(query Query) ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records := []Record{}
+	for i := 0; i <= l.maxFiles; i++ {
+		path := l.path
+		if i > 0 {
+			path = l.rotated(i)
+		}
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+
+		var matched []Record
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 64*1024), 1<<20)
+		for scanner.Scan() {
+			var record Record
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				continue
+			}
+			if query.Match(record) {
+				matched = append(matched, record)
+			}
+		}
+		for j := len(matched) - 1; j >= 0; j-- {
+			records = append(records, matched[j])
+			if query.Limit > 0 && len(records) >= query.Limit {
+				return records, nil
+			}
+		}
+	}
+	return records, nil
+}
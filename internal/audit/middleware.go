@@ -0,0 +1,138 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"openstack-reporter/internal/auth"
+	"openstack-reporter/internal/logging"
+)
+
+// maxCapturedBody limits the part of a response kept to find its error
+const maxCapturedBody = 4096
+
+// maxRecordedBody limits request bodies decoded into the record, larger ones are left out
+const maxRecordedBody = 16384
+
+// captureWriter keeps the start of the response body
+type captureWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	if room := maxCapturedBody - w.body.Len(); room > 0 {
+		if len(data) < room {
+			room = len(data)
+		}
+		w.body.Write(data[:room])
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// Middleware records the request after it is handled. It must run before
+// auth.Require, so that denied requests are recorded as well.
+func (l *Log) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		body := readBody(c)
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		record := NewRecord(c)
+		record.Time = start
+		record.Body = body
+		record.Status = c.Writer.Status()
+		record.DurationMS = time.Since(start).Milliseconds()
+		switch {
+		case record.Status == http.StatusUnauthorized || record.Status == http.StatusForbidden:
+			record.Outcome = OutcomeDenied
+		case record.Status >= http.StatusBadRequest:
+			record.Outcome = OutcomeFailed
+		default:
+			record.Outcome = OutcomeSuccess
+		}
+
+		// JSON responses carry the error or the refresh session
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "application/json") {
+			var response struct {
+				Error     string `json:"error"`
+				Details   string `json:"details"`
+				SessionID string `json:"session_id"`
+			}
+			if json.Unmarshal(writer.body.Bytes(), &response) == nil {
+				record.Error = strings.TrimSuffix(response.Error+": "+response.Details, ": ")
+				record.SessionID = response.SessionID
+			}
+		}
+
+		if err := l.Write(record); err != nil {
+			slog.Warn("Failed to write audit record", "error", err)
+		}
+	}
+}
+
+// readBody decodes the JSON object sent with a state-changing request and
+// restores the body for the handler. Credential-like fields and values are
+// redacted like log attributes.
+func readBody(c *gin.Context) map[string]interface{} {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return nil
+	}
+	if c.Request.Body == nil || !strings.HasPrefix(c.ContentType(), "application/json") {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRecordedBody+1))
+	c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(data), c.Request.Body), c.Request.Body}
+	if err != nil || len(data) > maxRecordedBody {
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(data, &fields) != nil || len(fields) == 0 {
+		return nil
+	}
+	for key, value := range fields {
+		fields[key] = logging.RedactValue(key, value)
+	}
+	return fields
+}
+
+// readCloser reads the buffered start of a body before the rest and closes the original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// NewRecord describes the client, route and query parameters of a request
+func NewRecord(c *gin.Context) Record {
+	record := Record{
+		Time:     time.Now(),
+		ClientIP: c.ClientIP(),
+		Method:   c.Request.Method,
+		Path:     c.Request.URL.Path,
+	}
+	if query := c.Request.URL.Query(); len(query) > 0 {
+		record.Params = query
+	}
+	if principal := auth.PrincipalFrom(c); principal != nil {
+		record.User = principal.Name
+		record.AuthMethod = principal.Method
+	}
+	return record
+}
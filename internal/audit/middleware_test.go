@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"openstack-reporter/internal/logging"
+)
+
+func newRouter(t *testing.T) (*gin.Engine, *Log) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	log, err := NewLog(filepath.Join(t.TempDir(), "audit.jsonl"), 1<<20, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { log.Close() })
+
+	r := gin.New()
+	echo := func(c *gin.Context) {
+		var request map[string]interface{}
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"name": request["name"]})
+	}
+	r.POST("/api/tokens", log.Middleware(), echo)
+	r.GET("/api/resources", log.Middleware(), func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	return r, log
+}
+
+func TestMiddlewareRecordsBody(t *testing.T) {
+	r, log := newRouter(t)
+
+	body := `{"name":"ci","permissions":["read","export"],"expires_at":"2027-01-01T00:00:00Z",` +
+		`"secret":"hunter2","options":{"api_key":"abc","note":"OS_PASSWORD=hunter2"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/tokens", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// The handler still reads the whole body
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"name":"ci"`) {
+		t.Fatalf("response %d %s", w.Code, w.Body.String())
+	}
+
+	records, err := log.Search(Query{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Search = %+v, %v", records, err)
+	}
+	want := map[string]interface{}{
+		"name":        "ci",
+		"permissions": []interface{}{"read", "export"},
+		"expires_at":  "2027-01-01T00:00:00Z",
+		"secret":      logging.Redacted,
+		"options": map[string]interface{}{
+			"api_key": logging.Redacted,
+			"note":    "OS_PASSWORD=" + logging.Redacted,
+		},
+	}
+	if !reflect.DeepEqual(records[0].Body, want) {
+		t.Errorf("body = %#v, want %#v", records[0].Body, want)
+	}
+	if records[0].Outcome != OutcomeSuccess || records[0].Status != http.StatusCreated {
+		t.Errorf("record = %+v", records[0])
+	}
+}
+
+func TestMiddlewareSkipsBody(t *testing.T) {
+	r, log := newRouter(t)
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/resources?type=server", nil),
+		httptest.NewRequest(http.MethodPost, "/api/tokens", strings.NewReader(`{"name":`)),
+		httptest.NewRequest(http.MethodPost, "/api/tokens", strings.NewReader(`{"name":"`+strings.Repeat("x", maxRecordedBody)+`"}`)),
+	}
+	for _, req := range requests {
+		if req.Method == http.MethodPost {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	records, err := log.Search(Query{})
+	if err != nil || len(records) != len(requests) {
+		t.Fatalf("Search = %+v, %v", records, err)
+	}
+	for _, record := range records {
+		if record.Body != nil {
+			t.Errorf("%s %s: body = %v, want none", record.Method, record.Path, record.Body)
+		}
+	}
+
+	// The oversized body is still handed to the handler in full
+	newest := records[0]
+	if newest.Outcome != OutcomeSuccess {
+		t.Errorf("oversized body: outcome %s, error %q", newest.Outcome, newest.Error)
+	}
+}
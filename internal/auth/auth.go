@@ -0,0 +1,322 @@
+// Package auth authenticates web UI and API clients with static API tokens,
+// HTTP basic credentials from a hashed user file, OIDC login or Keystone
+// credentials, and checks their permissions. Browser logins are kept in
+// signed session cookies.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/yaml.v3"
+
+	"openstack-reporter/internal/storage"
+)
+
+// DefaultConfigFile is used when the configuration sets no file
+const DefaultConfigFile = "auth.yaml"
+
+// Permission is an operation a principal may perform
+type Permission string
+
+// Permissions
+const (
+	// PermissionRead allows viewing the report and derived data
+	PermissionRead Permission = "read"
+	// PermissionExport allows PDF/DOT exports and mailing reports
+	PermissionExport Permission = "export"
+	// PermissionRefresh allows collecting data from OpenStack
+	PermissionRefresh Permission = "refresh"
+	// PermissionAdmin allows managing API tokens
+	PermissionAdmin Permission = "admin"
+)
+
+// Authentication methods
+const (
+	MethodToken    = "token"
+	MethodBasic    = "basic"
+	MethodOIDC     = "oidc"
+	MethodKeystone = "keystone"
+	MethodAPIToken = "api_token"
+)
+
+// defaultSessionTTL is the lifetime of browser sessions
+const defaultSessionTTL = 12 * time.Hour
+
+// Principal is an authenticated client
+type Principal struct {
+	Name        string       `json:"name"`
+	Method      string       `json:"method"`
+	Permissions []Permission `json:"permissions"`
+	// Projects are IDs of the projects the principal may see, nil means all
+	Projects []string `json:"projects"`
+}
+
+// Can reports whether the principal has the permission
+func (p *Principal) Can(permission Permission) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AllProjects reports whether the principal may see every project
+func (p *Principal) AllProjects() bool {
+	return p.Projects == nil
+}
+
+// SessionConfig configures browser session cookies
+type SessionConfig struct {
+	// Secret signs session cookies, may be omitted in favour of AUTH_SESSION_SECRET
+	Secret string        `yaml:"secret"`
+	TTL    time.Duration `yaml:"ttl"`
+}
+
+// TokenConfig is a static API token, stored as SHA-256 hex digest
+type TokenConfig struct {
+	Name        string       `yaml:"name"`
+	SHA256      string       `yaml:"sha256"`
+	Permissions []Permission `yaml:"permissions"`
+}
+
+// BasicConfig enables HTTP basic authentication and the login form
+type BasicConfig struct {
+	UsersFile string `yaml:"users_file"`
+}
+
+// User is an entry of the basic users file
+type User struct {
+	Name string `yaml:"name"`
+	// PasswordHash is a bcrypt hash, e.g. from htpasswd -nbB
+	PasswordHash string       `yaml:"password_hash"`
+	Permissions  []Permission `yaml:"permissions"`
+}
+
+// Config is the authentication configuration
+type Config struct {
+	Session  SessionConfig   `yaml:"session"`
+	Tokens   []TokenConfig   `yaml:"tokens"`
+	Basic    *BasicConfig    `yaml:"basic"`
+	OIDC     *OIDCConfig     `yaml:"oidc"`
+	Keystone *KeystoneConfig `yaml:"keystone"`
+}
+
+// Authenticator verifies credentials and sessions
+type Authenticator struct {
+	config   *Config
+	tokens   []token
+	users    map[string]User
+	sessions *sessionSigner
+	oidc     *oidcProvider
+	keystone *keystoneProvider
+	store    *TokenStore
+}
+
+type token struct {
+	name        string
+	digest      []byte
+	permissions []Permission
+}
+
+// dummyHash is compared against for unknown users so that response
+// times do not reveal which user names exist
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("openstack-reporter"), bcrypt.DefaultCost)
+
+// LoadConfig reads and validates a YAML auth config, Keystone settings left
+// empty are taken from keystoneDefaults
+func LoadConfig(path string, keystoneDefaults KeystoneConfig) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth config: %w", err)
+	}
+
+	config := Config{Session: SessionConfig{TTL: defaultSessionTTL}}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse auth config: %w", err)
+	}
+
+	if secret := os.Getenv("AUTH_SESSION_SECRET"); secret != "" {
+		config.Session.Secret = secret
+	}
+	if config.Session.TTL <= 0 {
+		return nil, fmt.Errorf("auth session ttl must be positive")
+	}
+	if len(config.Tokens) == 0 && config.Basic == nil && config.OIDC == nil && config.Keystone == nil {
+		return nil, fmt.Errorf("auth config enables no method, configure tokens, basic, oidc or keystone")
+	}
+	for i, token := range config.Tokens {
+		if token.Name == "" {
+			return nil, fmt.Errorf("auth token %d has no name", i+1)
+		}
+		if digest, err := hex.DecodeString(token.SHA256); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("auth token %q sha256 must be a hex SHA-256 digest", token.Name)
+		}
+		if config.Tokens[i].Permissions, err = validatePermissions(token.Permissions); err != nil {
+			return nil, fmt.Errorf("auth token %q: %w", token.Name, err)
+		}
+	}
+	if config.Basic != nil && config.Basic.UsersFile == "" {
+		return nil, fmt.Errorf("auth basic requires users_file")
+	}
+	if config.OIDC != nil {
+		if err := config.OIDC.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if config.Keystone != nil {
+		if err := config.Keystone.validate(keystoneDefaults); err != nil {
+			return nil, err
+		}
+	}
+	return &config, nil
+}
+
+// LoadUsers reads and validates a YAML users file
+func LoadUsers(path string) (map[string]User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read users file: %w", err)
+	}
+
+	var file struct {
+		Users []User `yaml:"users"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse users file: %w", err)
+	}
+
+	users := make(map[string]User, len(file.Users))
+	for i, user := range file.Users {
+		if user.Name == "" {
+			return nil, fmt.Errorf("user %d has no name", i+1)
+		}
+		if _, err := bcrypt.Cost([]byte(user.PasswordHash)); err != nil {
+			return nil, fmt.Errorf("user %q password_hash must be a bcrypt hash: %w", user.Name, err)
+		}
+		if user.Permissions, err = validatePermissions(user.Permissions); err != nil {
+			return nil, fmt.Errorf("user %q: %w", user.Name, err)
+		}
+		users[user.Name] = user
+	}
+	return users, nil
+}
+
+// NewAuthenticator prepares configured authentication methods and the
+// managed API tokens kept in storage
+func NewAuthenticator(config *Config, storage *storage.Storage) (*Authenticator, error) {
+	secret := []byte(config.Session.Secret)
+	if len(secret) == 0 {
+		// Sessions do not survive restarts without a configured secret
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate session secret: %w", err)
+		}
+	}
+
+	store, err := NewTokenStore(storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API tokens: %w", err)
+	}
+
+	a := &Authenticator{
+		config:   config,
+		sessions: &sessionSigner{secret: secret, ttl: config.Session.TTL},
+		store:    store,
+	}
+	for _, t := range config.Tokens {
+		digest, _ := hex.DecodeString(t.SHA256)
+		a.tokens = append(a.tokens, token{name: t.Name, digest: digest, permissions: t.Permissions})
+	}
+	if config.Basic != nil {
+		users, err := LoadUsers(config.Basic.UsersFile)
+		if err != nil {
+			return nil, err
+		}
+		a.users = users
+	}
+	if config.OIDC != nil {
+		a.oidc = newOIDCProvider(config.OIDC)
+	}
+	if config.Keystone != nil {
+		a.keystone = newKeystoneProvider(config.Keystone)
+	}
+	return a, nil
+}
+
+// Methods lists enabled authentication methods
+func (a *Authenticator) Methods() []string {
+	var methods []string
+	if len(a.tokens) > 0 {
+		methods = append(methods, MethodToken)
+	}
+	methods = append(methods, MethodAPIToken)
+	if a.users != nil {
+		methods = append(methods, MethodBasic)
+	}
+	if a.oidc != nil {
+		methods = append(methods, MethodOIDC)
+	}
+	if a.keystone != nil {
+		methods = append(methods, MethodKeystone)
+	}
+	return methods
+}
+
+// AuthenticateToken returns the principal of a static or managed API token
+func (a *Authenticator) AuthenticateToken(value string) (*Principal, bool) {
+	digest := sha256.Sum256([]byte(value))
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(digest[:], t.digest) == 1 {
+			return &Principal{Name: t.name, Method: MethodToken, Permissions: t.permissions}, true
+		}
+	}
+	return a.store.authenticate(value, time.Now())
+}
+
+// AuthenticateBasic returns the principal of user name and password
+func (a *Authenticator) AuthenticateBasic(name, password string) (*Principal, bool) {
+	user, ok := a.users[name]
+	if !ok {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, false
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, false
+	}
+	return &Principal{Name: user.Name, Method: MethodBasic, Permissions: user.Permissions}, true
+}
+
+// AuthenticateKeystone signs in with OpenStack credentials, an empty domain
+// selects the configured user domain
+func (a *Authenticator) AuthenticateKeystone(ctx context.Context, domain, name, password string) (*Principal, error) {
+	return a.keystone.password(ctx, domain, name, password)
+}
+
+// AuthenticateKeystoneToken returns the principal of a Keystone token
+func (a *Authenticator) AuthenticateKeystoneToken(ctx context.Context, token string) (*Principal, error) {
+	return a.keystone.token(ctx, token)
+}
+
+// validatePermissions checks permission names, defaulting to read-only access
+func validatePermissions(permissions []Permission) ([]Permission, error) {
+	if len(permissions) == 0 {
+		return []Permission{PermissionRead}, nil
+	}
+	for _, permission := range permissions {
+		switch permission {
+		case PermissionRead, PermissionExport, PermissionRefresh, PermissionAdmin:
+		default:
+			return nil, fmt.Errorf("unknown permission %q, expected read, export, refresh or admin", permission)
+		}
+	}
+	return permissions, nil
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultKeystoneCacheTTL limits how long validated Keystone tokens and
+// their projects are reused without asking Keystone again
+const defaultKeystoneCacheTTL = 5 * time.Minute
+
+// errKeystoneUnauthorized means Keystone rejected the credentials or token
+var errKeystoneUnauthorized = errors.New("keystone rejected the credentials")
+
+// KeystoneConfig enables login with OpenStack credentials or a Keystone token.
+// Such users only see resources of projects they have a role assignment in.
+type KeystoneConfig struct {
+	// AuthURL is the Keystone endpoint, the OpenStack auth URL by default
+	AuthURL string `yaml:"auth_url"`
+	// UserDomainName is used when the login form leaves the domain empty,
+	// the OpenStack user domain or Default by default
+	UserDomainName string `yaml:"user_domain_name"`
+	// Insecure skips TLS verification, as for OpenStack by default
+	Insecure *bool `yaml:"insecure"`
+	// CacheTTL limits reuse of validated X-Auth-Token headers
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// Permissions are granted to every signed in user
+	Permissions []Permission `yaml:"permissions"`
+}
+
+// validate fills empty settings from defaults, the OpenStack connection
+func (c *KeystoneConfig) validate(defaults KeystoneConfig) error {
+	if c.AuthURL == "" {
+		c.AuthURL = defaults.AuthURL
+	}
+	if c.AuthURL == "" {
+		return fmt.Errorf("auth keystone requires auth_url or OS_AUTH_URL")
+	}
+	c.AuthURL = strings.TrimSuffix(c.AuthURL, "/")
+	if !strings.HasSuffix(c.AuthURL, "/v3") {
+		c.AuthURL += "/v3"
+	}
+	if c.UserDomainName == "" {
+		c.UserDomainName = defaults.UserDomainName
+	}
+	if c.UserDomainName == "" {
+		c.UserDomainName = "Default"
+	}
+	if c.Insecure == nil {
+		insecure := defaults.Insecure != nil && *defaults.Insecure
+		c.Insecure = &insecure
+	}
+	if c.CacheTTL == 0 {
+		c.CacheTTL = defaultKeystoneCacheTTL
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("auth keystone cache_ttl must be positive")
+	}
+
+	var err error
+	if c.Permissions, err = validatePermissions(c.Permissions); err != nil {
+		return fmt.Errorf("auth keystone: %w", err)
+	}
+	return nil
+}
+
+// keystoneToken is the part of a Keystone token in use
+type keystoneToken struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	User      struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Domain struct {
+			Name string `json:"name"`
+		} `json:"domain"`
+	} `json:"user"`
+}
+
+// cachedPrincipal is a validated Keystone token
+type cachedPrincipal struct {
+	principal *Principal
+	expires   time.Time
+}
+
+// keystoneProvider signs users in with Keystone and looks up their projects
+type keystoneProvider struct {
+	config *KeystoneConfig
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedPrincipal
+}
+
+func newKeystoneProvider(config *KeystoneConfig) *keystoneProvider {
+	client := &http.Client{Timeout: 15 * time.Second}
+	if *config.Insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return &keystoneProvider{config: config, client: client, cache: make(map[string]cachedPrincipal)}
+}
+
+// password issues an unscoped token for the user, reads the projects and
+// revokes the token again
+func (p *keystoneProvider) password(ctx context.Context, domain, name, password string) (*Principal, error) {
+	if domain == "" {
+		domain = p.config.UserDomainName
+	}
+	var body struct {
+		Auth struct {
+			Identity struct {
+				Methods  []string `json:"methods"`
+				Password struct {
+					User struct {
+						Name     string            `json:"name"`
+						Domain   map[string]string `json:"domain"`
+						Password string            `json:"password"`
+					} `json:"user"`
+				} `json:"password"`
+			} `json:"identity"`
+		} `json:"auth"`
+	}
+	body.Auth.Identity.Methods = []string{"password"}
+	body.Auth.Identity.Password.User.Name = name
+	body.Auth.Identity.Password.User.Domain = map[string]string{"name": domain}
+	body.Auth.Identity.Password.User.Password = password
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.AuthURL+"/auth/tokens", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var issued struct {
+		Token keystoneToken `json:"token"`
+	}
+	header, err := p.doJSON(req, &issued)
+	if err != nil {
+		return nil, fmt.Errorf("keystone password authentication failed: %w", err)
+	}
+	token := header.Get("X-Subject-Token")
+	if token == "" {
+		return nil, fmt.Errorf("keystone response has no X-Subject-Token")
+	}
+	defer p.revoke(token)
+
+	return p.principal(ctx, token, &issued.Token)
+}
+
+// token validates a Keystone token supplied by the user. Results are cached
+// for CacheTTL, but never beyond the token expiry.
+func (p *keystoneProvider) token(ctx context.Context, token string) (*Principal, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	p.mu.Lock()
+	cached, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.principal, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.AuthURL+"/auth/tokens", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	req.Header.Set("X-Subject-Token", token)
+	var validated struct {
+		Token keystoneToken `json:"token"`
+	}
+	if _, err := p.doJSON(req, &validated); err != nil {
+		return nil, fmt.Errorf("keystone token validation failed: %w", err)
+	}
+	principal, err := p.principal(ctx, token, &validated.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(p.config.CacheTTL)
+	if !validated.Token.ExpiresAt.IsZero() && validated.Token.ExpiresAt.Before(expires) {
+		expires = validated.Token.ExpiresAt
+	}
+	p.mu.Lock()
+	now := time.Now()
+	for k, v := range p.cache {
+		if now.After(v.expires) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = cachedPrincipal{principal: principal, expires: expires}
+	p.mu.Unlock()
+	return principal, nil
+}
+
+// principal lists the projects the token owner has a role assignment in
+func (p *keystoneProvider) principal(ctx context.Context, token string, info *keystoneToken) (*Principal, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.AuthURL+"/auth/projects", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Auth-Token", token)
+	var list struct {
+		Projects []struct {
+			ID string `json:"id"`
+		} `json:"projects"`
+	}
+	if _, err := p.doJSON(req, &list); err != nil {
+		return nil, fmt.Errorf("keystone project listing failed: %w", err)
+	}
+
+	name := info.User.Name
+	if name == "" {
+		name = info.User.ID
+	}
+	if info.User.Domain.Name != "" && info.User.Domain.Name != p.config.UserDomainName {
+		name = info.User.Domain.Name + "/" + name
+	}
+	principal := &Principal{
+		Name:        name,
+		Method:      MethodKeystone,
+		Permissions: p.config.Permissions,
+		Projects:    make([]string, 0, len(list.Projects)),
+	}
+	for _, project := range list.Projects {
+		principal.Projects = append(principal.Projects, project.ID)
+	}
+	return principal, nil
+}
+
+// revoke invalidates a token issued for the login, failures only delay its expiry
+func (p *keystoneProvider) revoke(token string) {
+	req, err := http.NewRequest(http.MethodDelete, p.config.AuthURL+"/auth/tokens", nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set("X-Auth-Token", token)
+	req.Header.Set("X-Subject-Token", token)
+	if resp, err := p.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+func (p *keystoneProvider) doJSON(req *http.Request, out interface{}) (http.Header, error) {
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusNotFound {
+		return nil, errKeystoneUnauthorized
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Header, json.Unmarshal(body, out)
+}
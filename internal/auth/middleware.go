@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"openstack-reporter/internal/i18n"
+	"openstack-reporter/internal/logging"
+)
+
+// SessionCookie keeps the signed browser session
+const SessionCookie = "reporter_session"
+
+const (
+	// oidcStateCookie keeps the state of a pending OIDC login
+	oidcStateCookie = "reporter_oidc"
+	oidcStateTTL    = 10 * time.Minute
+	principalKey    = "auth.principal"
+	realm           = "OpenStack Reporter"
+	// maxCookieSize is the size browsers are guaranteed to store
+	maxCookieSize = 4096
+)
+
+// oidcState binds the provider callback to the browser that started the login
+type oidcState struct {
+	State   string `json:"state"`
+	Next    string `json:"next"`
+	Expires int64  `json:"exp"`
+}
+
+// PrincipalFrom returns the authenticated client of a request, nil when
+// authentication is disabled or the request is anonymous
+func PrincipalFrom(c *gin.Context) *Principal {
+	if value, ok := c.Get(principalKey); ok {
+		return value.(*Principal)
+	}
+	return nil
+}
+
+// Middleware identifies the client from a Keystone X-Auth-Token, a bearer
+// token, basic credentials or the session cookie. Access is enforced by Require.
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a == nil {
+			c.Next()
+			return
+		}
+		if principal, ok := a.authenticate(c); ok {
+			c.Set(principalKey, principal)
+		}
+		c.Next()
+	}
+}
+
+func (a *Authenticator) authenticate(c *gin.Context) (*Principal, bool) {
+	if value := c.GetHeader("X-Auth-Token"); value != "" {
+		if a.keystone == nil {
+			return nil, false
+		}
+		principal, err := a.AuthenticateKeystoneToken(c.Request.Context(), value)
+		if err != nil {
+			if !errors.Is(err, errKeystoneUnauthorized) {
+				logging.FromGin(c).Warn("Keystone token check failed", "error", err)
+			}
+			return nil, false
+		}
+		return principal, true
+	}
+	header := c.GetHeader("Authorization")
+	if value, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return a.AuthenticateToken(strings.TrimSpace(value))
+	}
+	if name, password, ok := c.Request.BasicAuth(); ok {
+		if a.users == nil {
+			return nil, false
+		}
+		return a.AuthenticateBasic(name, password)
+	}
+	if cookie, err := c.Cookie(SessionCookie); err == nil {
+		return a.sessions.parseSession(cookie)
+	}
+	return nil, false
+}
+
+// Require rejects requests of anonymous clients and clients lacking the
+// permission. It allows everything when authentication is disabled.
+func (a *Authenticator) Require(permission Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a == nil {
+			c.Next()
+			return
+		}
+
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			a.unauthorized(c)
+			return
+		}
+		if !principal.Can(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": i18n.T(i18n.FromRequest(c.Request), "api.error.forbidden", permission),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// unauthorized answers API requests with 401 and sends browsers to the login page
+func (a *Authenticator) unauthorized(c *gin.Context) {
+	if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		c.Header("WWW-Authenticate", `Bearer realm="`+realm+`"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": i18n.T(i18n.FromRequest(c.Request), "api.error.unauthorized"),
+		})
+		return
+	}
+	c.Redirect(http.StatusFound, "/login?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+	c.Abort()
+}
+
+// RegisterRoutes adds the login page, logout and OIDC callback routes
+func (a *Authenticator) RegisterRoutes(r *gin.Engine) {
+	r.GET("/login", a.loginPage)
+	r.POST("/login", a.login)
+	r.GET("/logout", a.logout)
+	if a.oidc != nil {
+		r.GET("/auth/oidc/login", a.oidcLogin)
+		r.GET("/auth/oidc/callback", a.oidcCallback)
+	}
+}
+
+func (a *Authenticator) loginPage(c *gin.Context) {
+	a.renderLogin(c, http.StatusOK, c.Query("next"), "")
+}
+
+func (a *Authenticator) renderLogin(c *gin.Context, status int, next, errorKey string) {
+	lang := i18n.FromRequest(c.Request)
+	message := ""
+	if errorKey != "" {
+		message = i18n.T(lang, errorKey)
+	}
+	c.HTML(status, "login.html", gin.H{
+		"lang":     lang,
+		"next":     safeNext(next),
+		"password": a.users != nil || a.keystone != nil,
+		"keystone": a.keystone != nil,
+		"domain":   a.keystoneDomain(),
+		"oidc":     a.oidc != nil,
+		"error":    message,
+	})
+}
+
+func (a *Authenticator) keystoneDomain() string {
+	if a.keystone == nil {
+		return ""
+	}
+	return a.keystone.config.UserDomainName
+}
+
+// login signs in with a Keystone token or a user name and password, which
+// are checked against the users file first and Keystone afterwards
+func (a *Authenticator) login(c *gin.Context) {
+	if a.users == nil && a.keystone == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	next := c.PostForm("next")
+
+	if token := c.PostForm("token"); token != "" && a.keystone != nil {
+		principal, err := a.AuthenticateKeystoneToken(c.Request.Context(), token)
+		if err != nil {
+			a.keystoneFailed(c, next, "ui.login.token_failed", err)
+			return
+		}
+		a.startSession(c, principal, next)
+		return
+	}
+
+	name, password, domain := c.PostForm("username"), c.PostForm("password"), c.PostForm("domain")
+	if a.users != nil && domain == "" {
+		if principal, ok := a.AuthenticateBasic(name, password); ok {
+			a.startSession(c, principal, next)
+			return
+		}
+	}
+	if a.keystone != nil && name != "" {
+		principal, err := a.AuthenticateKeystone(c.Request.Context(), domain, name, password)
+		if err != nil {
+			logging.FromGin(c).Info("Login failed", "user", name, "method", MethodKeystone, "client_ip", c.ClientIP())
+			a.keystoneFailed(c, next, "ui.login.failed", err)
+			return
+		}
+		a.startSession(c, principal, next)
+		return
+	}
+
+	logging.FromGin(c).Info("Login failed", "user", name, "client_ip", c.ClientIP())
+	a.renderLogin(c, http.StatusUnauthorized, next, "ui.login.failed")
+}
+
+// keystoneFailed tells rejected credentials apart from an unavailable Keystone
+func (a *Authenticator) keystoneFailed(c *gin.Context, next, rejectedKey string, err error) {
+	if errors.Is(err, errKeystoneUnauthorized) {
+		a.renderLogin(c, http.StatusUnauthorized, next, rejectedKey)
+		return
+	}
+	logging.FromGin(c).Error("Keystone login failed", "error", err)
+	a.renderLogin(c, http.StatusBadGateway, next, "ui.login.keystone_failed")
+}
+
+func (a *Authenticator) logout(c *gin.Context) {
+	a.setCookie(c, SessionCookie, "", -1)
+	c.Redirect(http.StatusFound, "/login")
+}
+
+func (a *Authenticator) oidcLogin(c *gin.Context) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	state := hex.EncodeToString(buf)
+
+	redirect, err := a.oidc.authCodeURL(c.Request.Context(), state)
+	if err != nil {
+		logging.FromGin(c).Error("OIDC login failed", "error", err)
+		a.renderLogin(c, http.StatusBadGateway, "", "ui.login.sso_failed")
+		return
+	}
+	cookie, err := a.sessions.encode(purposeOIDCState, oidcState{
+		State:   state,
+		Next:    safeNext(c.Query("next")),
+		Expires: time.Now().Add(oidcStateTTL).Unix(),
+	})
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	a.setCookie(c, oidcStateCookie, cookie, int(oidcStateTTL.Seconds()))
+	c.Redirect(http.StatusFound, redirect)
+}
+
+func (a *Authenticator) oidcCallback(c *gin.Context) {
+	var pending oidcState
+	cookie, err := c.Cookie(oidcStateCookie)
+	if err == nil {
+		err = a.sessions.decode(cookie, purposeOIDCState, &pending)
+	}
+	a.setCookie(c, oidcStateCookie, "", -1)
+	if err != nil || pending.State == "" || pending.State != c.Query("state") || time.Now().Unix() >= pending.Expires {
+		a.renderLogin(c, http.StatusBadRequest, "", "ui.login.sso_failed")
+		return
+	}
+	if providerError := c.Query("error"); providerError != "" {
+		logging.FromGin(c).Info("OIDC login rejected by provider", "reason", providerError, "description", c.Query("error_description"))
+		a.renderLogin(c, http.StatusUnauthorized, "", "ui.login.sso_failed")
+		return
+	}
+
+	principal, err := a.oidc.exchange(c.Request.Context(), c.Query("code"))
+	if err != nil {
+		logging.FromGin(c).Error("OIDC login failed", "error", err)
+		a.renderLogin(c, http.StatusBadGateway, "", "ui.login.sso_failed")
+		return
+	}
+	a.startSession(c, principal, pending.Next)
+}
+
+// startSession sets the session cookie and continues to the requested page
+func (a *Authenticator) startSession(c *gin.Context, principal *Principal, next string) {
+	cookie, err := a.sessions.newSession(principal)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	if len(cookie) > maxCookieSize {
+		logging.FromGin(c).Warn("Session cookie is too large and may be dropped by the browser",
+			"user", principal.Name, "projects", len(principal.Projects), "max_bytes", maxCookieSize)
+	}
+	logging.FromGin(c).Info("User signed in", "user", principal.Name, "method", principal.Method, "client_ip", c.ClientIP())
+	a.setCookie(c, SessionCookie, cookie, int(a.sessions.ttl.Seconds()))
+	c.Redirect(http.StatusFound, safeNext(next))
+}
+
+func (a *Authenticator) setCookie(c *gin.Context, name, value string, maxAge int) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(name, value, maxAge, "/", "", secure, true)
+}
+
+// safeNext only allows redirects to local paths
+func safeNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return "/"
+	}
+	return next
+}
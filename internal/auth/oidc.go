@@ -0,0 +1,235 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OIDCConfig enables browser login with an OpenID Connect provider
+type OIDCConfig struct {
+	Issuer   string `yaml:"issuer"`
+	ClientID string `yaml:"client_id"`
+	// ClientSecret may be omitted in favour of OIDC_CLIENT_SECRET
+	ClientSecret string `yaml:"client_secret"`
+	// RedirectURL is the public URL of /auth/oidc/callback
+	RedirectURL string   `yaml:"redirect_url"`
+	Scopes      []string `yaml:"scopes"`
+	// UsernameClaim names the user, preferred_username by default
+	UsernameClaim string `yaml:"username_claim"`
+	// Permissions are granted to every signed in user
+	Permissions []Permission `yaml:"permissions"`
+	// Roles grant additional permissions by claim value, e.g. groups
+	Roles []OIDCRole `yaml:"roles"`
+}
+
+// OIDCRole grants permissions to users having a claim value
+type OIDCRole struct {
+	Claim       string       `yaml:"claim"`
+	Value       string       `yaml:"value"`
+	Permissions []Permission `yaml:"permissions"`
+}
+
+func (c *OIDCConfig) validate() error {
+	if secret := os.Getenv("OIDC_CLIENT_SECRET"); secret != "" {
+		c.ClientSecret = secret
+	}
+	if c.Issuer == "" || c.ClientID == "" || c.RedirectURL == "" {
+		return fmt.Errorf("auth oidc requires issuer, client_id and redirect_url")
+	}
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "profile", "email"}
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = "preferred_username"
+	}
+
+	var err error
+	if c.Permissions, err = validatePermissions(c.Permissions); err != nil {
+		return fmt.Errorf("auth oidc: %w", err)
+	}
+	for i, role := range c.Roles {
+		if role.Claim == "" || role.Value == "" {
+			return fmt.Errorf("auth oidc role %d requires claim and value", i+1)
+		}
+		if len(role.Permissions) == 0 {
+			return fmt.Errorf("auth oidc role %d grants no permissions", i+1)
+		}
+		if _, err := validatePermissions(role.Permissions); err != nil {
+			return fmt.Errorf("auth oidc role %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// oidcEndpoints is the part of the provider discovery document in use
+type oidcEndpoints struct {
+	Authorization string `json:"authorization_endpoint"`
+	Token         string `json:"token_endpoint"`
+	UserInfo      string `json:"userinfo_endpoint"`
+}
+
+// oidcProvider runs the authorization code flow. User claims are read from
+// the userinfo endpoint over TLS, so ID token signatures are not verified.
+type oidcProvider struct {
+	config *OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	endpoints *oidcEndpoints
+}
+
+func newOIDCProvider(config *OIDCConfig) *oidcProvider {
+	return &oidcProvider{config: config, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// discover fetches and caches the provider endpoints
+func (p *oidcProvider) discover(ctx context.Context) (*oidcEndpoints, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.endpoints != nil {
+		return p.endpoints, nil
+	}
+
+	wellKnown := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return nil, err
+	}
+	var endpoints oidcEndpoints
+	if err := p.doJSON(req, &endpoints); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if endpoints.Authorization == "" || endpoints.Token == "" || endpoints.UserInfo == "" {
+		return nil, fmt.Errorf("oidc discovery document of %s lacks required endpoints", p.config.Issuer)
+	}
+	p.endpoints = &endpoints
+	return p.endpoints, nil
+}
+
+// authCodeURL returns the provider login URL
+func (p *oidcProvider) authCodeURL(ctx context.Context, state string) (string, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {p.config.ClientID},
+		"redirect_uri":  {p.config.RedirectURL},
+		"scope":         {strings.Join(p.config.Scopes, " ")},
+		"state":         {state},
+	}
+	separator := "?"
+	if strings.Contains(endpoints.Authorization, "?") {
+		separator = "&"
+	}
+	return endpoints.Authorization + separator + query.Encode(), nil
+}
+
+// exchange redeems the authorization code and returns the signed in user
+func (p *oidcProvider) exchange(ctx context.Context, code string) (*Principal, error) {
+	endpoints, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {p.config.RedirectURL},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.Token, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	var tokens struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := p.doJSON(req, &tokens); err != nil {
+		return nil, fmt.Errorf("oidc token request failed: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return nil, fmt.Errorf("oidc token response has no access_token")
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoints.UserInfo, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	var claims map[string]interface{}
+	if err := p.doJSON(req, &claims); err != nil {
+		return nil, fmt.Errorf("oidc userinfo request failed: %w", err)
+	}
+	return p.principal(claims)
+}
+
+// principal maps userinfo claims to a principal with configured permissions
+func (p *oidcProvider) principal(claims map[string]interface{}) (*Principal, error) {
+	name, _ := claims[p.config.UsernameClaim].(string)
+	if name == "" {
+		name, _ = claims["sub"].(string)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("oidc userinfo has neither %s nor sub claim", p.config.UsernameClaim)
+	}
+
+	principal := &Principal{Name: name, Method: MethodOIDC}
+	grant := func(permissions []Permission) {
+		for _, permission := range permissions {
+			if !principal.Can(permission) {
+				principal.Permissions = append(principal.Permissions, permission)
+			}
+		}
+	}
+	grant(p.config.Permissions)
+	for _, role := range p.config.Roles {
+		if claimHasValue(claims[role.Claim], role.Value) {
+			grant(role.Permissions)
+		}
+	}
+	return principal, nil
+}
+
+// claimHasValue matches string and string list claims
+func claimHasValue(claim interface{}, value string) bool {
+	switch v := claim.(type) {
+	case string:
+		return v == value
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *oidcProvider) doJSON(req *http.Request, out interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return json.Unmarshal(body, out)
+}
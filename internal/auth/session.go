@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Purposes of signed values, a value signed for one purpose is rejected for
+// another so the OIDC state cookie cannot be replayed as a session
+const (
+	purposeSession   = "session"
+	purposeOIDCState = "oidc_state"
+)
+
+// signed is the payload of a signed cookie
+type signed struct {
+	Purpose string          `json:"purpose"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// session is the payload of a signed session cookie
+type session struct {
+	Principal
+	Expires int64 `json:"exp"`
+}
+
+// sessionSigner encodes sessions as base64 JSON followed by an HMAC-SHA256 signature
+type sessionSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// encode signs value for purpose
+func (s *sessionSigner) encode(purpose string, value interface{}) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(signed{Purpose: purpose, Value: raw})
+	if err != nil {
+		return "", err
+	}
+	data := base64.RawURLEncoding.EncodeToString(payload)
+	return data + "." + s.sign(data), nil
+}
+
+// decode verifies the signature and purpose and unmarshals the value
+func (s *sessionSigner) decode(cookie, purpose string, value interface{}) error {
+	data, signature, ok := strings.Cut(cookie, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.sign(data))) {
+		return fmt.Errorf("invalid signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(data)
+	if err != nil {
+		return err
+	}
+	var envelope signed
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return err
+	}
+	if envelope.Purpose != purpose {
+		return fmt.Errorf("value signed for %q, not %q", envelope.Purpose, purpose)
+	}
+	return json.Unmarshal(envelope.Value, value)
+}
+
+func (s *sessionSigner) sign(data string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newSession returns the cookie value of a session for the principal
+func (s *sessionSigner) newSession(principal *Principal) (string, error) {
+	return s.encode(purposeSession, session{Principal: *principal, Expires: time.Now().Add(s.ttl).Unix()})
+}
+
+// parseSession returns the principal of a valid, unexpired session cookie
+func (s *sessionSigner) parseSession(cookie string) (*Principal, bool) {
+	var value session
+	if err := s.decode(cookie, purposeSession, &value); err != nil || time.Now().Unix() >= value.Expires {
+		return nil, false
+	}
+	return &value.Principal, true
+}
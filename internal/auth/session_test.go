@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionRoundTrip(t *testing.T) {
+	signer := &sessionSigner{secret: []byte("secret"), ttl: time.Hour}
+	cookie, err := signer.newSession(&Principal{Name: "alice", Method: MethodBasic, Permissions: []Permission{PermissionRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	principal, ok := signer.parseSession(cookie)
+	if !ok || principal.Name != "alice" || !principal.Can(PermissionRead) {
+		t.Fatalf("parseSession = %+v, %v", principal, ok)
+	}
+
+	other := &sessionSigner{secret: []byte("other"), ttl: time.Hour}
+	if _, ok := other.parseSession(cookie); ok {
+		t.Error("session accepted with a different secret")
+	}
+	data, signature, _ := strings.Cut(cookie, ".")
+	if _, ok := signer.parseSession(data + "x." + signature); ok {
+		t.Error("tampered session accepted")
+	}
+
+	expired := &sessionSigner{secret: []byte("secret"), ttl: -time.Minute}
+	cookie, _ = expired.newSession(&Principal{Name: "alice"})
+	if _, ok := signer.parseSession(cookie); ok {
+		t.Error("expired session accepted")
+	}
+}
+
+func TestSignedPurpose(t *testing.T) {
+	signer := &sessionSigner{secret: []byte("secret"), ttl: time.Hour}
+
+	// A state cookie carries fields a session would accept, it must still be rejected
+	state, err := signer.encode(purposeOIDCState, map[string]interface{}{
+		"state":       "abc",
+		"name":        "mallory",
+		"permissions": []Permission{PermissionAdmin},
+		"exp":         time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if principal, ok := signer.parseSession(state); ok {
+		t.Fatalf("OIDC state accepted as session of %q", principal.Name)
+	}
+
+	session, _ := signer.newSession(&Principal{Name: "alice"})
+	var pending oidcState
+	if err := signer.decode(session, purposeOIDCState, &pending); err == nil {
+		t.Error("session accepted as OIDC state")
+	}
+	if err := signer.decode(state, purposeOIDCState, &pending); err != nil || pending.State != "abc" {
+		t.Errorf("decode state = %+v, %v", pending, err)
+	}
+}
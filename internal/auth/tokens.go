@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"openstack-reporter/internal/i18n"
+	"openstack-reporter/internal/storage"
+)
+
+const (
+	// apiTokensFile keeps managed API tokens in the data directory
+	apiTokensFile = "api_tokens.json"
+	// apiTokensMode keeps token digests readable by the service user only
+	apiTokensMode = 0600
+	// apiTokenPrefix marks secrets of managed tokens, e.g. for secret scanners
+	apiTokenPrefix = "osr_"
+)
+
+// ErrTokenNotFound is returned when revoking an unknown token
+var ErrTokenNotFound = errors.New("token not found")
+
+// APIToken is a managed API token scoped to operations and projects.
+// Only the SHA-256 digest of the secret is stored.
+type APIToken struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	SHA256      string       `json:"sha256,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	// Projects are project IDs the token may see, empty means all projects
+	Projects  []string   `json:"projects,omitempty"`
+	CreatedBy string     `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the token is past its expiry
+func (t *APIToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// TokenRequest describes a token to create
+type TokenRequest struct {
+	Name        string       `json:"name"`
+	Permissions []Permission `json:"permissions"`
+	Projects    []string     `json:"projects"`
+	ExpiresAt   *time.Time   `json:"expires_at"`
+}
+
+// Validate checks the request and normalizes name, permissions and projects
+func (r *TokenRequest) Validate(now time.Time) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	permissions, err := validatePermissions(r.Permissions)
+	if err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if permission == PermissionAdmin {
+			return fmt.Errorf("managed tokens cannot be granted %s", PermissionAdmin)
+		}
+	}
+	r.Permissions = permissions
+
+	var projects []string
+	for _, project := range r.Projects {
+		if project = strings.TrimSpace(project); project != "" {
+			projects = append(projects, project)
+		}
+	}
+	r.Projects = projects
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(now) {
+		return fmt.Errorf("expires_at must be in the future")
+	}
+	return nil
+}
+
+// TokenStore keeps managed API tokens in the data directory
+type TokenStore struct {
+	storage *storage.Storage
+
+	mu     sync.RWMutex
+	tokens []APIToken
+	digest map[string]int
+}
+
+// NewTokenStore loads managed tokens, a missing file means no tokens
+func NewTokenStore(storage *storage.Storage) (*TokenStore, error) {
+	s := &TokenStore{storage: storage}
+	var tokens []APIToken
+	if err := storage.LoadJSON(apiTokensFile, &tokens); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	s.index(tokens)
+	return s, nil
+}
+
+func (s *TokenStore) index(tokens []APIToken) {
+	s.tokens = tokens
+	s.digest = make(map[string]int, len(tokens))
+	for i, t := range tokens {
+		s.digest[t.SHA256] = i
+	}
+}
+
+// Create stores a new token and returns it with its secret, which is not
+// kept and cannot be shown again
+func (s *TokenStore) Create(request TokenRequest, createdBy string, now time.Time) (*APIToken, string, error) {
+	if err := request.Validate(now); err != nil {
+		return nil, "", err
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, "", err
+	}
+	secret = apiTokenPrefix + secret
+	sum := sha256.Sum256([]byte(secret))
+
+	token := APIToken{
+		ID:          id,
+		Name:        request.Name,
+		SHA256:      hex.EncodeToString(sum[:]),
+		Permissions: request.Permissions,
+		Projects:    request.Projects,
+		CreatedBy:   createdBy,
+		CreatedAt:   now.UTC(),
+		ExpiresAt:   request.ExpiresAt,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens := append(append([]APIToken{}, s.tokens...), token)
+	if err := s.storage.SaveJSON(apiTokensFile, tokens, apiTokensMode); err != nil {
+		return nil, "", err
+	}
+	s.index(tokens)
+	token.SHA256 = ""
+	return &token, secret, nil
+}
+
+// List returns all tokens without their digests, newest first
+func (s *TokenStore) List() []APIToken {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tokens := make([]APIToken, len(s.tokens))
+	for i, t := range s.tokens {
+		t.SHA256 = ""
+		tokens[i] = t
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+	return tokens
+}
+
+// Revoke deletes a token, it stops working immediately
+func (s *TokenStore) Revoke(id string) (*APIToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, t := range s.tokens {
+		if t.ID != id {
+			continue
+		}
+		tokens := append(append([]APIToken{}, s.tokens[:i]...), s.tokens[i+1:]...)
+		if err := s.storage.SaveJSON(apiTokensFile, tokens, apiTokensMode); err != nil {
+			return nil, err
+		}
+		s.index(tokens)
+		t.SHA256 = ""
+		return &t, nil
+	}
+	return nil, ErrTokenNotFound
+}
+
+// authenticate returns the principal of an unexpired managed token
+func (s *TokenStore) authenticate(secret string, now time.Time) (*Principal, bool) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(secret))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.digest[hex.EncodeToString(sum[:])]
+	if !ok || s.tokens[i].Expired(now) {
+		return nil, false
+	}
+	t := s.tokens[i]
+	principal := &Principal{Name: t.Name, Method: MethodAPIToken, Permissions: t.Permissions}
+	if len(t.Projects) > 0 {
+		principal.Projects = append([]string{}, t.Projects...)
+	}
+	return principal, true
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// ListTokens returns managed API tokens
+func (a *Authenticator) ListTokens(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"tokens": a.store.List()})
+}
+
+// CreateToken creates a managed API token and returns its secret once
+func (a *Authenticator) CreateToken(c *gin.Context) {
+	lang := i18n.FromRequest(c.Request)
+	now := time.Now()
+	var request TokenRequest
+	err := c.ShouldBindJSON(&request)
+	if err == nil {
+		err = request.Validate(now)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   i18n.T(lang, "api.error.invalid_token"),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	createdBy := ""
+	if principal := PrincipalFrom(c); principal != nil {
+		createdBy = principal.Name
+	}
+	token, secret, err := a.store.Create(request, createdBy, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   i18n.T(lang, "api.error.token_failed"),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": i18n.T(lang, "api.message.token_created"),
+		"token":   token,
+		"secret":  secret,
+	})
+}
+
+// RevokeToken deletes a managed API token
+func (a *Authenticator) RevokeToken(c *gin.Context) {
+	lang := i18n.FromRequest(c.Request)
+	token, err := a.store.Revoke(c.Param("id"))
+	if errors.Is(err, ErrTokenNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": i18n.T(lang, "api.error.token_not_found")})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   i18n.T(lang, "api.error.token_failed"),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": i18n.T(lang, "api.message.token_revoked"),
+		"token":   token,
+	})
+}
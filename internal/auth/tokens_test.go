@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"openstack-reporter/internal/storage"
+)
+
+func TestTokenStoreFileMode(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, apiTokensFile)
+	// A file left readable by an older version is tightened on the next write
+	if err := os.WriteFile(path, []byte("[]"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewTokenStore(storage.NewStorage(storage.Config{DataDir: dir}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if _, _, err := store.Create(TokenRequest{Name: "ci", Permissions: []Permission{PermissionRead}}, "admin", now); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("mode = %o, want 600", mode)
+	}
+}
@@ -0,0 +1,218 @@
+package compliance
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openstack-reporter/internal/models"
+)
+
+// DefaultPolicyFile is used when the configuration sets no file
+const DefaultPolicyFile = "compliance-policy.yaml"
+
+// Violation kinds
+const (
+	MissingKey   = "missing_key"
+	InvalidValue = "invalid_value"
+)
+
+// Rule lists required metadata keys and allowed values for resource types
+type Rule struct {
+	// Types the rule applies to, empty or "*" means every resource type
+	Types    []string            `yaml:"types" json:"types,omitempty"`
+	Required []string            `yaml:"required" json:"required,omitempty"`
+	Allowed  map[string][]string `yaml:"allowed" json:"allowed,omitempty"`
+}
+
+// Policy is the tagging standard resources are evaluated against
+type Policy struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Violation is a single policy breach of a resource
+type Violation struct {
+	ResourceID   string   `json:"resource_id"`
+	ResourceName string   `json:"resource_name"`
+	ResourceType string   `json:"resource_type"`
+	Kind         string   `json:"kind"`
+	Key          string   `json:"key"`
+	Value        string   `json:"value,omitempty"`
+	Allowed      []string `json:"allowed,omitempty"`
+}
+
+// ProjectCompliance summarizes compliance of resources in a project
+type ProjectCompliance struct {
+	ProjectID    string      `json:"project_id"`
+	ProjectName  string      `json:"project_name"`
+	Evaluated    int         `json:"evaluated"`
+	Compliant    int         `json:"compliant"`
+	NonCompliant int         `json:"non_compliant"`
+	Violations   []Violation `json:"violations"`
+}
+
+// Report is the compliance view over a resource report
+type Report struct {
+	GeneratedAt     time.Time           `json:"generated_at"`
+	Evaluated       int                 `json:"evaluated"`
+	Compliant       int                 `json:"compliant"`
+	NonCompliant    int                 `json:"non_compliant"`
+	TotalViolations int                 `json:"total_violations"`
+	Projects        []ProjectCompliance `json:"projects"`
+}
+
+// LoadPolicy reads and validates a YAML policy file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compliance policy: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse compliance policy: %w", err)
+	}
+
+	for i, rule := range policy.Rules {
+		if len(rule.Required) == 0 && len(rule.Allowed) == 0 {
+			return nil, fmt.Errorf("compliance policy rule %d has neither required keys nor allowed values", i+1)
+		}
+	}
+
+	return &policy, nil
+}
+
+// appliesTo reports whether the rule covers the resource type
+func (r Rule) appliesTo(resourceType string) bool {
+	if len(r.Types) == 0 {
+		return true
+	}
+	for _, t := range r.Types {
+		if t == "*" || t == resourceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns violations of a single resource. Allowed values are only
+// checked for keys that are present, missing keys are reported by Required.
+func (p *Policy) Check(resource models.Resource) []Violation {
+	var violations []Violation
+	seen := make(map[string]bool)
+
+	newViolation := func(kind, key, value string, allowed []string) Violation {
+		return Violation{
+			ResourceID:   resource.ID,
+			ResourceName: resource.Name,
+			ResourceType: resource.Type,
+			Kind:         kind,
+			Key:          key,
+			Value:        value,
+			Allowed:      allowed,
+		}
+	}
+
+	for _, rule := range p.Rules {
+		if !rule.appliesTo(resource.Type) {
+			continue
+		}
+
+		for _, key := range rule.Required {
+			if _, exists := resource.Metadata[key]; exists || seen[MissingKey+key] {
+				continue
+			}
+			seen[MissingKey+key] = true
+			violations = append(violations, newViolation(MissingKey, key, "", nil))
+		}
+
+		keys := make([]string, 0, len(rule.Allowed))
+		for key := range rule.Allowed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			value, exists := resource.Metadata[key]
+			if !exists || seen[InvalidValue+key] || isAllowed(value, rule.Allowed[key]) {
+				continue
+			}
+			seen[InvalidValue+key] = true
+			violations = append(violations, newViolation(InvalidValue, key, value, rule.Allowed[key]))
+		}
+	}
+
+	return violations
+}
+
+func isAllowed(value string, allowed []string) bool {
+	for _, candidate := range allowed {
+		if strings.EqualFold(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate checks every resource of the report against the policy.
+// Resources not covered by any rule are not counted as evaluated.
+func Evaluate(report *models.ResourceReport, policy *Policy) *Report {
+	result := &Report{
+		GeneratedAt: report.GeneratedAt,
+		Projects:    []ProjectCompliance{},
+	}
+
+	projects := make(map[string]*ProjectCompliance)
+	for _, resource := range report.Resources {
+		covered := false
+		for _, rule := range policy.Rules {
+			if rule.appliesTo(resource.Type) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			continue
+		}
+
+		project, exists := projects[resource.ProjectID]
+		if !exists {
+			project = &ProjectCompliance{
+				ProjectID:   resource.ProjectID,
+				ProjectName: resource.ProjectName,
+				Violations:  []Violation{},
+			}
+			projects[resource.ProjectID] = project
+		}
+
+		violations := policy.Check(resource)
+		project.Evaluated++
+		result.Evaluated++
+		if len(violations) == 0 {
+			project.Compliant++
+			result.Compliant++
+			continue
+		}
+		project.NonCompliant++
+		result.NonCompliant++
+		result.TotalViolations += len(violations)
+		project.Violations = append(project.Violations, violations...)
+	}
+
+	for _, project := range projects {
+		result.Projects = append(result.Projects, *project)
+	}
+	// Projects with the most non-compliant resources first
+	sort.Slice(result.Projects, func(i, j int) bool {
+		if result.Projects[i].NonCompliant != result.Projects[j].NonCompliant {
+			return result.Projects[i].NonCompliant > result.Projects[j].NonCompliant
+		}
+		return result.Projects[i].ProjectName < result.Projects[j].ProjectName
+	})
+
+	return result
+}
@@ -0,0 +1,422 @@
+// Package config loads the application configuration from a YAML file,
+// environment variables and command line flags, in increasing precedence,
+// validates it and describes the effective settings with secrets redacted.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openstack-reporter/internal/audit"
+	"openstack-reporter/internal/auth"
+	"openstack-reporter/internal/compliance"
+	"openstack-reporter/internal/health"
+	"openstack-reporter/internal/i18n"
+	"openstack-reporter/internal/logging"
+	"openstack-reporter/internal/mailer"
+	"openstack-reporter/internal/notify"
+	"openstack-reporter/internal/openstack"
+	"openstack-reporter/internal/rules"
+	"openstack-reporter/internal/storage"
+)
+
+// DefaultFile is read when neither --config nor CONFIG_FILE is set, it may be missing
+const DefaultFile = "config.yaml"
+
+// Sources of a setting
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Config is the application configuration
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	OpenStack  openstack.Config `yaml:"openstack"`
+	Storage    storage.Config   `yaml:"storage"`
+	Collection CollectionConfig `yaml:"collection"`
+	Health     health.Config    `yaml:"health"`
+	Logging    LoggingConfig    `yaml:"logging"`
+	// Language is used when a request selects none
+	Language string       `yaml:"default_language"`
+	Audit    audit.Config `yaml:"audit"`
+	Auth     AuthConfig   `yaml:"auth"`
+	Files    FilesConfig  `yaml:"files"`
+
+	// file is the YAML file the configuration was read from, empty if none
+	file    string
+	sources map[string]string
+}
+
+// ServerConfig controls the HTTP server
+type ServerConfig struct {
+	Port string `yaml:"port"`
+	// TrustedProxies are addresses or CIDR ranges allowed to set X-Forwarded-For
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// ShutdownTimeout bounds draining connections and waiting for refreshes
+	// on SIGTERM, refreshes still running are aborted afterwards
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// CollectionConfig controls scheduled collection
+type CollectionConfig struct {
+	// Interval refreshes the report in background, zero disables it
+	Interval time.Duration `yaml:"interval"`
+	// Timeout aborts a refresh that takes longer, zero means no limit
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// AuthConfig controls authentication of the web UI and API
+type AuthConfig struct {
+	// Disabled serves every route without authentication. Otherwise the
+	// server refuses to start without the auth config file.
+	Disabled bool `yaml:"disabled"`
+}
+
+// LoggingConfig selects log level and format
+type LoggingConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// FilesConfig locates the optional feature configuration files
+type FilesConfig struct {
+	Auth             string `yaml:"auth"`
+	Notifications    string `yaml:"notifications"`
+	Mailer           string `yaml:"mailer"`
+	Rules            string `yaml:"rules"`
+	CompliancePolicy string `yaml:"compliance_policy"`
+}
+
+// Setting is an effective setting, secrets are redacted
+type Setting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Env    string `json:"env,omitempty"`
+	Flag   string `json:"flag,omitempty"`
+}
+
+// Default returns the configuration used when nothing is set
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port: "8080",
+			TrustedProxies: []string{
+				"127.0.0.1",
+				"::1",
+				"10.0.0.0/8",
+				"172.16.0.0/12",
+				"192.168.0.0/16",
+			},
+			ShutdownTimeout: 25 * time.Second,
+		},
+		OpenStack: openstack.Config{
+			InitProject:    openstack.DefaultInitProject,
+			RequestTimeout: openstack.DefaultRequestTimeout,
+		},
+		Storage: storage.Config{
+			DataDir:         storage.DefaultDataDir,
+			BackupRetention: storage.DefaultBackupRetention,
+		},
+		Collection: CollectionConfig{Timeout: 30 * time.Minute},
+		Health:     health.Config{Timeout: health.DefaultTimeout},
+		Logging:    LoggingConfig{Level: "info", Format: "text"},
+		Language:   i18n.DefaultLanguage,
+		Audit: audit.Config{
+			MaxSizeMB: audit.DefaultMaxSizeMB,
+			MaxFiles:  audit.DefaultMaxFiles,
+		},
+		Files: FilesConfig{
+			Auth:             auth.DefaultConfigFile,
+			Notifications:    notify.DefaultConfigFile,
+			Mailer:           mailer.DefaultConfigFile,
+			Rules:            rules.DefaultRulesFile,
+			CompliancePolicy: compliance.DefaultPolicyFile,
+		},
+	}
+}
+
+// setting binds a field to its YAML name, environment variables and flag
+type setting struct {
+	name string
+	// env lists variables in order of preference
+	env    []string
+	flag   string
+	usage  string
+	secret bool
+	value  value
+}
+
+// value is a configuration field that can be set from text
+type value interface {
+	Set(text string) error
+	String() string
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{name: "server.port", env: []string{"PORT"}, flag: "port", usage: "HTTP port", value: (*stringValue)(&c.Server.Port)},
+		{name: "server.trusted_proxies", env: []string{"TRUSTED_PROXIES"}, usage: "comma separated proxy addresses or CIDR ranges", value: (*listValue)(&c.Server.TrustedProxies)},
+		{name: "server.shutdown_timeout", env: []string{"SHUTDOWN_TIMEOUT"}, flag: "shutdown-timeout", usage: "wait for connections and refreshes on SIGTERM before aborting them", value: (*durationValue)(&c.Server.ShutdownTimeout)},
+		{name: "openstack.auth_url", env: []string{"OS_AUTH_URL"}, value: (*stringValue)(&c.OpenStack.AuthURL)},
+		{name: "openstack.username", env: []string{"OS_USERNAME"}, value: (*stringValue)(&c.OpenStack.Username)},
+		{name: "openstack.password", env: []string{"OS_PASSWORD"}, secret: true, value: (*stringValue)(&c.OpenStack.Password)},
+		{name: "openstack.user_domain_name", env: []string{"OS_USER_DOMAIN_NAME", "OS_DOMAIN_NAME"}, value: (*stringValue)(&c.OpenStack.UserDomainName)},
+		{name: "openstack.project_name", env: []string{"OS_PROJECT_NAME"}, flag: "project", usage: "collect a single project, all accessible projects if empty", value: (*stringValue)(&c.OpenStack.ProjectName)},
+		{name: "openstack.project_id", env: []string{"OS_PROJECT_ID"}, value: (*stringValue)(&c.OpenStack.ProjectID)},
+		{name: "openstack.init_project", env: []string{"OS_INIT_PROJECT"}, value: (*stringValue)(&c.OpenStack.InitProject)},
+		{name: "openstack.region_name", env: []string{"OS_REGION_NAME"}, value: (*stringValue)(&c.OpenStack.RegionName)},
+		{name: "openstack.insecure", env: []string{"OS_INSECURE"}, value: (*boolValue)(&c.OpenStack.Insecure)},
+		{name: "openstack.request_timeout", env: []string{"OPENSTACK_REQUEST_TIMEOUT"}, flag: "request-timeout", usage: "bound of a single OpenStack API call, 0 means no limit", value: (*durationValue)(&c.OpenStack.RequestTimeout)},
+		{name: "storage.data_dir", env: []string{"DATA_DIR"}, flag: "data-dir", usage: "directory of reports and backups", value: (*stringValue)(&c.Storage.DataDir)},
+		{name: "storage.backup_retention", env: []string{"BACKUP_RETENTION"}, flag: "backup-retention", usage: "remove report backups older than this, 0 keeps them", value: (*durationValue)(&c.Storage.BackupRetention)},
+		{name: "storage.max_backups", env: []string{"MAX_BACKUPS"}, flag: "max-backups", usage: "number of report backups to keep, 0 means no limit", value: (*intValue)(&c.Storage.MaxBackups)},
+		{name: "collection.interval", env: []string{"COLLECTION_INTERVAL"}, flag: "collection-interval", usage: "refresh the report in background, minutes or a duration, 0 disables", value: (*durationValue)(&c.Collection.Interval)},
+		{name: "collection.timeout", env: []string{"REFRESH_TIMEOUT"}, flag: "refresh-timeout", usage: "abort a refresh that takes longer, 0 means no limit", value: (*durationValue)(&c.Collection.Timeout)},
+		{name: "health.report_max_age", env: []string{"HEALTH_REPORT_MAX_AGE"}, flag: "report-max-age", usage: "readiness fails when the report is older, minutes or a duration, 0 disables", value: (*durationValue)(&c.Health.ReportMaxAge)},
+		{name: "health.keystone", env: []string{"HEALTH_CHECK_KEYSTONE"}, flag: "check-keystone", usage: "readiness requires Keystone to answer", value: (*boolValue)(&c.Health.Keystone)},
+		{name: "health.timeout", env: []string{"HEALTH_TIMEOUT"}, value: (*durationValue)(&c.Health.Timeout)},
+		{name: "logging.level", env: []string{"LOG_LEVEL"}, flag: "log-level", usage: "debug, info, warn or error", value: (*stringValue)(&c.Logging.Level)},
+		{name: "logging.format", env: []string{"LOG_FORMAT"}, flag: "log-format", usage: "text or json", value: (*stringValue)(&c.Logging.Format)},
+		{name: "default_language", env: []string{"DEFAULT_LANGUAGE"}, flag: "language", usage: "language when a request selects none", value: (*stringValue)(&c.Language)},
+		{name: "audit.file", env: []string{"AUDIT_LOG_FILE"}, flag: "audit-log", usage: "audit log file, audit.jsonl in the data directory if empty", value: (*stringValue)(&c.Audit.File)},
+		{name: "audit.max_size_mb", env: []string{"AUDIT_MAX_SIZE_MB"}, value: (*intValue)(&c.Audit.MaxSizeMB)},
+		{name: "audit.max_files", env: []string{"AUDIT_MAX_FILES"}, value: (*intValue)(&c.Audit.MaxFiles)},
+		{name: "auth.disabled", env: []string{"AUTH_DISABLED"}, flag: "auth-disabled", usage: "serve every route without authentication instead of requiring the auth config", value: (*boolValue)(&c.Auth.Disabled)},
+		{name: "files.auth", env: []string{"AUTH_CONFIG_FILE"}, value: (*stringValue)(&c.Files.Auth)},
+		{name: "files.notifications", env: []string{"NOTIFY_CONFIG_FILE"}, value: (*stringValue)(&c.Files.Notifications)},
+		{name: "files.mailer", env: []string{"MAILER_CONFIG_FILE"}, value: (*stringValue)(&c.Files.Mailer)},
+		{name: "files.rules", env: []string{"RULES_FILE"}, value: (*stringValue)(&c.Files.Rules)},
+		{name: "files.compliance_policy", env: []string{"COMPLIANCE_POLICY_FILE"}, value: (*stringValue)(&c.Files.CompliancePolicy)},
+	}
+}
+
+// Load reads the configuration file selected with --config or CONFIG_FILE,
+// applies environment variables and the flags in args, and validates the result.
+// The configuration flags are added to flags, which may already define flags
+// of a subcommand; positional arguments are left in flags.Args(). flag.ErrHelp
+// is returned when args ask for usage.
+func Load(flags *flag.FlagSet, args []string) (*Config, error) {
+	c := Default()
+	settings := c.settings()
+
+	configFile := flags.String("config", "", "YAML configuration file, "+DefaultFile+" if it exists (CONFIG_FILE)")
+	flagged := make(map[string]string)
+	for _, s := range settings {
+		if s.flag == "" {
+			continue
+		}
+		s := s
+		usage := s.usage
+		if len(s.env) > 0 {
+			usage += " (" + s.env[0] + ")"
+		}
+		set := func(text string) error {
+			flagged[s.name] = text
+			return nil
+		}
+		// Boolean flags may be given without a value
+		if _, ok := s.value.(*boolValue); ok {
+			flags.BoolFunc(s.flag, usage, set)
+		} else {
+			flags.Func(s.flag, usage, set)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	c.sources = make(map[string]string, len(settings))
+	before := make(map[string]string, len(settings))
+	for _, s := range settings {
+		c.sources[s.name] = SourceDefault
+		before[s.name] = s.value.String()
+	}
+
+	// A missing file is only an error if it was selected explicitly
+	path, explicit := *configFile, *configFile != ""
+	if !explicit {
+		path = strings.TrimSpace(os.Getenv("CONFIG_FILE"))
+		explicit = path != ""
+	}
+	if path == "" {
+		path = DefaultFile
+	}
+	if err := c.readFile(path); err == nil {
+		c.file = path
+		for _, s := range settings {
+			if s.value.String() != before[s.name] {
+				c.sources[s.name] = SourceFile
+			}
+		}
+	} else if explicit || !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var problems []error
+	for _, s := range settings {
+		for _, env := range s.env {
+			text, ok := os.LookupEnv(env)
+			if !ok || strings.TrimSpace(text) == "" {
+				continue
+			}
+			if err := s.value.Set(text); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", env, err))
+			}
+			c.sources[s.name] = SourceEnv
+			break
+		}
+		if text, ok := flagged[s.name]; ok {
+			if err := s.value.Set(text); err != nil {
+				problems = append(problems, fmt.Errorf("--%s: %w", s.flag, err))
+			}
+			c.sources[s.name] = SourceFlag
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+
+	if c.Audit.File == "" {
+		c.Audit.File = filepath.Join(c.Storage.DataDir, "audit.jsonl")
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks every setting and reports all problems at once
+func (c *Config) Validate() error {
+	var problems []error
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		add("server.port must be a number between 1 and 65535")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				add("server.trusted_proxies: %q is not an address or CIDR range", proxy)
+			}
+		}
+	}
+	if c.Server.ShutdownTimeout < 0 {
+		add("server.shutdown_timeout must not be negative")
+	}
+	if c.OpenStack.AuthURL != "" {
+		if u, err := url.Parse(c.OpenStack.AuthURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("openstack.auth_url must be an http or https URL")
+		}
+	}
+	if c.Storage.DataDir == "" {
+		add("storage.data_dir is required")
+	}
+	if c.Storage.BackupRetention < 0 {
+		add("storage.backup_retention must not be negative")
+	}
+	if c.Storage.MaxBackups < 0 {
+		add("storage.max_backups must not be negative")
+	}
+	if c.OpenStack.RequestTimeout < 0 {
+		add("openstack.request_timeout must not be negative")
+	}
+	if c.Collection.Timeout < 0 {
+		add("collection.timeout must not be negative")
+	}
+	if c.Collection.Interval < 0 || (c.Collection.Interval > 0 && c.Collection.Interval < time.Minute) {
+		add("collection.interval must be 0 or at least one minute")
+	}
+	if c.Health.ReportMaxAge < 0 {
+		add("health.report_max_age must not be negative")
+	}
+	if c.Health.Timeout <= 0 {
+		add("health.timeout must be positive")
+	}
+	if c.Health.Keystone && c.OpenStack.AuthURL == "" {
+		add("health.keystone requires openstack.auth_url")
+	}
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		add("logging.level: %v", err)
+	}
+	if _, err := logging.New(io.Discard, 0, c.Logging.Format); err != nil {
+		add("logging.format: %v", err)
+	}
+	if i18n.Match(c.Language) == "" {
+		add("default_language must be one of %s", strings.Join(i18n.Languages(), ", "))
+	}
+	if c.Audit.MaxSizeMB < 1 || c.Audit.MaxFiles < 1 {
+		add("audit.max_size_mb and audit.max_files must be positive")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+	return nil
+}
+
+// File returns the YAML file the configuration was read from, empty if none
+func (c *Config) File() string {
+	return c.file
+}
+
+// Settings returns the effective settings with their source, secrets redacted
+func (c *Config) Settings() []Setting {
+	var result []Setting
+	for _, s := range c.settings() {
+		text := s.value.String()
+		if s.secret && text != "" {
+			text = logging.Redacted
+		}
+		source := c.sources[s.name]
+		if source == "" {
+			source = SourceDefault
+		}
+		setting := Setting{Name: s.name, Value: text, Source: source, Flag: s.flag}
+		if len(s.env) > 0 {
+			setting.Env = s.env[0]
+		}
+		result = append(result, setting)
+	}
+	return result
+}
+
+// LogAttrs returns the effective settings as log attributes, secrets redacted
+func (c *Config) LogAttrs() []any {
+	attrs := []any{"file", c.file}
+	for _, s := range c.Settings() {
+		attrs = append(attrs, s.Name, s.Value)
+	}
+	return attrs
+}
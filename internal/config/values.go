@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type stringValue string
+
+func (v *stringValue) Set(text string) error {
+	*v = stringValue(strings.TrimSpace(text))
+	return nil
+}
+
+func (v *stringValue) String() string {
+	return string(*v)
+}
+
+type boolValue bool
+
+func (v *boolValue) Set(text string) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(text))
+	if err != nil {
+		return fmt.Errorf("%q is not a boolean", text)
+	}
+	*v = boolValue(b)
+	return nil
+}
+
+func (v *boolValue) String() string {
+	return strconv.FormatBool(bool(*v))
+}
+
+type intValue int
+
+func (v *intValue) Set(text string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return fmt.Errorf("%q is not a number", text)
+	}
+	*v = intValue(n)
+	return nil
+}
+
+func (v *intValue) String() string {
+	return strconv.Itoa(int(*v))
+}
+
+// durationValue accepts Go durations like 36h, a plain number counts
+// minutes as in the Helm chart values
+type durationValue time.Duration
+
+func (v *durationValue) Set(text string) error {
+	text = strings.TrimSpace(text)
+	if minutes, err := strconv.Atoi(text); err == nil {
+		*v = durationValue(time.Duration(minutes) * time.Minute)
+		return nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("%q is not a duration", text)
+	}
+	*v = durationValue(d)
+	return nil
+}
+
+func (v *durationValue) String() string {
+	return time.Duration(*v).String()
+}
+
+// listValue is a comma separated list
+type listValue []string
+
+func (v *listValue) Set(text string) error {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*v = items
+	return nil
+}
+
+func (v *listValue) String() string {
+	return strings.Join(*v, ",")
+}
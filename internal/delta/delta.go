@@ -0,0 +1,142 @@
+package delta
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"openstack-reporter/internal/models"
+	"openstack-reporter/internal/rules"
+)
+
+// StatusChange is a resource present in both reports with a different status
+type StatusChange struct {
+	Resource models.Resource `json:"resource"`
+	From     string          `json:"from"`
+	To       string          `json:"to"`
+}
+
+// IsError reports whether the resource transitioned into an error state
+func (s StatusChange) IsError() bool {
+	return isErrorStatus(s.To) && !isErrorStatus(s.From)
+}
+
+// Delta describes changes between two resource reports
+type Delta struct {
+	From          time.Time         `json:"from"`
+	To            time.Time         `json:"to"`
+	Added         []models.Resource `json:"added"`
+	Removed       []models.Resource `json:"removed"`
+	StatusChanges []StatusChange    `json:"status_changes"`
+	NewFindings   []rules.Finding   `json:"new_findings,omitempty"`
+}
+
+// IsEmpty reports whether nothing changed
+func (d *Delta) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.StatusChanges) == 0 && len(d.NewFindings) == 0
+}
+
+// ErrorTransitions returns status changes into an error state
+func (d *Delta) ErrorTransitions() []StatusChange {
+	var changes []StatusChange
+	for _, change := range d.StatusChanges {
+		if change.IsError() {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func isErrorStatus(status string) bool {
+	return strings.Contains(strings.ToUpper(status), "ERROR")
+}
+
+// resourceKey identifies a resource across reports, IDs are only unique per type
+func resourceKey(resource models.Resource) string {
+	return resource.Type + "/" + resource.ID
+}
+
+// Compute returns resources added, removed and changed status between
+// reports. Resources of a project or type that failed to collect in either
+// report are neither added nor removed, they only went missing.
+func Compute(previous, current *models.ResourceReport) *Delta {
+	d := &Delta{
+		From:          previous.GeneratedAt,
+		To:            current.GeneratedAt,
+		Added:         []models.Resource{},
+		Removed:       []models.Resource{},
+		StatusChanges: []StatusChange{},
+	}
+
+	before := make(map[string]models.Resource, len(previous.Resources))
+	for _, resource := range previous.Resources {
+		before[resourceKey(resource)] = resource
+	}
+
+	seen := make(map[string]bool, len(current.Resources))
+	for _, resource := range current.Resources {
+		key := resourceKey(resource)
+		seen[key] = true
+
+		old, exists := before[key]
+		if !exists {
+			if previous.Collected(resource) {
+				d.Added = append(d.Added, resource)
+			}
+			continue
+		}
+		if old.Status != resource.Status {
+			d.StatusChanges = append(d.StatusChanges, StatusChange{
+				Resource: resource,
+				From:     old.Status,
+				To:       resource.Status,
+			})
+		}
+	}
+
+	for _, resource := range previous.Resources {
+		if !seen[resourceKey(resource)] && current.Collected(resource) {
+			d.Removed = append(d.Removed, resource)
+		}
+	}
+
+	sortResources(d.Added)
+	sortResources(d.Removed)
+	sort.SliceStable(d.StatusChanges, func(i, j int) bool {
+		return lessResource(d.StatusChanges[i].Resource, d.StatusChanges[j].Resource)
+	})
+
+	return d
+}
+
+// NewFindings returns findings that were not reported for the same rule and resource before
+func NewFindings(previous, current []rules.Finding) []rules.Finding {
+	known := make(map[string]bool, len(previous))
+	for _, finding := range previous {
+		known[finding.Rule+"\x00"+finding.ResourceID] = true
+	}
+
+	var findings []rules.Finding
+	for _, finding := range current {
+		if !known[finding.Rule+"\x00"+finding.ResourceID] {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+func sortResources(resources []models.Resource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return lessResource(resources[i], resources[j])
+	})
+}
+
+func lessResource(a, b models.Resource) bool {
+	if a.ProjectName != b.ProjectName {
+		return a.ProjectName < b.ProjectName
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	return a.Name < b.Name
+}
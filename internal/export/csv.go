@@ -0,0 +1,68 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"openstack-reporter/internal/models"
+)
+
+// CSVHeader lists the columns written by WriteCSV
+var CSVHeader = []string{
+	"type", "id", "name", "project_id", "project_name", "status",
+	"created_at", "updated_at", "stack_name", "tags",
+}
+
+// WriteCSV writes one row per resource of the report, timestamps are RFC 3339
+// and tags are written as sorted key=value pairs separated by semicolons
+func WriteCSV(w io.Writer, report *models.ResourceReport) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(CSVHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, resource := range report.Resources {
+		record := []string{
+			resource.Type,
+			resource.ID,
+			resource.Name,
+			resource.ProjectID,
+			resource.ProjectName,
+			resource.Status,
+			formatTime(resource.CreatedAt),
+			formatTime(resource.UpdatedAt),
+			resource.StackName,
+			formatTags(resource.Metadata),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// formatTime leaves unknown timestamps empty
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTags(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ";")
+}
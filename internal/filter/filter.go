@@ -0,0 +1,286 @@
+package filter
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"openstack-reporter/internal/models"
+)
+
+// Untagged is the group name for resources without the requested tag key
+const Untagged = "(untagged)"
+
+// TagSelector matches resources by metadata key and optional value
+type TagSelector struct {
+	Key      string `json:"key"`
+	Value    string `json:"value,omitempty"`
+	AnyValue bool   `json:"any_value"`
+}
+
+// ParseTag parses tag selector in "key" or "key=value" form
+func ParseTag(expr string) TagSelector {
+	expr = strings.TrimSpace(expr)
+	if index := strings.Index(expr, "="); index > 0 {
+		return TagSelector{
+			Key:   strings.TrimSpace(expr[:index]),
+			Value: strings.TrimSpace(expr[index+1:]),
+		}
+	}
+	return TagSelector{Key: expr, AnyValue: true}
+}
+
+// Match checks whether metadata contains the selected key (and value)
+func (t TagSelector) Match(metadata map[string]string) bool {
+	value, exists := metadata[t.Key]
+	if !exists {
+		return false
+	}
+	return t.AnyValue || strings.EqualFold(value, t.Value)
+}
+
+// Criteria selects resources of a report, all conditions must match
+type Criteria struct {
+	// Projects are project names or IDs, any of them must match
+	Projects []string `json:"projects,omitempty"`
+	// Types are resource types, any of them must match
+	Types []string `json:"types,omitempty"`
+	// Statuses are resource statuses compared case-insensitively, any of them must match
+	Statuses []string      `json:"statuses,omitempty"`
+	Tags     []TagSelector `json:"tags,omitempty"`
+}
+
+// FromQuery builds criteria from URL query parameters:
+// project, type and status (repeatable or comma separated) and
+// tag=key or tag=key=value, repeatable
+func FromQuery(values url.Values) Criteria {
+	criteria := Criteria{
+		Projects: queryList(values["project"]),
+		Types:    queryList(values["type"]),
+		Statuses: queryList(values["status"]),
+	}
+	for _, expr := range values["tag"] {
+		if selector := ParseTag(expr); selector.Key != "" {
+			criteria.Tags = append(criteria.Tags, selector)
+		}
+	}
+	return criteria
+}
+
+// queryList splits repeated and comma separated query values
+func queryList(values []string) []string {
+	var result []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				result = append(result, item)
+			}
+		}
+	}
+	return result
+}
+
+// IsEmpty reports whether criteria matches every resource
+func (c Criteria) IsEmpty() bool {
+	return len(c.Projects) == 0 && len(c.Types) == 0 && len(c.Statuses) == 0 && len(c.Tags) == 0
+}
+
+// String describes the criteria for report headers, empty if nothing is selected
+func (c Criteria) String() string {
+	var parts []string
+	if len(c.Projects) > 0 {
+		parts = append(parts, "project: "+strings.Join(c.Projects, ", "))
+	}
+	if len(c.Types) > 0 {
+		parts = append(parts, "type: "+strings.Join(c.Types, ", "))
+	}
+	if len(c.Statuses) > 0 {
+		parts = append(parts, "status: "+strings.Join(c.Statuses, ", "))
+	}
+	for _, selector := range c.Tags {
+		if selector.AnyValue {
+			parts = append(parts, "tag: "+selector.Key)
+		} else {
+			parts = append(parts, "tag: "+selector.Key+"="+selector.Value)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// matchProject checks whether project ID or name is selected
+func (c Criteria) matchProject(id, name string) bool {
+	if len(c.Projects) == 0 {
+		return true
+	}
+	for _, project := range c.Projects {
+		if project == id || project == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Match checks whether resource satisfies the criteria
+func (c Criteria) Match(resource models.Resource) bool {
+	if !c.matchProject(resource.ProjectID, resource.ProjectName) {
+		return false
+	}
+	if len(c.Types) > 0 && !containsFold(c.Types, resource.Type) {
+		return false
+	}
+	if len(c.Statuses) > 0 && !containsFold(c.Statuses, resource.Status) {
+		return false
+	}
+	for _, selector := range c.Tags {
+		if !selector.Match(resource.Metadata) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns a copy of the report containing only matching resources,
+// with the summary recalculated for the selected subset
+func Apply(report *models.ResourceReport, criteria Criteria) *models.ResourceReport {
+	if criteria.IsEmpty() {
+		return report
+	}
+
+	// Other projects must not be listed in a project scoped report
+	var matchProject func(id, name string) bool
+	if len(criteria.Projects) > 0 {
+		matchProject = criteria.matchProject
+	}
+	return subset(report, criteria.Match, matchProject)
+}
+
+// Visible returns a copy of the report restricted to projects with the
+// given IDs, for clients who may only see their own projects
+func Visible(report *models.ResourceReport, projectIDs []string) *models.ResourceReport {
+	allowed := make(map[string]bool, len(projectIDs))
+	for _, id := range projectIDs {
+		allowed[id] = true
+	}
+	return subset(report, func(resource models.Resource) bool {
+		return allowed[resource.ProjectID]
+	}, func(id, name string) bool {
+		return allowed[id]
+	})
+}
+
+// subset copies matching resources and their unresolved owners, and
+// matching projects and their collection failures unless matchProject is nil
+func subset(report *models.ResourceReport, match func(models.Resource) bool, matchProject func(id, name string) bool) *models.ResourceReport {
+	filtered := *report
+	filtered.Resources = []models.Resource{}
+	selected := make(map[string]bool)
+	for _, resource := range report.Resources {
+		if match(resource) {
+			filtered.Resources = append(filtered.Resources, resource)
+			selected[resource.ID] = true
+		}
+	}
+
+	filtered.UnresolvedOwners = nil
+	for _, owner := range report.UnresolvedOwners {
+		if selected[owner.ResourceID] {
+			filtered.UnresolvedOwners = append(filtered.UnresolvedOwners, owner)
+		}
+	}
+
+	if matchProject != nil {
+		filtered.Projects = []models.Project{}
+		for _, project := range report.Projects {
+			if matchProject(project.ID, project.Name) {
+				filtered.Projects = append(filtered.Projects, project)
+			}
+		}
+
+		kept := make(map[string]bool, len(filtered.Projects))
+		for _, project := range filtered.Projects {
+			kept[project.ID] = true
+		}
+		filtered.Failures = nil
+		for _, failure := range report.Failures {
+			if failure.ProjectID == "" || kept[failure.ProjectID] {
+				filtered.Failures = append(filtered.Failures, failure)
+			}
+		}
+	}
+
+	filtered.CalculateSummary()
+	return &filtered
+}
+
+// TagValue returns value of tag key used for grouping, Untagged if key is missing
+func TagValue(resource models.Resource, key string) string {
+	value, exists := resource.Metadata[key]
+	if !exists {
+		return Untagged
+	}
+	if value == "" {
+		return key
+	}
+	return value
+}
+
+// TagGroup is a set of resources sharing the same tag value
+type TagGroup struct {
+	Value     string            `json:"value"`
+	Count     int               `json:"count"`
+	ByType    map[string]int    `json:"by_type"`
+	Resources []models.Resource `json:"resources,omitempty"`
+}
+
+// GroupByTag groups resources by value of tag key, sorted by value with Untagged last
+func GroupByTag(resources []models.Resource, key string, withResources bool) []TagGroup {
+	index := make(map[string]*TagGroup)
+	for _, resource := range resources {
+		value := TagValue(resource, key)
+		group, exists := index[value]
+		if !exists {
+			group = &TagGroup{Value: value, ByType: make(map[string]int)}
+			index[value] = group
+		}
+		group.Count++
+		group.ByType[resource.Type]++
+		if withResources {
+			group.Resources = append(group.Resources, resource)
+		}
+	}
+
+	groups := make([]TagGroup, 0, len(index))
+	for _, group := range index {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Value == Untagged) != (groups[j].Value == Untagged) {
+			return groups[j].Value == Untagged
+		}
+		return groups[i].Value < groups[j].Value
+	})
+	return groups
+}
+
+// TagKeys returns number of resources per value for every tag key
+func TagKeys(resources []models.Resource) map[string]map[string]int {
+	keys := make(map[string]map[string]int)
+	for _, resource := range resources {
+		for key, value := range resource.Metadata {
+			if keys[key] == nil {
+				keys[key] = make(map[string]int)
+			}
+			keys[key][value]++
+		}
+	}
+	return keys
+}
@@ -1,36 +1,121 @@
 package handlers
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"openstack-reporter/internal/audit"
+	"openstack-reporter/internal/auth"
+	"openstack-reporter/internal/compliance"
+	"openstack-reporter/internal/config"
+	"openstack-reporter/internal/delta"
+	"openstack-reporter/internal/filter"
+	"openstack-reporter/internal/health"
+	"openstack-reporter/internal/i18n"
+	"openstack-reporter/internal/ipam"
+	"openstack-reporter/internal/logging"
+	"openstack-reporter/internal/mailer"
 	"openstack-reporter/internal/models"
+	"openstack-reporter/internal/notify"
 	"openstack-reporter/internal/openstack"
-	"openstack-reporter/internal/storage"
 	"openstack-reporter/internal/pdf"
+	"openstack-reporter/internal/rules"
+	"openstack-reporter/internal/storage"
+	"openstack-reporter/internal/topology"
+	"openstack-reporter/internal/version"
 )
 
+// ruleResultsFile keeps results of expression rules for the saved report
+const ruleResultsFile = "rule_results.json"
+
+// errRefreshCancelled is the outcome of a refresh cancelled via the API
+var errRefreshCancelled = errors.New("refresh cancelled")
+
+// refreshSession is a refresh started by RefreshWithProgress, guarded by Handler.mu
+type refreshSession struct {
+	progress chan openstack.ProgressMessage
+	// final is the complete, error or cancelled message, set before progress
+	// is closed so a full buffer cannot drop it
+	final  openstack.ProgressMessage
+	cancel context.CancelFunc
+	// owner identifies the principal that started the refresh, empty without authentication
+	owner string
+	// cancelled is set by CancelRefresh, done once collection ended and
+	// saving once it succeeded; a done refresh can no longer be cancelled
+	cancelled bool
+	done      bool
+	saving    bool
+}
+
 type Handler struct {
-	storage          *storage.Storage
-	progressChannels map[string]chan openstack.ProgressMessage
-	mu               sync.RWMutex
+	config   *config.Config
+	storage  *storage.Storage
+	notifier *notify.Notifier
+	mailer   *mailer.Mailer
+	audit    *audit.Log
+	sessions map[string]*refreshSession
+	mu       sync.RWMutex
+	started  time.Time
+
+	// background is cancelled by Shutdown to abort running refreshes
+	background context.Context
+	abort      context.CancelFunc
+	refreshes  sync.WaitGroup
+	closing    bool
 }
 
-func NewHandler() *Handler {
-	storage := storage.NewStorage()
+func NewHandler(cfg *config.Config) *Handler {
+	storage := storage.NewStorage(cfg.Storage)
 	if err := storage.Initialize(); err != nil {
-		log.Printf("Warning: Failed to initialize storage: %v", err)
+		slog.Warn("Failed to initialize storage", "error", err)
+	}
+
+	// Notifications are optional, disabled without config file
+	var notifier *notify.Notifier
+	if config, err := notify.LoadConfig(cfg.Files.Notifications); err == nil {
+		notifier = notify.NewNotifier(config)
+		slog.Info("Notifications enabled", "targets", len(config.Targets))
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Failed to load notification config", "error", err)
+	}
+
+	// Scheduled mailer is optional, disabled without config file
+	var reportMailer *mailer.Mailer
+	if config, err := mailer.LoadConfig(cfg.Files.Mailer); err == nil {
+		reportMailer = mailer.NewMailer(config)
+		slog.Info("Mailer enabled", "recipient_lists", len(config.Recipients), "every", config.Schedule.Every, "at", config.Schedule.At)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("Failed to load mailer config", "error", err)
+	}
+
+	auditLog, err := audit.Open(cfg.Audit)
+	if err != nil {
+		slog.Warn("Audit log disabled", "error", err)
 	}
 
+	background, abort := context.WithCancel(context.Background())
 	return &Handler{
-		storage:          storage,
-		progressChannels: make(map[string]chan openstack.ProgressMessage),
+		background: background,
+		abort:      abort,
+		config:     cfg,
+		storage:    storage,
+		notifier:   notifier,
+		mailer:     reportMailer,
+		audit:      auditLog,
+		sessions:   make(map[string]*refreshSession),
+		started:    time.Now(),
 	}
 }
 
@@ -39,13 +124,28 @@ func (h *Handler) GetResources(c *gin.Context) {
 	// Try to load cached report first
 	report, err := h.storage.LoadReport()
 	if err != nil {
-		log.Printf("No cached report found, attempting to fetch from OpenStack: %v", err)
+		// Readers only get the saved report, collecting requires the refresh permission
+		if principal := auth.PrincipalFrom(c); principal != nil && !principal.Can(auth.PermissionRefresh) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   localize(c, "api.error.no_report.resources"),
+				"details": localize(c, "api.details.refresh_first"),
+			})
+			return
+		}
+		logger(c).Info("No cached report found, fetching from OpenStack", "error", err)
 
 		// If no cache, try to fetch from OpenStack
-		freshReport, fetchErr := h.fetchFromOpenStack()
+		if !h.beginRefresh() {
+			shuttingDown(c)
+			return
+		}
+		defer h.refreshes.Done()
+		ctx, cancel := h.refreshContext(c.Request.Context())
+		defer cancel()
+		freshReport, fetchErr := h.fetchFromOpenStack(ctx, logger(c))
 		if fetchErr != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Failed to load cached data and unable to fetch from OpenStack",
+				"error":   localize(c, "api.error.load_failed"),
 				"details": fetchErr.Error(),
 			})
 			return
@@ -55,115 +155,314 @@ func (h *Handler) GetResources(c *gin.Context) {
 
 		// Save to cache
 		if saveErr := h.storage.SaveReport(report); saveErr != nil {
-			log.Printf("Warning: Failed to save report to cache: %v", saveErr)
+			logger(c).Warn("Failed to save report to cache", "error", saveErr)
+		} else {
+			h.evaluateSavedReport(ctx, report, logger(c))
 		}
 	}
 
-	c.JSON(http.StatusOK, report)
+	c.JSON(http.StatusOK, filter.Apply(visibleReport(c, report), filter.FromQuery(c.Request.URL.Query())))
 }
 
 // RefreshResources fetches fresh data from OpenStack and saves it
 func (h *Handler) RefreshResources(c *gin.Context) {
-	report, err := h.fetchFromOpenStack()
+	if !h.beginRefresh() {
+		shuttingDown(c)
+		return
+	}
+	defer h.refreshes.Done()
+
+	ctx, cancel := h.refreshContext(c.Request.Context())
+	defer cancel()
+	report, err := h.fetchFromOpenStack(ctx, logger(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to fetch resources from OpenStack",
+			"error":   localize(c, "api.error.fetch_failed"),
 			"details": err.Error(),
 		})
 		return
 	}
 
-	// Save the fresh report
-	if err := h.storage.SaveReport(report); err != nil {
-		log.Printf("Warning: Failed to save refreshed report: %v", err)
-	}
-
-	// Clean up old backups (keep last 7 days)
-	if err := h.storage.CleanupBackups(7 * 24 * time.Hour); err != nil {
-		log.Printf("Warning: Failed to cleanup backups: %v", err)
-	}
+	h.saveRefreshed(ctx, report, logger(c))
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Resources refreshed successfully",
-		"generated_at": report.GeneratedAt,
-		"total_resources": len(report.Resources),
+		"message":         localize(c, "api.message.refreshed"),
+		"generated_at":    report.GeneratedAt,
+		"total_resources": len(visibleReport(c, report).Resources),
 	})
 }
 
 // RefreshWithProgress fetches fresh data from OpenStack with progress updates
 func (h *Handler) RefreshWithProgress(c *gin.Context) {
+	sessionID, err := newSessionID()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   localize(c, "api.error.refresh_start_failed"),
+			"details": err.Error(),
+		})
+		return
+	}
+	if !h.beginRefresh() {
+		shuttingDown(c)
+		return
+	}
 	progressChan := make(chan openstack.ProgressMessage, 100)
-	sessionID := fmt.Sprintf("session_%d", time.Now().UnixNano())
+	record := audit.NewRecord(c)
+	record.SessionID = sessionID
+	log := logger(c).With("session_id", sessionID)
+
+	// Start background refresh, it outlives the request
+	ctx, cancel := h.refreshContext(context.Background())
+	session := &refreshSession{progress: progressChan, cancel: cancel, owner: principalKey(c)}
 
 	// Store progress channel
 	h.mu.Lock()
-	h.progressChannels[sessionID] = progressChan
+	h.sessions[sessionID] = session
 	h.mu.Unlock()
 
-	// Start background refresh
 	go func() {
 		defer func() {
 			// Clean up when goroutine is done
+			cancel()
 			h.mu.Lock()
-			delete(h.progressChannels, sessionID)
+			delete(h.sessions, sessionID)
 			h.mu.Unlock()
 			close(progressChan)
+			h.refreshes.Done()
 		}()
 
-		report, err := h.fetchFromOpenStackWithProgress(progressChan)
-		if err != nil {
-			select {
-			case progressChan <- openstack.ProgressMessage{
-				Type:    "error",
-				Message: fmt.Sprintf("Failed to fetch resources: %v", err),
-			}:
-			default:
+		log.Info("Refresh started")
+		report, err := h.fetchFromOpenStackWithProgress(ctx, progressChan, log)
+
+		// A cancelled refresh discards what it collected, even if collection
+		// finished before the cancellation took effect
+		h.mu.Lock()
+		cancelled := session.cancelled
+		session.done = true
+		session.saving = err == nil && !cancelled
+		h.mu.Unlock()
+		if cancelled {
+			log.Info("Refresh cancelled, saved report kept")
+			h.auditRefresh(record, nil, errRefreshCancelled, log)
+			session.final = openstack.ProgressMessage{
+				Type: "cancelled",
+				Key:  "api.progress.cancelled",
 			}
 			return
 		}
-
-		// Save the fresh report
-		if err := h.storage.SaveReport(report); err != nil {
-			log.Printf("Warning: Failed to save refreshed report: %v", err)
+		if err != nil {
+			log.Error("Refresh failed", "error", err)
+			h.auditRefresh(record, nil, err, log)
+			session.final = openstack.ProgressMessage{
+				Type: "error",
+				Key:  "api.progress.fetch_failed",
+				Args: []interface{}{err},
+			}
+			return
 		}
 
-		// Clean up old backups
-		if err := h.storage.CleanupBackups(7 * 24 * time.Hour); err != nil {
-			log.Printf("Warning: Failed to cleanup backups: %v", err)
-		}
+		h.saveRefreshed(ctx, report, log)
+		h.auditRefresh(record, report, nil, log)
+		log.Info("Refresh completed", "resources", len(report.Resources))
 
-		select {
-		case progressChan <- openstack.ProgressMessage{
+		session.final = openstack.ProgressMessage{
 			Type:    "complete",
-			Message: "Resources refreshed successfully",
+			Key:     "api.message.refreshed",
 			Summary: calculateTypeSummary(report.Resources),
-		}:
-		default:
 		}
 	}()
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":    "Refresh started",
+		"message":    localize(c, "api.message.refresh_started"),
+		"session_id": sessionID,
+	})
+}
+
+// CancelRefresh aborts a refresh started by RefreshWithProgress. The
+// progress stream ends with a cancelled message and the saved report is kept.
+// Only the principal that started the refresh and admins may cancel it.
+func (h *Handler) CancelRefresh(c *gin.Context) {
+	sessionID := c.Param("session_id")
+	principal := auth.PrincipalFrom(c)
+	admin := principal == nil || principal.Can(auth.PermissionAdmin)
+
+	h.mu.Lock()
+	session, exists := h.sessions[sessionID]
+	permitted := exists && (admin || session.owner == principalKey(c))
+	saving := permitted && session.saving
+	// A failed collection ends without saving, cancelling it has no effect
+	failed := permitted && session.done && !session.saving && !session.cancelled
+	if permitted && !session.done {
+		session.cancelled = true
+		session.cancel()
+	}
+	h.mu.Unlock()
+
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.error.session_not_found")})
+		return
+	}
+	if !permitted {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   localize(c, "api.error.refresh_not_owner"),
+			"details": localize(c, "api.details.refresh_not_owner"),
+		})
+		return
+	}
+	if saving {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   localize(c, "api.error.refresh_saving"),
+			"details": localize(c, "api.details.refresh_saving"),
+		})
+		return
+	}
+	if failed {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   localize(c, "api.error.refresh_failed"),
+			"details": localize(c, "api.details.refresh_failed"),
+		})
+		return
+	}
+
+	logger(c).Info("Refresh cancellation requested", "session_id", sessionID)
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    localize(c, "api.message.refresh_cancelled"),
 		"session_id": sessionID,
 	})
 }
 
-// GetProgress returns SSE stream of progress updates
+// newSessionID returns an unguessable refresh session ID
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "session_" + hex.EncodeToString(buf), nil
+}
+
+// principalKey identifies the principal of a request, empty without authentication
+func principalKey(c *gin.Context) string {
+	if principal := auth.PrincipalFrom(c); principal != nil {
+		return principal.Method + ":" + principal.Name
+	}
+	return ""
+}
+
+// beginRefresh registers a refresh Shutdown waits for, the caller must call
+// h.refreshes.Done once it ends. It fails once shutdown has started.
+func (h *Handler) beginRefresh() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closing {
+		return false
+	}
+	h.refreshes.Add(1)
+	return true
+}
+
+// refreshContext bounds a refresh by the collection timeout. The refresh is
+// aborted when parent is done or Shutdown gives up waiting.
+func (h *Handler) refreshContext(parent context.Context) (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout := h.config.Collection.Timeout; timeout > 0 {
+		ctx, cancel = context.WithTimeout(h.background, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(h.background)
+	}
+	stop := context.AfterFunc(parent, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// shuttingDown rejects a refresh requested during shutdown
+func shuttingDown(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   localize(c, "api.error.shutting_down"),
+		"details": localize(c, "api.details.retry_later"),
+	})
+}
+
+// Shutdown rejects new refreshes and waits for running ones until ctx is
+// done, then aborts them. An aborted refresh saves nothing, so the stored
+// report stays intact.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.refreshes.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		h.abort()
+		return nil
+	case <-ctx.Done():
+	}
+
+	slog.Warn("Aborting running refreshes")
+	h.abort()
+	<-done
+	return ctx.Err()
+}
+
+// saveRefreshed saves a freshly collected report, evaluates it and removes
+// old backups. Notifications are bounded by ctx of the refresh.
+func (h *Handler) saveRefreshed(ctx context.Context, report *models.ResourceReport, log *slog.Logger) {
+	if err := h.storage.SaveReport(report); err != nil {
+		log.Warn("Failed to save refreshed report", "error", err)
+	}
+	h.evaluateSavedReport(ctx, report, log)
+
+	if err := h.storage.CleanupBackups(); err != nil {
+		log.Warn("Failed to cleanup backups", "error", err)
+	}
+}
+
+// GetProgress returns SSE stream of progress updates. Only the principal
+// that started the refresh and admins may follow it.
 func (h *Handler) GetProgress(c *gin.Context) {
 	sessionID := c.Query("session_id")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "session_id is required"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.error.session_required")})
 		return
 	}
+	principal := auth.PrincipalFrom(c)
+	admin := principal == nil || principal.Can(auth.PermissionAdmin)
 
 	h.mu.RLock()
-	progressChan, exists := h.progressChannels[sessionID]
+	session, exists := h.sessions[sessionID]
+	permitted := exists && (admin || session.owner == principalKey(c))
 	h.mu.RUnlock()
 
 	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.error.session_not_found")})
 		return
 	}
+	if !permitted {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   localize(c, "api.error.refresh_not_owner"),
+			"details": localize(c, "api.details.refresh_progress_not_owner"),
+		})
+		return
+	}
+
+	// Clients restricted to their projects only follow those projects
+	var visibleProjects map[string]bool
+	if !allProjects(c) {
+		visibleProjects = make(map[string]bool)
+		if report, err := h.loadReport(c); err == nil {
+			for _, project := range report.Projects {
+				visibleProjects[project.Name] = true
+			}
+		}
+	}
 
 	// Set SSE headers
 	c.Header("Content-Type", "text/event-stream")
@@ -174,16 +473,25 @@ func (h *Handler) GetProgress(c *gin.Context) {
 	// Send events
 	for {
 		select {
-		case msg, ok := <-progressChan:
-			if !ok {
-				return
+		case msg, open := <-session.progress:
+			// The stream ends with the final message kept on the session
+			if !open {
+				if msg = session.final; msg.Type == "" {
+					return
+				}
+			}
+			if visibleProjects != nil {
+				var visible bool
+				if msg, visible = h.scopeProgress(c, msg, visibleProjects); !visible && open {
+					continue
+				}
 			}
 
-			data, _ := json.Marshal(msg)
+			data, _ := json.Marshal(localizeProgress(c, msg))
 			fmt.Fprintf(c.Writer, "data: %s\n\n", data)
 			c.Writer.Flush()
 
-			if msg.Type == "complete" || msg.Type == "error" {
+			if !open {
 				return
 			}
 		case <-c.Request.Context().Done():
@@ -192,109 +500,718 @@ func (h *Handler) GetProgress(c *gin.Context) {
 	}
 }
 
+// scopeProgress drops progress of other projects and overall totals for
+// clients restricted to their projects, the final summary covers their part
+func (h *Handler) scopeProgress(c *gin.Context, msg openstack.ProgressMessage, visibleProjects map[string]bool) (openstack.ProgressMessage, bool) {
+	switch msg.Type {
+	case "start", "error", "cancelled":
+		return msg, true
+	case "complete":
+		msg.Summary = nil
+		if report, err := h.loadReport(c); err == nil {
+			msg.Summary = calculateTypeSummary(report.Resources)
+		}
+		return msg, true
+	}
+	return msg, msg.Project != "" && visibleProjects[msg.Project]
+}
+
+// localizeProgress fills the message of a progress update in the language
+// of the client following it. Updates of a resource type receive its localized
+// name as their first argument.
+func localizeProgress(c *gin.Context, msg openstack.ProgressMessage) openstack.ProgressMessage {
+	if msg.Key == "" {
+		return msg
+	}
+	args := msg.Args
+	if msg.ResourceType != "" {
+		args = append([]interface{}{localize(c, "ui.collect."+msg.ResourceType)}, args...)
+	}
+	msg.Message = localize(c, msg.Key, args...)
+	return msg
+}
+
+// auditRefresh records the outcome of a background refresh, linked to the
+// request record by the session ID
+func (h *Handler) auditRefresh(record audit.Record, report *models.ResourceReport, err error, log *slog.Logger) {
+	if h.audit == nil {
+		return
+	}
+	record.DurationMS = time.Since(record.Time).Milliseconds()
+	record.Time = time.Now()
+	if errors.Is(err, errRefreshCancelled) {
+		record.Outcome = audit.OutcomeCancelled
+	} else if err != nil {
+		record.Outcome = audit.OutcomeFailed
+		record.Error = err.Error()
+	} else {
+		record.Outcome = audit.OutcomeCompleted
+		resources := len(report.Resources)
+		record.Resources = &resources
+	}
+	if err := h.audit.Write(record); err != nil {
+		log.Warn("Failed to write audit record", "error", err)
+	}
+}
+
+// Audit returns the middleware recording requests in the audit log
+func (h *Handler) Audit() gin.HandlerFunc {
+	return h.audit.Middleware()
+}
+
+// GetAuditLog returns audit records, newest first
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	if h.audit == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": localize(c, "api.error.no_audit")})
+		return
+	}
+
+	query := audit.Query{
+		User:    c.Query("user"),
+		IP:      c.Query("ip"),
+		Path:    c.Query("path"),
+		Outcome: c.Query("outcome"),
+		Limit:   100,
+	}
+	for name, target := range map[string]*time.Time{"since": &query.Since, "until": &query.Until} {
+		if value := c.Query(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   localize(c, "api.error.invalid_audit_query"),
+					"details": localize(c, "api.details.rfc3339", name),
+				})
+				return
+			}
+			*target = parsed
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > 1000 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   localize(c, "api.error.invalid_audit_query"),
+				"details": localize(c, "api.details.audit_limit"),
+			})
+			return
+		}
+		query.Limit = limit
+	}
+
+	records, err := h.audit.Search(query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   localize(c, "api.error.audit_failed"),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"records": records,
+		"count":   len(records),
+	})
+}
+
 // ExportToPDF generates and returns a PDF report
 func (h *Handler) ExportToPDF(c *gin.Context) {
-	log.Printf("PDF export requested from %s", c.ClientIP())
+	log := logger(c)
 
 	// Check if report exists
 	if !h.storage.ReportExists() {
-		log.Printf("PDF export failed: no report data available")
+		log.Info("PDF export failed, no report data available")
 		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No report data available for export",
-			"details": "Please refresh the data first",
+			"error":   localize(c, "api.error.no_report.export"),
+			"details": localize(c, "api.details.refresh_first"),
 		})
 		return
 	}
 
 	// Load current report
-	report, err := h.storage.LoadReport()
+	report, err := h.loadReport(c)
 	if err != nil {
-		log.Printf("PDF export failed: error loading report: %v", err)
+		log.Warn("PDF export failed, error loading report", "error", err)
 		c.JSON(http.StatusNotFound, gin.H{
-			"error": "No report data available for export",
-			"details": "Please refresh the data first",
+			"error":   localize(c, "api.error.no_report.export"),
+			"details": localize(c, "api.details.refresh_first"),
 		})
 		return
 	}
 
-	log.Printf("PDF export: loaded report with %d resources", len(report.Resources))
+	criteria := filter.FromQuery(c.Request.URL.Query())
+	report = filter.Apply(report, criteria)
+	log.Debug("PDF export loaded report", "resources", len(report.Resources))
 
 	// Generate PDF
-	log.Printf("PDF export: starting PDF generation")
 	pdfGenerator := pdf.NewGenerator()
-	pdfData, err := pdfGenerator.GenerateReport(report)
+	pdfGenerator.SetLogger(log)
+	complianceReport, err := h.evaluateCompliance(report)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warn("PDF export skipping compliance section", "error", err)
+	}
+	pdfData, err := pdfGenerator.GenerateReportWithOptions(report, pdf.Options{
+		GroupByTag: c.Query("group_by_tag"),
+		Compliance: complianceReport,
+		Scope:      criteria.String(),
+		Language:   language(c),
+	})
 	if err != nil {
-		log.Printf("PDF export failed: error generating PDF: %v", err)
+		log.Error("PDF export failed", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to generate PDF",
+			"error":   localize(c, "api.error.pdf_failed"),
 			"details": err.Error(),
 		})
 		return
 	}
 
-	log.Printf("PDF export: successfully generated PDF (%d bytes)", len(pdfData))
-
 	// Set headers for PDF download
 	filename := "openstack_report_" + time.Now().Format("2006-01-02_15-04-05") + ".pdf"
 	c.Header("Content-Type", "application/pdf")
 	c.Header("Content-Disposition", "attachment; filename="+filename)
-	c.Header("Content-Length", string(rune(len(pdfData))))
+	c.Header("Content-Length", strconv.Itoa(len(pdfData)))
 
 	c.Data(http.StatusOK, "application/pdf", pdfData)
 }
 
+// GetTopology returns the network topology graph as JSON
+func (h *Handler) GetTopology(c *gin.Context) {
+	report, err := h.loadReport(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.topology"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	graph := topology.Build(topologyScope(report, c), c.Query("project"))
+	c.JSON(http.StatusOK, graph)
+}
+
+// ExportTopologyDOT returns the network topology graph in Graphviz DOT format
+func (h *Handler) ExportTopologyDOT(c *gin.Context) {
+	report, err := h.loadReport(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.export"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	graph := topology.Build(topologyScope(report, c), c.Query("project"))
+
+	filename := "openstack_topology_" + time.Now().Format("2006-01-02_15-04-05") + ".dot"
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(graph.DOT()))
+}
+
+// topologyScope applies type, status and tag filters. Project is handled by
+// topology.Build, which keeps shared networks of other projects connected.
+func topologyScope(report *models.ResourceReport, c *gin.Context) *models.ResourceReport {
+	criteria := filter.FromQuery(c.Request.URL.Query())
+	criteria.Projects = nil
+	return filter.Apply(report, criteria)
+}
+
+// GetIPAM returns per-subnet IP utilization, free addresses and subnets close to exhaustion
+func (h *Handler) GetIPAM(c *gin.Context) {
+	report, err := h.loadReport(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.ipam"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	opts := ipam.Options{
+		Project:   c.Query("project"),
+		SubnetID:  c.Query("subnet"),
+		Threshold: ipam.DefaultThreshold,
+		FreeLimit: 10,
+	}
+
+	if value := c.Query("threshold"); value != "" {
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil || threshold <= 0 || threshold > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.error.invalid_threshold")})
+			return
+		}
+		opts.Threshold = threshold
+	}
+
+	if value := c.Query("free"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 || limit > 1024 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": localize(c, "api.error.invalid_free")})
+			return
+		}
+		opts.FreeLimit = limit
+	}
+
+	c.JSON(http.StatusOK, ipam.Analyze(report, opts))
+}
+
+// GetTags returns tag keys with value counts, or resources grouped by tag key if key is given
+func (h *Handler) GetTags(c *gin.Context) {
+	report, err := h.loadReport(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.tags"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	report = filter.Apply(report, filter.FromQuery(c.Request.URL.Query()))
+
+	key := c.Query("key")
+	if key == "" {
+		c.JSON(http.StatusOK, gin.H{
+			"keys": filter.TagKeys(report.Resources),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"key":    key,
+		"groups": filter.GroupByTag(report.Resources, key, c.Query("resources") == "true"),
+	})
+}
+
+// GetCompliance evaluates resources against the tagging compliance policy
+func (h *Handler) GetCompliance(c *gin.Context) {
+	report, err := h.loadReport(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.compliance"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	report = filter.Apply(report, filter.FromQuery(c.Request.URL.Query()))
+
+	result, err := h.evaluateCompliance(report)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   localize(c, "api.error.no_policy"),
+				"details": localize(c, "api.details.create_config", h.config.Files.CompliancePolicy, "COMPLIANCE_POLICY_FILE"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   localize(c, "api.error.policy_failed"),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetRuleResults returns findings of expression rules evaluated after the last refresh
+func (h *Handler) GetRuleResults(c *gin.Context) {
+	// Stored results cover every project, clients restricted to their
+	// projects get the rules evaluated against their part of the report
+	restricted := !allProjects(c)
+
+	var result rules.Result
+	err := h.storage.LoadJSON(ruleResultsFile, &result)
+	if err == nil && c.Query("reevaluate") != "true" && !restricted {
+		c.JSON(http.StatusOK, &result)
+		return
+	}
+
+	// No stored results (e.g. rules added after the last refresh) or reevaluation requested
+	report, err := h.loadReport(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.rules"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	var evaluated *rules.Result
+	if restricted {
+		var ruleSet *rules.RuleSet
+		if ruleSet, err = rules.LoadRules(h.config.Files.Rules); err == nil {
+			evaluated = ruleSet.Evaluate(report, time.Now())
+		}
+	} else {
+		evaluated, err = h.evaluateRules(report, logger(c))
+	}
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   localize(c, "api.error.no_rules"),
+				"details": localize(c, "api.details.create_config", h.config.Files.Rules, "RULES_FILE"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   localize(c, "api.error.rules_failed"),
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, evaluated)
+}
+
+// TestNotifications sends a test message to every notification target
+func (h *Handler) TestNotifications(c *gin.Context) {
+	if !requireAllProjects(c) {
+		return
+	}
+	if h.notifier == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_notifications"),
+			"details": localize(c, "api.details.create_config", h.config.Files.Notifications, "NOTIFY_CONFIG_FILE"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": h.notifier.SendTest(c.Request.Context()),
+	})
+}
+
+// StartMailer runs the scheduled mailer in background if it is configured
+func (h *Handler) StartMailer(ctx context.Context) {
+	if h.mailer != nil {
+		go h.mailer.Run(ctx, h.storage.LoadReport)
+	}
+}
+
+// StartCollector refreshes the report in background on the configured
+// collection interval, if any
+func (h *Handler) StartCollector(ctx context.Context) {
+	interval := h.config.Collection.Interval
+	if interval <= 0 {
+		return
+	}
+	log := slog.Default().With("component", "collector")
+	log.Info("Scheduled collection enabled", "interval", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if !h.beginRefresh() {
+				return
+			}
+			h.scheduledRefresh(log)
+			h.refreshes.Done()
+		}
+	}()
+}
+
+// scheduledRefresh runs one refresh of the collector, a refresh in progress
+// when ctx of StartCollector ends is finished or aborted by Shutdown
+func (h *Handler) scheduledRefresh(log *slog.Logger) {
+	ctx, cancel := h.refreshContext(context.Background())
+	defer cancel()
+
+	report, err := h.fetchFromOpenStack(ctx, log)
+	if err != nil {
+		log.Error("Scheduled refresh failed", "error", err)
+		return
+	}
+	h.saveRefreshed(ctx, report, log)
+	log.Info("Scheduled refresh completed", "resources", len(report.Resources))
+}
+
+// GetConfig returns the effective configuration with secrets redacted
+func (h *Handler) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"file":     h.config.File(),
+		"settings": h.config.Settings(),
+	})
+}
+
+// SendReportMail mails the current report to configured recipients immediately
+func (h *Handler) SendReportMail(c *gin.Context) {
+	if !requireAllProjects(c) {
+		return
+	}
+	if h.mailer == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_mailer"),
+			"details": localize(c, "api.details.create_config", h.config.Files.Mailer, "MAILER_CONFIG_FILE"),
+		})
+		return
+	}
+
+	report, err := h.storage.LoadReport()
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   localize(c, "api.error.no_report.mailing"),
+			"details": localize(c, "api.details.refresh_first"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": h.mailer.SendAll(report),
+	})
+}
+
 // GetReportStatus returns information about the current report
 func (h *Handler) GetReportStatus(c *gin.Context) {
 	status := gin.H{
 		"report_exists": h.storage.ReportExists(),
-		"last_check": time.Now(),
+		"last_check":    time.Now(),
 	}
 
 	if h.storage.ReportExists() {
 		age, err := h.storage.GetReportAge()
 		if err == nil {
 			status["report_age_hours"] = age.Hours()
-			status["report_age_human"] = formatDuration(age)
+			status["report_age_human"] = i18n.FormatDuration(language(c), age)
 		}
 	}
 
 	c.JSON(http.StatusOK, status)
 }
 
+// Healthz is the liveness probe, it only reports that the process serves requests
+func (h *Handler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         health.StatusOK,
+		"version":        version.GetVersionString(),
+		"uptime_seconds": int64(time.Since(h.started).Seconds()),
+	})
+}
+
+// Readyz is the readiness probe, it answers 503 with the failed checks when
+// storage, report freshness or Keystone make the instance useless
+func (h *Handler) Readyz(c *gin.Context) {
+	report := health.Run(c.Request.Context(), h.config.Health.Timeout, h.readinessChecks()...)
+	if !report.Ready() {
+		var failed []string
+		for _, check := range report.Checks {
+			if check.Status == health.StatusFail {
+				failed = append(failed, check.Name+": "+check.Message)
+			}
+		}
+		logger(c).Debug("Not ready", "failed", failed)
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// readinessChecks returns the storage, report freshness and Keystone checks,
+// the latter two are skipped unless configured
+func (h *Handler) readinessChecks() []health.Check {
+	cfg := h.config.Health
+	checks := []health.Check{
+		{
+			Name: "storage",
+			Run: func(ctx context.Context) (map[string]interface{}, error) {
+				return map[string]interface{}{"data_dir": h.storage.DataDir()}, h.storage.CheckWritable()
+			},
+		},
+		{
+			Name: "report_freshness",
+			Run: func(ctx context.Context) (map[string]interface{}, error) {
+				details := map[string]interface{}{"max_age_seconds": int64(cfg.ReportMaxAge.Seconds())}
+				if !h.storage.ReportExists() {
+					return details, fmt.Errorf("no report has been collected")
+				}
+				age, err := h.storage.GetReportAge()
+				if err != nil {
+					return details, err
+				}
+				details["age_seconds"] = int64(age.Seconds())
+				if age > cfg.ReportMaxAge {
+					return details, fmt.Errorf("report is %s old, more than %s", age.Round(time.Second), cfg.ReportMaxAge)
+				}
+				return details, nil
+			},
+		},
+		{
+			Name: "keystone",
+			Run: func(ctx context.Context) (map[string]interface{}, error) {
+				status, err := h.config.OpenStack.CheckKeystone(ctx)
+				details := map[string]interface{}{"auth_url": h.config.OpenStack.AuthURL}
+				if status != 0 {
+					details["http_status"] = status
+				}
+				return details, err
+			},
+		},
+	}
+	if cfg.ReportMaxAge == 0 {
+		checks[1].Disabled = "health.report_max_age is not set"
+	}
+	if !cfg.Keystone {
+		checks[2].Disabled = "health.keystone is disabled"
+	}
+	return checks
+}
+
 // fetchFromOpenStack connects to OpenStack and fetches all resources
-func (h *Handler) fetchFromOpenStack() (*models.ResourceReport, error) {
-	client, err := openstack.NewClient()
+func (h *Handler) fetchFromOpenStack(ctx context.Context, log *slog.Logger) (*models.ResourceReport, error) {
+	client, err := openstack.NewClient(ctx, h.config.OpenStack)
 	if err != nil {
 		return nil, err
 	}
+	client.SetLogger(log)
 
-	return client.GetAllResources()
+	return client.GetAllResources(ctx)
 }
 
 // fetchFromOpenStackWithProgress connects to OpenStack and fetches all resources with progress updates
-func (h *Handler) fetchFromOpenStackWithProgress(progressChan chan openstack.ProgressMessage) (*models.ResourceReport, error) {
+func (h *Handler) fetchFromOpenStackWithProgress(ctx context.Context, progressChan chan openstack.ProgressMessage, log *slog.Logger) (*models.ResourceReport, error) {
 	select {
 	case progressChan <- openstack.ProgressMessage{
-		Type:    "start",
-		Message: "Initializing OpenStack client...",
+		Type: "start",
+		Key:  "api.progress.initializing",
 	}:
 	default:
 	}
 
-	client, err := openstack.NewClient()
+	client, err := openstack.NewClient(ctx, h.config.OpenStack)
 	if err != nil {
 		return nil, err
 	}
+	client.SetLogger(log)
 
 	select {
 	case progressChan <- openstack.ProgressMessage{
-		Type:    "progress",
-		Message: "Getting resources with progress updates...",
+		Type: "progress",
+		Key:  "api.progress.collecting",
 	}:
 	default:
 	}
 
-	return client.GetAllResourcesWithProgress(progressChan)
+	return client.GetAllResourcesWithProgress(ctx, progressChan)
+}
+
+// evaluateCompliance loads the configured policy and evaluates the report against it
+func (h *Handler) evaluateCompliance(report *models.ResourceReport) (*compliance.Report, error) {
+	policy, err := compliance.LoadPolicy(h.config.Files.CompliancePolicy)
+	if err != nil {
+		return nil, err
+	}
+	return compliance.Evaluate(report, policy), nil
+}
+
+// evaluateSavedReport runs compliance policy and expression rules against a
+// freshly saved report and notifies targets of the changes within ctx
+func (h *Handler) evaluateSavedReport(ctx context.Context, report *models.ResourceReport, log *slog.Logger) {
+	h.logCompliance(report, log)
+
+	// Findings of the previous report are overwritten by evaluateRules
+	var previousResults rules.Result
+	hasPreviousResults := h.storage.LoadJSON(ruleResultsFile, &previousResults) == nil
+
+	results, err := h.evaluateRules(report, log)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warn("Failed to evaluate rules", "error", err)
+	}
+
+	if h.notifier == nil {
+		return
+	}
+
+	previous, err := h.storage.LoadPreviousReport()
+	if err != nil {
+		log.Info("Notifications skipped, no previous report to compare with", "error", err)
+		return
+	}
+
+	changes := delta.Compute(previous, report)
+	if results != nil && hasPreviousResults {
+		changes.NewFindings = delta.NewFindings(previousResults.Findings, results.Findings)
+	}
+	if changes.IsEmpty() {
+		return
+	}
+
+	for _, delivery := range h.notifier.Notify(ctx, changes) {
+		switch {
+		case delivery.Error != "":
+			log.Warn("Notification failed", "target", delivery.Target, "error", delivery.Error)
+		case delivery.Skipped != "":
+			log.Info("Notification skipped", "target", delivery.Target, "reason", delivery.Skipped)
+		default:
+			log.Info("Notification sent", "target", delivery.Target, "events", delivery.Events)
+		}
+	}
+}
+
+// evaluateRules evaluates expression rules against the report and stores the results
+func (h *Handler) evaluateRules(report *models.ResourceReport, log *slog.Logger) (*rules.Result, error) {
+	ruleSet, err := rules.LoadRules(h.config.Files.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	result := ruleSet.Evaluate(report, time.Now())
+	if err := h.storage.SaveJSON(ruleResultsFile, result, 0644); err != nil {
+		log.Warn("Failed to save rule results", "error", err)
+	}
+	log.Info("Rules evaluated", "findings", len(result.Findings), "rules", len(result.Rules))
+	return result, nil
+}
+
+// logCompliance reports policy violations of a freshly collected report
+func (h *Handler) logCompliance(report *models.ResourceReport, log *slog.Logger) {
+	result, err := h.evaluateCompliance(report)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("Failed to evaluate compliance policy", "error", err)
+		}
+		return
+	}
+	log.Info("Compliance evaluated", "non_compliant", result.NonCompliant,
+		"evaluated", result.Evaluated, "violations", result.TotalViolations)
+}
+
+// loadReport loads the saved report restricted to projects the client may see
+func (h *Handler) loadReport(c *gin.Context) (*models.ResourceReport, error) {
+	report, err := h.storage.LoadReport()
+	if err != nil {
+		return nil, err
+	}
+	return visibleReport(c, report), nil
+}
+
+// visibleReport restricts the report to projects of Keystone users and
+// project scoped API tokens, other clients see every project
+func visibleReport(c *gin.Context, report *models.ResourceReport) *models.ResourceReport {
+	if allProjects(c) {
+		return report
+	}
+	return filter.Visible(report, auth.PrincipalFrom(c).Projects)
+}
+
+// allProjects reports whether the client may see every project
+func allProjects(c *gin.Context) bool {
+	principal := auth.PrincipalFrom(c)
+	return principal == nil || principal.AllProjects()
+}
+
+// requireAllProjects rejects clients restricted to their projects, for
+// operations that are not scoped to projects
+func requireAllProjects(c *gin.Context) bool {
+	if allProjects(c) {
+		return true
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": localize(c, "api.error.all_projects_required")})
+	return false
 }
 
 // calculateTypeSummary creates a summary of resources by type
@@ -306,28 +1223,22 @@ func calculateTypeSummary(resources []models.Resource) map[string]int {
 	return summary
 }
 
-// formatDuration formats duration in human readable format
-func formatDuration(d time.Duration) string {
-	if d < time.Minute {
-		return "меньше минуты"
-	}
-	if d < time.Hour {
-		minutes := int(d.Minutes())
-		if minutes == 1 {
-			return "1 минута"
-		}
-		return string(rune(minutes)) + " минут"
-	}
-	if d < 24*time.Hour {
-		hours := int(d.Hours())
-		if hours == 1 {
-			return "1 час"
-		}
-		return string(rune(hours)) + " часов"
-	}
-	days := int(d.Hours() / 24)
-	if days == 1 {
-		return "1 день"
+// logger returns the request logger, carrying the request ID and the
+// signed in user
+func logger(c *gin.Context) *slog.Logger {
+	log := logging.FromGin(c)
+	if principal := auth.PrincipalFrom(c); principal != nil {
+		log = log.With("user", principal.Name)
 	}
-	return string(rune(days)) + " дней"
+	return log
+}
+
+// language returns the language selected for the request
+func language(c *gin.Context) string {
+	return i18n.FromRequest(c.Request)
+}
+
+// localize returns the message of key in the request language
+func localize(c *gin.Context, key string, args ...interface{}) string {
+	return i18n.T(language(c), key, args...)
 }
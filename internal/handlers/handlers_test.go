@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"openstack-reporter/internal/config"
+	"openstack-reporter/internal/models"
+	"openstack-reporter/internal/notify"
+	"openstack-reporter/internal/storage"
+)
+
+func TestEvaluateSavedReportPartial(t *testing.T) {
+	var mu sync.Mutex
+	var events []notify.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Events []notify.Event `json:"events"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("payload: %v", err)
+		}
+		mu.Lock()
+		events = append(events, payload.Events...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "notifications.yaml")
+	if err := os.WriteFile(path, []byte("targets:\n  - name: hook\n    url: "+server.URL+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notifyConfig, err := notify.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Handler{
+		config:   &config.Config{},
+		storage:  storage.NewStorage(storage.Config{DataDir: t.TempDir()}),
+		notifier: notify.NewNotifier(notifyConfig),
+	}
+
+	// api failed to collect before, its resources were not created now
+	previous := &models.ResourceReport{
+		GeneratedAt: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+		Resources: []models.Resource{
+			{ID: "srv-1", Type: "server", Status: "ACTIVE", ProjectID: "p-web"},
+			{ID: "vol-1", Type: "volume", Status: "available", ProjectID: "p-web"},
+			{ID: "lb-1", Type: "load_balancer", Status: "ACTIVE", ProjectID: "p-web"},
+			{ID: "srv-2", Type: "server", Status: "ACTIVE", ProjectID: "p-db"},
+		},
+		Failures: []models.CollectionFailure{{ProjectID: "p-api", Error: "unauthorized"}},
+	}
+	// db and the volumes of web failed to collect, their resources were not deleted
+	current := &models.ResourceReport{
+		GeneratedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Resources: []models.Resource{
+			{ID: "srv-1", Type: "server", Status: "ACTIVE", ProjectID: "p-web"},
+			{ID: "srv-3", Type: "server", Status: "ACTIVE", ProjectID: "p-web"},
+			{ID: "srv-9", Type: "server", Status: "ACTIVE", ProjectID: "p-api"},
+		},
+		Failures: []models.CollectionFailure{
+			{ProjectID: "p-db", Error: "failed to create client for project db"},
+			{ProjectID: "p-web", ResourceType: "volume", Error: "timeout"},
+		},
+	}
+	for _, report := range []*models.ResourceReport{previous, current} {
+		if err := h.storage.SaveReport(report); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h.evaluateSavedReport(context.Background(), current, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	mu.Lock()
+	defer mu.Unlock()
+	var kinds []string
+	for _, event := range events {
+		kinds = append(kinds, event.Kind+" "+event.ResourceID)
+	}
+	want := "resource_created srv-3,resource_deleted lb-1"
+	if strings.Join(kinds, ",") != want {
+		t.Errorf("events = %v, want %s", kinds, want)
+	}
+}
+
+func TestCancelRefreshFinished(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{sessions: map[string]*refreshSession{
+		"running": {cancel: func() {}},
+		"failed":  {cancel: func() {}, done: true},
+		"saving":  {cancel: func() {}, done: true, saving: true},
+	}}
+
+	for id, want := range map[string]int{"running": http.StatusAccepted, "failed": http.StatusConflict, "saving": http.StatusConflict, "unknown": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodDelete, "/api/refresh/"+id, nil)
+		c.Params = gin.Params{{Key: "session_id", Value: id}}
+		h.CancelRefresh(c)
+		if w.Code != want {
+			t.Errorf("%s: status %d, want %d", id, w.Code, want)
+		}
+	}
+	if !h.sessions["running"].cancelled || h.sessions["failed"].cancelled {
+		t.Errorf("cancelled running %v, failed %v", h.sessions["running"].cancelled, h.sessions["failed"].cancelled)
+	}
+}
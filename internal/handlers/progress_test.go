@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"openstack-reporter/internal/openstack"
+)
+
+func TestLocalizeProgress(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		lang string
+		msg  openstack.ProgressMessage
+		want string
+	}{
+		{"en", openstack.ProgressMessage{Type: "project_complete", Key: "api.progress.project_complete", Args: []interface{}{12, "web"}}, "Found 12 resources in project web"},
+		{"ru", openstack.ProgressMessage{Type: "project_complete", Key: "api.progress.project_complete", Args: []interface{}{12, "web"}}, "Найдено ресурсов: 12 в проекте web"},
+		{"en", openstack.ProgressMessage{Type: "resource_start", Key: "api.progress.resource_start", ResourceType: "volumes"}, "Volumes: collecting"},
+		{"ru", openstack.ProgressMessage{Type: "resource_error", Key: "api.progress.resource_error", ResourceType: "servers", Args: []interface{}{errors.New("timeout")}}, "Серверы: ошибка сбора: timeout"},
+		{"ru", openstack.ProgressMessage{Type: "error", Key: "api.progress.fetch_failed", Args: []interface{}{errors.New("denied")}}, "Не удалось получить ресурсы: denied"},
+		// Messages without a key are already localized
+		{"ru", openstack.ProgressMessage{Type: "start", Message: "ready"}, "ready"},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/api/progress?lang="+tt.lang, nil)
+		if got := localizeProgress(c, tt.msg).Message; got != tt.want {
+			t.Errorf("%s %s = %q, want %q", tt.lang, tt.msg.Key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package health runs the dependency checks behind the readiness probe and
+// reports a structured result per check.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Check statuses, a report is ready when no check failed
+const (
+	StatusOK      = "ok"
+	StatusFail    = "fail"
+	StatusSkipped = "skipped"
+)
+
+// DefaultTimeout bounds every check of a probe
+const DefaultTimeout = 5 * time.Second
+
+// Config controls the readiness checks
+type Config struct {
+	// ReportMaxAge fails readiness when the report is older, zero disables the check
+	ReportMaxAge time.Duration `yaml:"report_max_age" json:"report_max_age"`
+	// Keystone checks that the OpenStack identity endpoint answers
+	Keystone bool `yaml:"keystone" json:"keystone"`
+	// Timeout bounds each check
+	Timeout time.Duration `yaml:"timeout" json:"timeout"`
+}
+
+// Check probes one dependency. Run returns details for the result and an
+// error when the dependency is unusable. A check with Disabled set is
+// reported as skipped with that reason.
+type Check struct {
+	Name     string
+	Disabled string
+	Run      func(ctx context.Context) (map[string]interface{}, error)
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Name       string                 `json:"name"`
+	Status     string                 `json:"status"`
+	Message    string                 `json:"message,omitempty"`
+	DurationMS int64                  `json:"duration_ms"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report combines the results of all checks
+type Report struct {
+	Status    string    `json:"status"`
+	CheckedAt time.Time `json:"checked_at"`
+	Checks    []Result  `json:"checks"`
+}
+
+// Ready reports whether no check failed
+func (r *Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Run executes the checks concurrently, each bounded by timeout, and returns
+// the results in the order of checks
+func Run(ctx context.Context, timeout time.Duration, checks ...Check) *Report {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	report := &Report{
+		Status:    StatusOK,
+		CheckedAt: time.Now(),
+		Checks:    make([]Result, len(checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		if check.Disabled != "" || check.Run == nil {
+			report.Checks[i] = Result{Name: check.Name, Status: StatusSkipped, Message: check.Disabled}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			report.Checks[i] = run(ctx, timeout, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == StatusFail {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// run executes one check, a check that ignores its context still fails
+// once the timeout passes
+func run(ctx context.Context, timeout time.Duration, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type outcome struct {
+		details map[string]interface{}
+		err     error
+	}
+	done := make(chan outcome, 1)
+	started := time.Now()
+	go func() {
+		details, err := check.Run(ctx)
+		done <- outcome{details, err}
+	}()
+
+	result := Result{Name: check.Name, Status: StatusOK}
+	select {
+	case o := <-done:
+		result.Details = o.details
+		if o.err != nil {
+			result.Status = StatusFail
+			result.Message = o.err.Error()
+		}
+	case <-ctx.Done():
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("check did not finish within %s", timeout)
+	}
+	result.DurationMS = time.Since(started).Milliseconds()
+	return result
+}
@@ -0,0 +1,218 @@
+// Package i18n provides message catalogs for API responses, the web UI and
+// PDF reports. Catalogs are embedded JSON files in locales/, one per language.
+//
+// Messages used from Go are fmt format strings, messages used by the web UI
+// have {name} placeholders substituted in the browser. Plural messages are
+// stored as key.one, key.few, key.many and key.other forms.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLanguage is used when no default language is configured
+const DefaultLanguage = "en"
+
+// CookieName is the cookie keeping the language selected in the web UI
+const CookieName = "lang"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = loadCatalogs()
+
+// defaultLanguage is the language set with SetDefault
+var defaultLanguage = DefaultLanguage
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to read locales: %v", err))
+	}
+
+	result := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", entry.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: failed to parse %s: %v", entry.Name(), err))
+		}
+		result[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return result
+}
+
+// Languages returns supported language codes
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Match returns the supported language of a tag like "ru-RU", or empty string
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	if _, ok := catalogs[tag]; ok {
+		return tag
+	}
+	return ""
+}
+
+// Default returns the configured default language, English otherwise
+func Default() string {
+	return defaultLanguage
+}
+
+// SetDefault configures the language used when a request selects none
+func SetDefault(tag string) error {
+	lang := Match(tag)
+	if lang == "" {
+		return fmt.Errorf("unsupported language %q, expected one of %s", tag, strings.Join(Languages(), ", "))
+	}
+	defaultLanguage = lang
+	return nil
+}
+
+// FromRequest selects the language of a request from the lang query
+// parameter, the lang cookie or the Accept-Language header, in that order
+func FromRequest(r *http.Request) string {
+	if lang := Match(r.URL.Query().Get("lang")); lang != "" {
+		return lang
+	}
+	if cookie, err := r.Cookie(CookieName); err == nil {
+		if lang := Match(cookie.Value); lang != "" {
+			return lang
+		}
+	}
+	if lang := matchAcceptLanguage(r.Header.Get("Accept-Language")); lang != "" {
+		return lang
+	}
+	return Default()
+}
+
+// matchAcceptLanguage returns the supported language with the highest quality
+func matchAcceptLanguage(header string) string {
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+		if lang := Match(tag); lang != "" && quality > bestQuality {
+			best, bestQuality = lang, quality
+		}
+	}
+	return best
+}
+
+// T returns the message of key in the language, formatted with args.
+// Missing messages fall back to English and then to the key itself.
+func T(lang, key string, args ...interface{}) string {
+	message, ok := lookup(lang, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N returns the plural form of key matching n, formatted with n
+func N(lang, key string, n int) string {
+	if message, ok := lookup(lang, key+"."+pluralForm(lang, n)); ok {
+		return fmt.Sprintf(message, n)
+	}
+	return T(lang, key+".other", n)
+}
+
+func lookup(lang, key string) (string, bool) {
+	if message, ok := catalogs[lang][key]; ok {
+		return message, true
+	}
+	message, ok := catalogs[DefaultLanguage][key]
+	return message, ok
+}
+
+// pluralForm follows CLDR cardinal rules for supported languages
+func pluralForm(lang string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch lang {
+	case "ru":
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
+
+// Messages returns the catalog of a language completed with English messages,
+// limited to keys with one of prefixes if any are given
+func Messages(lang string, prefixes ...string) map[string]string {
+	messages := make(map[string]string, len(catalogs[DefaultLanguage]))
+	for _, catalog := range []map[string]string{catalogs[DefaultLanguage], catalogs[lang]} {
+		for key, message := range catalog {
+			if hasPrefix(key, prefixes) {
+				messages[key] = message
+			}
+		}
+	}
+	return messages
+}
+
+func hasPrefix(key string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatDuration formats duration in human readable form
+func FormatDuration(lang string, d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return T(lang, "duration.less_than_minute")
+	case d < time.Hour:
+		return N(lang, "duration.minutes", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return N(lang, "duration.hours", int(d.Hours()))
+	default:
+		return N(lang, "duration.days", int(d.Hours()/24))
+	}
+}
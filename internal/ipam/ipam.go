@@ -0,0 +1,229 @@
+package ipam
+
+import (
+	"math"
+	"math/big"
+	"net/netip"
+	"sort"
+	"time"
+
+	"openstack-reporter/internal/models"
+)
+
+// DefaultThreshold is the utilization percentage at which a subnet is considered close to exhaustion
+const DefaultThreshold = 80.0
+
+// Options controls IPAM analysis
+type Options struct {
+	Project   string  // Project ID or name, empty for all projects
+	SubnetID  string  // Limit to a single subnet
+	Threshold float64 // Utilization percentage for near-exhaustion
+	FreeLimit int     // Maximum number of free IPs listed per subnet
+}
+
+// SubnetUsage describes IP utilization of a single subnet
+type SubnetUsage struct {
+	SubnetID    string                  `json:"subnet_id"`
+	SubnetName  string                  `json:"subnet_name"`
+	CIDR        string                  `json:"cidr"`
+	GatewayIP   string                  `json:"gateway_ip,omitempty"`
+	IPVersion   int                     `json:"ip_version,omitempty"`
+	NetworkID   string                  `json:"network_id"`
+	NetworkName string                  `json:"network_name"`
+	ProjectID   string                  `json:"project_id"`
+	ProjectName string                  `json:"project_name"`
+	Pools       []models.AllocationPool `json:"allocation_pools"`
+	TotalIPs    int                     `json:"total_ips"`
+	UsedIPs     int                     `json:"used_ips"`
+	FreeIPs     int                     `json:"free_ips"`
+	Utilization float64                 `json:"utilization"`
+	Exhausted   bool                    `json:"near_exhaustion"`
+	FreeSample  []string                `json:"free_ip_sample,omitempty"`
+}
+
+// Report is the IPAM view over a resource report
+type Report struct {
+	GeneratedAt    time.Time     `json:"generated_at"`
+	Threshold      float64       `json:"threshold"`
+	Subnets        []SubnetUsage `json:"subnets"`
+	NearExhaustion []SubnetUsage `json:"near_exhaustion"`
+}
+
+// UsedIPsBySubnet returns distinct fixed IP addresses allocated in each subnet
+func UsedIPsBySubnet(resources []models.Resource) map[string]map[string]bool {
+	used := make(map[string]map[string]bool)
+	for _, resource := range resources {
+		if resource.Type != "port" {
+			continue
+		}
+		var port models.Port
+		if resource.DecodeProperties(&port) != nil {
+			continue
+		}
+		for _, ip := range port.FixedIPs {
+			if used[ip.SubnetID] == nil {
+				used[ip.SubnetID] = make(map[string]bool)
+			}
+			used[ip.SubnetID][ip.IPAddress] = true
+		}
+	}
+	return used
+}
+
+// PoolSize returns number of addresses in allocation pools, capped at math.MaxInt32
+func PoolSize(pools []models.AllocationPool) int {
+	total := new(big.Int)
+	for _, pool := range pools {
+		start, err1 := netip.ParseAddr(pool.Start)
+		end, err2 := netip.ParseAddr(pool.End)
+		if err1 != nil || err2 != nil || end.Less(start) {
+			continue
+		}
+		size := new(big.Int).Sub(addrToInt(end), addrToInt(start))
+		total.Add(total, size.Add(size, big.NewInt(1)))
+	}
+	if !total.IsInt64() || total.Int64() > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(total.Int64())
+}
+
+// InPools checks whether address belongs to one of allocation pools
+func InPools(address string, pools []models.AllocationPool) bool {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return false
+	}
+	for _, pool := range pools {
+		start, err1 := netip.ParseAddr(pool.Start)
+		end, err2 := netip.ParseAddr(pool.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if !addr.Less(start) && !end.Less(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountUsed returns the number of used addresses that fall into allocation pools
+func CountUsed(used map[string]bool, pools []models.AllocationPool) int {
+	count := 0
+	for address := range used {
+		if InPools(address, pools) {
+			count++
+		}
+	}
+	return count
+}
+
+// FreeIPs lists up to limit unallocated addresses from allocation pools
+func FreeIPs(pools []models.AllocationPool, used map[string]bool, gatewayIP string, limit int) []string {
+	var result []string
+	for _, pool := range pools {
+		start, err1 := netip.ParseAddr(pool.Start)
+		end, err2 := netip.ParseAddr(pool.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		for addr := start; addr.IsValid() && !end.Less(addr); addr = addr.Next() {
+			if len(result) >= limit {
+				return result
+			}
+			address := addr.String()
+			if used[address] || address == gatewayIP {
+				continue
+			}
+			result = append(result, address)
+		}
+	}
+	return result
+}
+
+// Utilization returns used/total as a percentage
+func Utilization(used, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(used)/float64(total)*1000) / 10
+}
+
+// Analyze builds IPAM view from networks and ports of the report
+func Analyze(report *models.ResourceReport, opts Options) *Report {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultThreshold
+	}
+
+	result := &Report{
+		GeneratedAt:    report.GeneratedAt,
+		Threshold:      opts.Threshold,
+		Subnets:        []SubnetUsage{},
+		NearExhaustion: []SubnetUsage{},
+	}
+
+	used := UsedIPsBySubnet(report.Resources)
+
+	for _, resource := range report.Resources {
+		if resource.Type != "network" {
+			continue
+		}
+		if opts.Project != "" && resource.ProjectID != opts.Project && resource.ProjectName != opts.Project {
+			continue
+		}
+
+		var network models.Network
+		if resource.DecodeProperties(&network) != nil {
+			continue
+		}
+
+		for _, subnet := range network.Subnets {
+			if opts.SubnetID != "" && subnet.ID != opts.SubnetID {
+				continue
+			}
+
+			total := PoolSize(subnet.AllocationPools)
+			usedCount := CountUsed(used[subnet.ID], subnet.AllocationPools)
+
+			usage := SubnetUsage{
+				SubnetID:    subnet.ID,
+				SubnetName:  subnet.Name,
+				CIDR:        subnet.CIDR,
+				GatewayIP:   subnet.GatewayIP,
+				IPVersion:   subnet.IPVersion,
+				NetworkID:   network.ID,
+				NetworkName: network.Name,
+				ProjectID:   resource.ProjectID,
+				ProjectName: resource.ProjectName,
+				Pools:       subnet.AllocationPools,
+				TotalIPs:    total,
+				UsedIPs:     usedCount,
+				FreeIPs:     total - usedCount,
+				Utilization: Utilization(usedCount, total),
+			}
+			usage.Exhausted = total > 0 && usage.Utilization >= opts.Threshold
+
+			if opts.FreeLimit > 0 {
+				usage.FreeSample = FreeIPs(subnet.AllocationPools, used[subnet.ID], subnet.GatewayIP, opts.FreeLimit)
+			}
+
+			result.Subnets = append(result.Subnets, usage)
+			if usage.Exhausted {
+				result.NearExhaustion = append(result.NearExhaustion, usage)
+			}
+		}
+	}
+
+	sort.Slice(result.Subnets, func(i, j int) bool {
+		return result.Subnets[i].Utilization > result.Subnets[j].Utilization
+	})
+	sort.Slice(result.NearExhaustion, func(i, j int) bool {
+		return result.NearExhaustion[i].Utilization > result.NearExhaustion[j].Utilization
+	})
+
+	return result
+}
+
+func addrToInt(addr netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(addr.AsSlice())
+}
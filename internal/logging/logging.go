@@ -0,0 +1,142 @@
+// Package logging configures the structured logger by level and format,
+// redacts credential-like fields and carries request and refresh session
+// IDs through contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces credential values
+const Redacted = "[REDACTED]"
+
+var (
+	// sensitiveKey matches attribute keys holding credentials
+	sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|authorization|api_?key|cookie|private_?key)`)
+	// sensitiveAssignment matches KEY=value and key: value pairs in free text,
+	// e.g. OS_PASSWORD=... in command output or errors
+	sensitiveAssignment = regexp.MustCompile(`(?i)\b([\w.-]*(?:password|passwd|secret|token|api_?key|credential)[\w.-]*)(["']?\s*[=:]\s*["']?)([^\s"',;&]+)`)
+	// authorizationValue matches HTTP credentials in free text
+	authorizationValue = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[a-z0-9._~+/=-]{8,}`)
+)
+
+// ParseLevel converts debug, info, warn or error to a level
+func ParseLevel(value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q, expected debug, info, warn or error", value)
+}
+
+// New returns a logger writing text or JSON records of at least level to w
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q, expected text or json", format)
+}
+
+// Setup installs a logger of the level and format as default, standard
+// library log output goes through it as well
+func Setup(level, format string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logger, err := New(os.Stderr, parsed, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	return nil
+}
+
+// Redact masks credential assignments and HTTP credentials in free text
+func Redact(text string) string {
+	text = sensitiveAssignment.ReplaceAllString(text, "${1}${2}"+Redacted)
+	return authorizationValue.ReplaceAllString(text, "${1} "+Redacted)
+}
+
+// RedactValue masks the value of a credential-like key and credentials in
+// strings, nested maps and lists of decoded JSON are redacted recursively
+func RedactValue(key string, value interface{}) interface{} {
+	if sensitiveKey.MatchString(key) {
+		if text, ok := value.(string); ok && text == "" {
+			return value
+		}
+		return Redacted
+	}
+	switch v := value.(type) {
+	case string:
+		return Redact(v)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			redacted[k] = RedactValue(k, item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = RedactValue(key, item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// redactAttr masks values of credential-like keys and credentials in strings and errors
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindGroup {
+		return attr
+	}
+	if sensitiveKey.MatchString(attr.Key) {
+		if attr.Value.Kind() == slog.KindString && attr.Value.String() == "" {
+			return attr
+		}
+		return slog.String(attr.Key, Redacted)
+	}
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, Redact(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, Redact(err.Error()))
+		}
+	}
+	return attr
+}
+
+type contextKey struct{}
+
+// WithLogger returns a context carrying the logger
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the context, the default logger otherwise
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
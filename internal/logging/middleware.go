@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID, taken from the client or proxy when valid
+const RequestIDHeader = "X-Request-ID"
+
+// probePaths are requested by Kubernetes and Docker health checks
+var probePaths = map[string]bool{"/healthz": true, "/readyz": true}
+
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// NewID returns a random identifier for requests
+func NewID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Middleware assigns a request ID, stores a logger carrying it in the
+// request context and logs every request once it is handled
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = NewID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		logger := slog.Default().With("request_id", requestID)
+		c.Request = c.Request.WithContext(WithLogger(c.Request.Context(), logger))
+
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case strings.HasPrefix(c.Request.URL.Path, "/static/"):
+			level = slog.LevelDebug
+		case probePaths[c.Request.URL.Path]:
+			// Probes run every few seconds, failures are visible in their answer
+			level = slog.LevelDebug
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		)
+	}
+}
+
+// FromGin returns the request logger
+func FromGin(c *gin.Context) *slog.Logger {
+	return FromContext(c.Request.Context())
+}
@@ -0,0 +1,398 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openstack-reporter/internal/filter"
+	"openstack-reporter/internal/i18n"
+	"openstack-reporter/internal/models"
+	"openstack-reporter/internal/pdf"
+)
+
+// DefaultConfigFile is used when the configuration sets no file
+const DefaultConfigFile = "mailer.yaml"
+
+const (
+	// dialTimeout bounds connecting to the SMTP server
+	dialTimeout = 30 * time.Second
+	// sessionTimeout bounds the whole SMTP conversation including the upload
+	sessionTimeout = 5 * time.Minute
+)
+
+// SMTPConfig describes the outgoing mail server
+type SMTPConfig struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	Username string `yaml:"username" json:"username,omitempty"`
+	// Password may be omitted in favour of SMTP_PASSWORD environment variable
+	Password string `yaml:"password" json:"-"`
+	From     string `yaml:"from" json:"from"`
+	// TLS uses implicit TLS (port 465), otherwise STARTTLS is used when offered
+	TLS      bool `yaml:"tls" json:"tls"`
+	Insecure bool `yaml:"insecure" json:"insecure,omitempty"`
+}
+
+// Schedule defines when reports are sent, in server local time
+type Schedule struct {
+	// Every is "daily" or "weekly"
+	Every   string `yaml:"every" json:"every"`
+	Weekday string `yaml:"weekday" json:"weekday,omitempty"`
+	// At is the time of day as HH:MM
+	At string `yaml:"at" json:"at"`
+}
+
+// Recipient is a list of addresses receiving the report of selected projects
+type Recipient struct {
+	Name string   `yaml:"name" json:"name"`
+	To   []string `yaml:"to" json:"to"`
+	// Projects are project names or IDs, empty means the whole cloud
+	Projects []string `yaml:"projects" json:"projects,omitempty"`
+	Subject  string   `yaml:"subject" json:"subject,omitempty"`
+	// Language of the message and PDF, the default language if empty
+	Language string `yaml:"language" json:"language,omitempty"`
+}
+
+// Config is the mailer configuration
+type Config struct {
+	SMTP       SMTPConfig  `yaml:"smtp" json:"smtp"`
+	Schedule   Schedule    `yaml:"schedule" json:"schedule"`
+	Recipients []Recipient `yaml:"recipients" json:"recipients"`
+}
+
+// Delivery is the outcome of mailing a single recipient list
+type Delivery struct {
+	Recipient string `json:"recipient"`
+	Resources int    `json:"resources"`
+	Skipped   string `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// LoadConfig reads and validates a YAML mailer config
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mailer config: %w", err)
+	}
+
+	config := Config{
+		SMTP:     SMTPConfig{Port: 587},
+		Schedule: Schedule{Every: "weekly", Weekday: "monday", At: "08:00"},
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse mailer config: %w", err)
+	}
+
+	if config.SMTP.Host == "" || config.SMTP.From == "" {
+		return nil, fmt.Errorf("mailer config requires smtp.host and smtp.from")
+	}
+	if _, err := mail.ParseAddress(config.SMTP.From); err != nil {
+		return nil, fmt.Errorf("mailer config has invalid smtp.from %q: %w", config.SMTP.From, err)
+	}
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		config.SMTP.Password = password
+	}
+	if _, err := config.Schedule.Next(time.Now()); err != nil {
+		return nil, err
+	}
+	for i, recipient := range config.Recipients {
+		if len(recipient.To) == 0 {
+			return nil, fmt.Errorf("mailer recipient %d has no addresses", i+1)
+		}
+		if _, err := parseAddresses(recipient.To); err != nil {
+			return nil, fmt.Errorf("mailer recipient %d: %w", i+1, err)
+		}
+		if recipient.Name == "" {
+			config.Recipients[i].Name = strings.Join(recipient.To, ", ")
+		}
+		if recipient.Language != "" {
+			lang := i18n.Match(recipient.Language)
+			if lang == "" {
+				return nil, fmt.Errorf("mailer recipient %d has unsupported language %q, expected one of %s",
+					i+1, recipient.Language, strings.Join(i18n.Languages(), ", "))
+			}
+			config.Recipients[i].Language = lang
+		}
+	}
+
+	return &config, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Next returns the first scheduled time after now
+func (s Schedule) Next(now time.Time) (time.Time, error) {
+	at, err := time.Parse("15:04", s.At)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule time %q, expected HH:MM", s.At)
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, now.Location())
+	switch strings.ToLower(s.Every) {
+	case "daily":
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+	case "weekly":
+		weekday, ok := weekdays[strings.ToLower(s.Weekday)]
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid schedule weekday %q", s.Weekday)
+		}
+		next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+	default:
+		return time.Time{}, fmt.Errorf("invalid schedule %q, expected daily or weekly", s.Every)
+	}
+	return next, nil
+}
+
+// Mailer sends PDF reports to configured recipients
+type Mailer struct {
+	config *Config
+}
+
+// NewMailer creates a mailer for the configuration
+func NewMailer(config *Config) *Mailer {
+	return &Mailer{config: config}
+}
+
+// Run sends reports on schedule until the context is cancelled
+func (m *Mailer) Run(ctx context.Context, loadReport func() (*models.ResourceReport, error)) {
+	log := slog.Default().With("component", "mailer")
+	for {
+		next, err := m.config.Schedule.Next(time.Now())
+		if err != nil {
+			log.Error("Failed to schedule report", "error", err)
+			return
+		}
+		log.Info("Next report scheduled", "at", next.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		report, err := loadReport()
+		if err != nil {
+			log.Warn("Skipping scheduled report", "error", err)
+			continue
+		}
+		for _, delivery := range m.SendAll(report) {
+			switch {
+			case delivery.Error != "":
+				log.Warn("Failed to send report", "recipient", delivery.Recipient, "error", delivery.Error)
+			case delivery.Skipped != "":
+				log.Info("Skipped report", "recipient", delivery.Recipient, "reason", delivery.Skipped)
+			default:
+				log.Info("Sent report", "recipient", delivery.Recipient, "resources", delivery.Resources)
+			}
+		}
+	}
+}
+
+// SendAll mails the report to every recipient list, scoped to their projects.
+// Lists whose projects match no resources are skipped rather than sent an empty PDF.
+func (m *Mailer) SendAll(report *models.ResourceReport) []Delivery {
+	deliveries := make([]Delivery, 0, len(m.config.Recipients))
+	for _, recipient := range m.config.Recipients {
+		scoped := filter.Apply(report, filter.Criteria{Projects: recipient.Projects})
+		delivery := Delivery{Recipient: recipient.Name, Resources: len(scoped.Resources)}
+		if len(scoped.Resources) == 0 {
+			delivery.Skipped = fmt.Sprintf("no resources in projects %s", strings.Join(recipient.Projects, ", "))
+			if len(recipient.Projects) == 0 {
+				delivery.Skipped = "the report has no resources"
+			}
+		} else if err := m.Send(recipient, scoped); err != nil {
+			delivery.Error = err.Error()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+// Send renders the report as PDF and mails it to the recipient list
+func (m *Mailer) Send(recipient Recipient, report *models.ResourceReport) error {
+	lang := recipient.Language
+	if lang == "" {
+		lang = i18n.Default()
+	}
+
+	pdfData, err := pdf.NewGenerator().GenerateReportWithOptions(report, pdf.Options{Language: lang})
+	if err != nil {
+		return err
+	}
+
+	subject := recipient.Subject
+	if subject == "" {
+		subject = i18n.T(lang, "mail.subject", report.GeneratedAt.Format("2006-01-02"))
+	}
+	body := strings.ReplaceAll(i18n.T(lang, "mail.body",
+		report.GeneratedAt.Format("2006-01-02 15:04 MST"), len(report.Resources), len(report.Projects)), "\n", "\r\n")
+	filename := "openstack_report_" + report.GeneratedAt.Format("2006-01-02") + ".pdf"
+
+	from, err := mail.ParseAddress(m.config.SMTP.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q: %w", m.config.SMTP.From, err)
+	}
+	to, err := parseAddresses(recipient.To)
+	if err != nil {
+		return err
+	}
+
+	message, err := buildMessage(from, to, subject, body, filename, pdfData)
+	if err != nil {
+		return err
+	}
+	return m.deliver(from, to, message)
+}
+
+// parseAddresses parses RFC 5322 addresses, optionally with display names
+func parseAddresses(list []string) ([]*mail.Address, error) {
+	addresses := make([]*mail.Address, 0, len(list))
+	for _, value := range list {
+		address, err := mail.ParseAddress(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q: %w", value, err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, nil
+}
+
+// buildMessage creates a MIME message with text body and PDF attachment,
+// addresses are formatted with their display names in the headers
+func buildMessage(from *mail.Address, to []*mail.Address, subject, body, filename string, attachment []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	recipients := make([]string, 0, len(to))
+	for _, address := range to {
+		recipients = append(recipients, address.String())
+	}
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: =?UTF-8?B?%s?=\r\n", base64.StdEncoding.EncodeToString([]byte(subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", writer.Boundary())
+
+	text, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=UTF-8"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	text.Write([]byte(body))
+
+	file, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"application/pdf"},
+		"Content-Transfer-Encoding": {"base64"},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", filename)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.StdEncoding.EncodeToString(attachment)
+	for len(encoded) > 76 {
+		file.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	file.Write([]byte(encoded + "\r\n"))
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// deliver sends the message over SMTP with STARTTLS or implicit TLS, a
+// deadline on the connection keeps a stalled server from blocking forever.
+// The envelope carries bare addresses, display names are only in the headers.
+func (m *Mailer) deliver(from *mail.Address, to []*mail.Address, message []byte) error {
+	cfg := m.config.SMTP
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	tlsConfig := &tls.Config{ServerName: cfg.Host, InsecureSkipVerify: cfg.Insecure}
+
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if err := conn.SetDeadline(time.Now().Add(sessionTimeout)); err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to set SMTP deadline: %w", err)
+	}
+	if cfg.TLS {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	if !cfg.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return fmt.Errorf("failed to start TLS: %w", err)
+			}
+		}
+	}
+	defer client.Close()
+
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+	for _, address := range to {
+		if err := client.Rcpt(address.Address); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s failed: %w", address.Address, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := writer.Write(message); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,221 @@
+package mailer
+
+import (
+	"bufio"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"openstack-reporter/internal/models"
+)
+
+// smtpStub is a minimal SMTP server recording the envelope of every message
+type smtpStub struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []envelope
+}
+
+// envelope is the MAIL FROM and RCPT TO arguments of a message
+type envelope struct {
+	from string
+	rcpt []string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStub{listener: listener}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 stub ready")
+
+	var current envelope
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			text.PrintfLine("250 stub")
+		case "MAIL":
+			current.from = strings.TrimPrefix(line, "MAIL FROM:")
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			current.rcpt = append(current.rcpt, strings.TrimPrefix(line, "RCPT TO:"))
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			if _, err := text.ReadDotBytes(); err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, current)
+			s.mu.Unlock()
+			current = envelope{}
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return
+		default:
+			text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func (s *smtpStub) received() []envelope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]envelope(nil), s.messages...)
+}
+
+func (s *smtpStub) config() SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "OpenStack Reporter <reporter@example.com>"}
+}
+
+func fixtureReport() *models.ResourceReport {
+	return &models.ResourceReport{
+		GeneratedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Projects: []models.Project{
+			{ID: "p-web", Name: "web"},
+			{ID: "p-db", Name: "db"},
+		},
+		Resources: []models.Resource{
+			{ID: "srv-1", Name: "builder", Type: "server", Status: "ACTIVE", ProjectID: "p-web", ProjectName: "web"},
+		},
+	}
+}
+
+func TestSendAll(t *testing.T) {
+	server := newSMTPStub(t)
+	mailer := NewMailer(&Config{
+		SMTP: server.config(),
+		Recipients: []Recipient{
+			{Name: "web", To: []string{"web@example.com"}, Projects: []string{"web"}},
+			{Name: "db", To: []string{"db@example.com"}, Projects: []string{"db"}},
+			{Name: "admins", To: []string{"Alice <a@example.com>", "b@example.com"}},
+		},
+	})
+
+	deliveries := mailer.SendAll(fixtureReport())
+	want := []Delivery{
+		{Recipient: "web", Resources: 1},
+		{Recipient: "db", Skipped: "no resources in projects db"},
+		{Recipient: "admins", Resources: 1},
+	}
+	if len(deliveries) != len(want) {
+		t.Fatalf("deliveries = %+v", deliveries)
+	}
+	for i := range want {
+		if deliveries[i] != want[i] {
+			t.Errorf("delivery %d = %+v, want %+v", i, deliveries[i], want[i])
+		}
+	}
+
+	// The envelope carries bare addresses even with display names configured
+	var got []string
+	for _, message := range server.received() {
+		got = append(got, message.from+" -> "+strings.Join(message.rcpt, ","))
+	}
+	wantEnvelopes := "<reporter@example.com> -> <web@example.com>|<reporter@example.com> -> <a@example.com>,<b@example.com>"
+	if strings.Join(got, "|") != wantEnvelopes {
+		t.Errorf("envelopes %v, want %s", got, wantEnvelopes)
+	}
+
+	empty := fixtureReport()
+	empty.Resources = nil
+	deliveries = NewMailer(&Config{SMTP: server.config(), Recipients: []Recipient{{Name: "admins", To: []string{"a@example.com"}}}}).SendAll(empty)
+	if len(deliveries) != 1 || deliveries[0].Skipped != "the report has no resources" {
+		t.Errorf("empty report: deliveries = %+v", deliveries)
+	}
+}
+
+func TestDeliverUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer := NewMailer(&Config{SMTP: SMTPConfig{Host: "127.0.0.1", Port: port, From: "reporter@example.com"}})
+	from := &mail.Address{Address: "reporter@example.com"}
+	err = mailer.deliver(from, []*mail.Address{{Address: "a@example.com"}}, []byte("Subject: x\r\n\r\nx\r\n"))
+	if err == nil || !strings.Contains(err.Error(), "failed to connect to SMTP server") {
+		t.Errorf("deliver = %v, want connection error", err)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	from := &mail.Address{Name: "OpenStack Reporter", Address: "from@example.com"}
+	to := []*mail.Address{{Address: "a@example.com"}, {Name: "Bob", Address: "b@example.com"}}
+	message, err := buildMessage(from, to, "Отчёт", "body", "report.pdf", make([]byte, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(string(message)))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Get("From") != `"OpenStack Reporter" <from@example.com>` || header.Get("To") != `<a@example.com>, "Bob" <b@example.com>` ||
+		!strings.HasPrefix(header.Get("Subject"), "=?UTF-8?B?") {
+		t.Errorf("header = %v", header)
+	}
+	if !strings.Contains(string(message), `filename="report.pdf"`) {
+		t.Error("attachment missing")
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(filepath.Join("..", "..", "mailer.example.yaml"))
+	if err != nil {
+		t.Fatalf("example config: %v", err)
+	}
+	if config.SMTP.From != "OpenStack Reporter <reporter@example.com>" {
+		t.Errorf("from = %q", config.SMTP.From)
+	}
+
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"smtp: {host: h, from: 'Reporter reporter@example.com'}\n", `invalid smtp.from "Reporter reporter@example.com"`},
+		{"smtp: {host: h, from: r@example.com}\nrecipients:\n  - to: [ok@example.com, not-an-address]\n", `mailer recipient 1: invalid address "not-an-address"`},
+		{"smtp: {host: h, from: r@example.com}\nrecipients:\n  - to: []\n", "mailer recipient 1 has no addresses"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "mailer.yaml")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want %q", tt.content, err, tt.want)
+		}
+	}
+}
@@ -1,19 +1,58 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // Resource represents a generic OpenStack resource
 type Resource struct {
-	ID           string            `json:"id"`
-	Name         string            `json:"name"`
-	Type         string            `json:"type"`
-	ProjectID    string            `json:"project_id"`
-	ProjectName  string            `json:"project_name"`
-	Status       string            `json:"status"`
-	CreatedAt    time.Time         `json:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
-	Properties   interface{}       `json:"properties,omitempty"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Type        string            `json:"type"`
+	ProjectID   string            `json:"project_id"`
+	ProjectName string            `json:"project_name"`
+	Status      string            `json:"status"`
+	CreatedAt   *time.Time        `json:"created_at"`
+	UpdatedAt   *time.Time        `json:"updated_at"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	StackID     string            `json:"stack_id,omitempty"`
+	StackName   string            `json:"stack_name,omitempty"`
+	Properties  interface{}       `json:"properties,omitempty"`
+}
+
+// OptionalTime returns nil for zero time, so unknown timestamps are reported as null
+func OptionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// CreatedBefore orders resources by creation time, resources with unknown time go last
+func (r Resource) CreatedBefore(other Resource) bool {
+	if r.CreatedAt == nil || other.CreatedAt == nil {
+		return r.CreatedAt != nil && other.CreatedAt == nil
+	}
+	return r.CreatedAt.Before(*other.CreatedAt)
+}
+
+// DecodeProperties converts resource properties into the typed model.
+// Properties hold structs right after collection and generic maps after
+// the report is loaded from storage, so both are handled via JSON.
+func (r Resource) DecodeProperties(target interface{}) error {
+	if r.Properties == nil {
+		return fmt.Errorf("resource %s has no properties", r.ID)
+	}
+	data, err := json.Marshal(r.Properties)
+	if err != nil {
+		return fmt.Errorf("failed to marshal properties: %w", err)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("failed to unmarshal properties: %w", err)
+	}
+	return nil
 }
 
 // Project represents OpenStack project
@@ -27,47 +66,47 @@ type Project struct {
 
 // Server represents OpenStack compute instance
 type Server struct {
-	ID           string            `json:"id"`
-	Name         string            `json:"name"`
-	Status       string            `json:"status"`
-	FlavorName   string            `json:"flavor_name"`
-	FlavorID     string            `json:"flavor_id"`
-	Networks     map[string]string `json:"networks"`
-	CreatedAt    time.Time         `json:"created_at"`
-	UpdatedAt    time.Time         `json:"updated_at"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Status     string            `json:"status"`
+	FlavorName string            `json:"flavor_name"`
+	FlavorID   string            `json:"flavor_id"`
+	Networks   map[string]string `json:"networks"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
 }
 
 // Volume represents OpenStack block storage volume
 type Volume struct {
-	ID             string               `json:"id"`
-	Name           string               `json:"name"`
-	Status         string               `json:"status"`
-	Size           int                  `json:"size"`
-	VolumeType     string               `json:"volume_type"`
-	Bootable       bool                 `json:"bootable"`
-	Attachments    []VolumeAttachment   `json:"attachments"`
-	AttachedTo     string               `json:"attached_to,omitempty"`
-	CreatedAt      time.Time            `json:"created_at"`
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Status      string             `json:"status"`
+	Size        int                `json:"size"`
+	VolumeType  string             `json:"volume_type"`
+	Bootable    bool               `json:"bootable"`
+	Attachments []VolumeAttachment `json:"attachments"`
+	AttachedTo  string             `json:"attached_to,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
 }
 
 // VolumeAttachment represents volume attachment details
 type VolumeAttachment struct {
-	ServerID     string `json:"server_id"`
-	ServerName   string `json:"server_name,omitempty"`
-	Device       string `json:"device,omitempty"`
+	ServerID   string `json:"server_id"`
+	ServerName string `json:"server_name,omitempty"`
+	Device     string `json:"device,omitempty"`
 }
 
 // LoadBalancer represents OpenStack load balancer
 type LoadBalancer struct {
-	ID                string    `json:"id"`
-	Name              string    `json:"name"`
-	Description       string    `json:"description"`
-	ProvisioningStatus string   `json:"provisioning_status"`
-	OperatingStatus   string    `json:"operating_status"`
-	VipAddress        string    `json:"vip_address"`
-	VipSubnetID       string    `json:"vip_subnet_id"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                 string    `json:"id"`
+	Name               string    `json:"name"`
+	Description        string    `json:"description"`
+	ProvisioningStatus string    `json:"provisioning_status"`
+	OperatingStatus    string    `json:"operating_status"`
+	VipAddress         string    `json:"vip_address"`
+	VipSubnetID        string    `json:"vip_subnet_id"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 // FloatingIP represents OpenStack floating IP
@@ -79,24 +118,151 @@ type FloatingIP struct {
 	PortID               string    `json:"port_id,omitempty"`
 	AttachedResourceName string    `json:"attached_resource_name,omitempty"`
 	FloatingNetworkID    string    `json:"floating_network_id"`
+	DNSNames             []string  `json:"dns_names,omitempty"`
 	CreatedAt            time.Time `json:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at"`
 }
 
+// Stack represents Heat orchestration stack
+type Stack struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	Status       string          `json:"status"`
+	StatusReason string          `json:"status_reason,omitempty"`
+	Tags         []string        `json:"tags,omitempty"`
+	Resources    []StackResource `json:"resources,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// StackResource represents resource managed by a Heat stack
+type StackResource struct {
+	LogicalID  string `json:"logical_id"`
+	PhysicalID string `json:"physical_id"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+}
+
+// ObjectContainer represents Swift object storage container
+type ObjectContainer struct {
+	Name          string   `json:"name"`
+	ObjectCount   int64    `json:"object_count"`
+	Bytes         int64    `json:"bytes"`
+	Public        bool     `json:"public"`
+	ReadACL       []string `json:"read_acl,omitempty"`
+	WriteACL      []string `json:"write_acl,omitempty"`
+	StoragePolicy string   `json:"storage_policy,omitempty"`
+}
+
+// DNSZone represents Designate DNS zone
+type DNSZone struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Description string    `json:"description,omitempty"`
+	Type        string    `json:"type"`
+	TTL         int       `json:"ttl"`
+	Serial      int       `json:"serial"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DNSRecordSet represents Designate DNS recordset
+type DNSRecordSet struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	ZoneID      string   `json:"zone_id"`
+	ZoneName    string   `json:"zone_name"`
+	Type        string   `json:"type"`
+	Records     []string `json:"records"`
+	TTL         int      `json:"ttl"`
+	Status      string   `json:"status"`
+	Description string   `json:"description,omitempty"`
+	// FloatingIPs lists floating IP IDs the records point to
+	FloatingIPs []string `json:"floating_ips,omitempty"`
+	// Dangling lists record addresses inside cloud subnets that are not allocated
+	Dangling  []string  `json:"dangling,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // VPNService represents OpenStack VPN service
 type VPNService struct {
-	ID              string    `json:"id"`
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	Status          string    `json:"status"`
-	RouterID        string    `json:"router_id"`
-	SubnetID        string    `json:"subnet_id"`
-	PeerID          string    `json:"peer_id,omitempty"`
-	PeerAddress     string    `json:"peer_address,omitempty"`
-	AuthMode        string    `json:"auth_mode,omitempty"`
-	IKEVersion      string    `json:"ike_version,omitempty"`
-	MTU             int       `json:"mtu,omitempty"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	RouterID    string     `json:"router_id"`
+	SubnetID    string     `json:"subnet_id"`
+	PeerID      string     `json:"peer_id,omitempty"`
+	PeerAddress string     `json:"peer_address,omitempty"`
+	AuthMode    string     `json:"auth_mode,omitempty"`
+	IKEVersion  string     `json:"ike_version,omitempty"`
+	MTU         int        `json:"mtu,omitempty"`
+	ExternalIP  string     `json:"external_ip,omitempty"`
+	Connections []string   `json:"connections,omitempty"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+// VPNConnection represents OpenStack VPN IPSec site connection
+type VPNConnection struct {
+	ID                 string         `json:"id"`
+	Name               string         `json:"name"`
+	Description        string         `json:"description"`
+	Status             string         `json:"status"`
+	VPNServiceID       string         `json:"vpn_service_id"`
+	VPNServiceName     string         `json:"vpn_service_name,omitempty"`
+	RouterID           string         `json:"router_id,omitempty"`
+	PeerID             string         `json:"peer_id,omitempty"`
+	PeerAddress        string         `json:"peer_address,omitempty"`
+	AuthMode           string         `json:"auth_mode,omitempty"`
+	Initiator          string         `json:"initiator,omitempty"`
+	IKEVersion         string         `json:"ike_version,omitempty"`
+	MTU                int            `json:"mtu,omitempty"`
+	LocalCIDRs         []string       `json:"local_cidrs,omitempty"`
+	PeerCIDRs          []string       `json:"peer_cidrs,omitempty"`
+	IKEPolicy          *IKEPolicy     `json:"ike_policy,omitempty"`
+	IPSecPolicy        *IPSecPolicy   `json:"ipsec_policy,omitempty"`
+	LocalEndpointGroup *EndpointGroup `json:"local_endpoint_group,omitempty"`
+	PeerEndpointGroup  *EndpointGroup `json:"peer_endpoint_group,omitempty"`
+	WeakCrypto         []string       `json:"weak_crypto,omitempty"`
+	CreatedAt          *time.Time     `json:"created_at"`
+	UpdatedAt          *time.Time     `json:"updated_at"`
+}
+
+// IKEPolicy represents VPNaaS IKE (phase 1) policy
+type IKEPolicy struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	IKEVersion          string `json:"ike_version"`
+	EncryptionAlgorithm string `json:"encryption_algorithm"`
+	AuthAlgorithm       string `json:"auth_algorithm"`
+	PFS                 string `json:"pfs"`
+	Lifetime            string `json:"lifetime"`
+}
+
+// IPSecPolicy represents VPNaaS IPsec (phase 2) policy
+type IPSecPolicy struct {
+	ID                  string `json:"id"`
+	Name                string `json:"name"`
+	EncryptionAlgorithm string `json:"encryption_algorithm"`
+	AuthAlgorithm       string `json:"auth_algorithm"`
+	PFS                 string `json:"pfs"`
+	TransformProtocol   string `json:"transform_protocol"`
+	EncapsulationMode   string `json:"encapsulation_mode"`
+	Lifetime            string `json:"lifetime"`
+}
+
+// EndpointGroup represents VPNaaS endpoint group (local subnets or peer CIDRs)
+type EndpointGroup struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Endpoints []string `json:"endpoints"`
+	CIDRs     []string `json:"cidrs,omitempty"`
 }
 
 // Cluster represents Kubernetes cluster
@@ -120,16 +286,49 @@ type Router struct {
 	AdminStateUp        bool                   `json:"admin_state_up"`
 	ExternalGatewayInfo map[string]interface{} `json:"external_gateway_info,omitempty"`
 	Routes              []interface{}          `json:"routes,omitempty"`
-	CreatedAt           time.Time              `json:"created_at"`
-	UpdatedAt           time.Time              `json:"updated_at"`
+	CreatedAt           *time.Time             `json:"created_at"`
+	UpdatedAt           *time.Time             `json:"updated_at"`
 }
 
 // Subnet represents OpenStack subnet
 type Subnet struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	CIDR      string `json:"cidr"`
-	GatewayIP string `json:"gateway_ip,omitempty"`
+	ID              string           `json:"id"`
+	Name            string           `json:"name"`
+	CIDR            string           `json:"cidr"`
+	GatewayIP       string           `json:"gateway_ip,omitempty"`
+	IPVersion       int              `json:"ip_version,omitempty"`
+	AllocationPools []AllocationPool `json:"allocation_pools,omitempty"`
+	TotalIPs        int              `json:"total_ips"`
+	UsedIPs         int              `json:"used_ips"`
+	Utilization     float64          `json:"utilization"`
+}
+
+// AllocationPool represents subnet allocation pool range
+type AllocationPool struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Port represents OpenStack network port
+type Port struct {
+	ID             string        `json:"id"`
+	Name           string        `json:"name"`
+	Status         string        `json:"status"`
+	NetworkID      string        `json:"network_id"`
+	DeviceID       string        `json:"device_id,omitempty"`
+	DeviceOwner    string        `json:"device_owner,omitempty"`
+	MACAddress     string        `json:"mac_address"`
+	AdminStateUp   bool          `json:"admin_state_up"`
+	FixedIPs       []PortFixedIP `json:"fixed_ips"`
+	SecurityGroups []string      `json:"security_groups,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// PortFixedIP represents fixed IP address of a port in a subnet
+type PortFixedIP struct {
+	SubnetID  string `json:"subnet_id"`
+	IPAddress string `json:"ip_address"`
 }
 
 // Network represents OpenStack network
@@ -148,21 +347,124 @@ type Network struct {
 
 // ResourceReport represents the complete report structure
 type ResourceReport struct {
-	GeneratedAt time.Time  `json:"generated_at"`
-	Projects    []Project  `json:"projects"`
-	Resources   []Resource `json:"resources"`
-	Summary     Summary    `json:"summary"`
+	GeneratedAt      time.Time           `json:"generated_at"`
+	Projects         []Project           `json:"projects"`
+	Resources        []Resource          `json:"resources"`
+	Summary          Summary             `json:"summary"`
+	UnresolvedOwners []UnresolvedOwner   `json:"unresolved_owners,omitempty"`
+	Failures         []CollectionFailure `json:"failures,omitempty"`
+}
+
+// CollectionFailure is a project or resource type whose resources could not
+// be collected and are missing from the report. An empty ProjectID stands
+// for every project, an empty ResourceType for every type.
+type CollectionFailure struct {
+	ProjectID    string `json:"project_id,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+	Error        string `json:"error"`
+}
+
+// Collected reports whether the project and type of the resource were
+// collected, resources of failed collections are missing from the report
+// without being deleted
+func (r *ResourceReport) Collected(resource Resource) bool {
+	for _, failure := range r.Failures {
+		if (failure.ProjectID == "" || failure.ProjectID == resource.ProjectID) &&
+			(failure.ResourceType == "" || failure.ResourceType == resource.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// UnresolvedOwner is a resource whose owning project could not be resolved
+type UnresolvedOwner struct {
+	ResourceID   string `json:"resource_id"`
+	ResourceName string `json:"resource_name"`
+	ResourceType string `json:"resource_type"`
+	ProjectID    string `json:"project_id"`
 }
 
 // Summary provides counts by resource type
 type Summary struct {
-	TotalProjects      int `json:"total_projects"`
-	TotalServers       int `json:"total_servers"`
-	TotalVolumes       int `json:"total_volumes"`
-	TotalLoadBalancers int `json:"total_load_balancers"`
-	TotalFloatingIPs   int `json:"total_floating_ips"`
-	TotalVPNServices   int `json:"total_vpn_services"`
-	TotalClusters      int `json:"total_clusters"`
-	TotalRouters       int `json:"total_routers"`
-	TotalNetworks      int `json:"total_networks"`
+	TotalProjects         int `json:"total_projects"`
+	TotalServers          int `json:"total_servers"`
+	TotalVolumes          int `json:"total_volumes"`
+	TotalLoadBalancers    int `json:"total_load_balancers"`
+	TotalFloatingIPs      int `json:"total_floating_ips"`
+	TotalVPNServices      int `json:"total_vpn_services"`
+	TotalVPNConnections   int `json:"total_vpn_connections"`
+	TotalWeakVPNCrypto    int `json:"total_weak_vpn_crypto"`
+	TotalClusters         int `json:"total_clusters"`
+	TotalRouters          int `json:"total_routers"`
+	TotalNetworks         int `json:"total_networks"`
+	TotalPorts            int `json:"total_ports"`
+	TotalObjectContainers int `json:"total_object_containers"`
+	TotalPublicContainers int `json:"total_public_containers"`
+	TotalDNSZones         int `json:"total_dns_zones"`
+	TotalDNSRecordSets    int `json:"total_dns_recordsets"`
+	TotalDanglingDNS      int `json:"total_dangling_dns"`
+	TotalStacks           int `json:"total_stacks"`
+	TotalStackManaged     int `json:"total_stack_managed"`
+	TotalUnresolvedOwners int `json:"total_unresolved_owners"`
+}
+
+// CalculateSummary recalculates summary counters from report resources.
+// Works for collected reports and reports loaded from JSON.
+func (r *ResourceReport) CalculateSummary() {
+	summary := Summary{
+		TotalProjects:         len(r.Projects),
+		TotalUnresolvedOwners: len(r.UnresolvedOwners),
+	}
+
+	for _, resource := range r.Resources {
+		if resource.StackID != "" {
+			summary.TotalStackManaged++
+		}
+
+		switch resource.Type {
+		case "server":
+			summary.TotalServers++
+		case "volume":
+			summary.TotalVolumes++
+		case "load_balancer":
+			summary.TotalLoadBalancers++
+		case "floating_ip":
+			summary.TotalFloatingIPs++
+		case "vpn_service":
+			summary.TotalVPNServices++
+		case "vpn_connection":
+			summary.TotalVPNConnections++
+			var conn VPNConnection
+			if resource.DecodeProperties(&conn) == nil && len(conn.WeakCrypto) > 0 {
+				summary.TotalWeakVPNCrypto++
+			}
+		case "cluster":
+			summary.TotalClusters++
+		case "router":
+			summary.TotalRouters++
+		case "network":
+			summary.TotalNetworks++
+		case "port":
+			summary.TotalPorts++
+		case "object_container":
+			summary.TotalObjectContainers++
+			var container ObjectContainer
+			if resource.DecodeProperties(&container) == nil && container.Public {
+				summary.TotalPublicContainers++
+			}
+		case "stack":
+			summary.TotalStacks++
+		case "dns_zone":
+			summary.TotalDNSZones++
+		case "dns_recordset":
+			summary.TotalDNSRecordSets++
+			var record DNSRecordSet
+			if resource.DecodeProperties(&record) == nil && len(record.Dangling) > 0 {
+				summary.TotalDanglingDNS++
+			}
+		}
+	}
+
+	r.Summary = summary
 }
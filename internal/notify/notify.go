@@ -0,0 +1,412 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openstack-reporter/internal/delta"
+)
+
+// DefaultConfigFile is used when the configuration sets no file
+const DefaultConfigFile = "notifications.yaml"
+
+// Event kinds
+const (
+	ResourceCreated = "resource_created"
+	ResourceDeleted = "resource_deleted"
+	StatusError     = "status_error"
+	NewFinding      = "new_finding"
+)
+
+// Target types
+const (
+	TypeWebhook    = "webhook"
+	TypeSlack      = "slack"
+	TypeMattermost = "mattermost"
+)
+
+// defaultMaxEvents limits events listed in a single message
+const defaultMaxEvents = 50
+
+// Target is a notification destination
+type Target struct {
+	Name     string            `yaml:"name" json:"name"`
+	Type     string            `yaml:"type" json:"type"`
+	URL      string            `yaml:"url" json:"-"`
+	Channel  string            `yaml:"channel" json:"channel,omitempty"`
+	Username string            `yaml:"username" json:"username,omitempty"`
+	Headers  map[string]string `yaml:"headers" json:"-"`
+	// Projects routes events of these project names or IDs only, empty means all projects
+	Projects []string `yaml:"projects" json:"projects,omitempty"`
+	// Events selects event kinds, empty means all kinds
+	Events []string `yaml:"events" json:"events,omitempty"`
+	// MaxPerHour limits messages sent to the target, 0 means unlimited
+	MaxPerHour int `yaml:"max_per_hour" json:"max_per_hour,omitempty"`
+	// MaxEvents limits events listed in a single message
+	MaxEvents int `yaml:"max_events" json:"max_events,omitempty"`
+}
+
+// Config lists notification targets
+type Config struct {
+	Targets []Target `yaml:"targets" json:"targets"`
+}
+
+// Event is a single change reported to targets
+type Event struct {
+	Kind         string `json:"kind"`
+	ProjectID    string `json:"project_id"`
+	ProjectName  string `json:"project_name"`
+	ResourceID   string `json:"resource_id"`
+	ResourceName string `json:"resource_name"`
+	ResourceType string `json:"resource_type"`
+	Status       string `json:"status,omitempty"`
+	PrevStatus   string `json:"previous_status,omitempty"`
+	Rule         string `json:"rule,omitempty"`
+	Severity     string `json:"severity,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+// Delivery is the outcome of sending to a single target
+type Delivery struct {
+	Target  string `json:"target"`
+	Events  int    `json:"events"`
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// LoadConfig reads and validates a YAML notification config
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notification config: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse notification config: %w", err)
+	}
+
+	for i := range config.Targets {
+		target := &config.Targets[i]
+		if target.Name == "" {
+			target.Name = fmt.Sprintf("target-%d", i+1)
+		}
+		if target.URL == "" {
+			return nil, fmt.Errorf("notification target %q has no url", target.Name)
+		}
+		target.Type = strings.ToLower(target.Type)
+		switch target.Type {
+		case "":
+			target.Type = TypeWebhook
+		case TypeWebhook, TypeSlack, TypeMattermost:
+		default:
+			return nil, fmt.Errorf("notification target %q: unknown type %q", target.Name, target.Type)
+		}
+		for _, kind := range target.Events {
+			switch kind {
+			case ResourceCreated, ResourceDeleted, StatusError, NewFinding:
+			default:
+				return nil, fmt.Errorf("notification target %q: unknown event %q", target.Name, kind)
+			}
+		}
+		if target.MaxEvents <= 0 {
+			target.MaxEvents = defaultMaxEvents
+		}
+	}
+
+	return &config, nil
+}
+
+// Notifier delivers report changes to configured targets
+type Notifier struct {
+	targets []Target
+	client  *http.Client
+	mu      sync.Mutex
+	sent    map[string][]time.Time
+}
+
+// NewNotifier creates a notifier for the configured targets
+func NewNotifier(config *Config) *Notifier {
+	return &Notifier{
+		targets: config.Targets,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		sent:    make(map[string][]time.Time),
+	}
+}
+
+// Targets returns configured targets without secrets
+func (n *Notifier) Targets() []Target {
+	return n.targets
+}
+
+// Events converts a report delta into notification events
+func Events(d *delta.Delta) []Event {
+	var events []Event
+	for _, resource := range d.Added {
+		events = append(events, Event{
+			Kind:         ResourceCreated,
+			ProjectID:    resource.ProjectID,
+			ProjectName:  resource.ProjectName,
+			ResourceID:   resource.ID,
+			ResourceName: resource.Name,
+			ResourceType: resource.Type,
+			Status:       resource.Status,
+		})
+	}
+	for _, resource := range d.Removed {
+		events = append(events, Event{
+			Kind:         ResourceDeleted,
+			ProjectID:    resource.ProjectID,
+			ProjectName:  resource.ProjectName,
+			ResourceID:   resource.ID,
+			ResourceName: resource.Name,
+			ResourceType: resource.Type,
+			Status:       resource.Status,
+		})
+	}
+	for _, change := range d.ErrorTransitions() {
+		events = append(events, Event{
+			Kind:         StatusError,
+			ProjectID:    change.Resource.ProjectID,
+			ProjectName:  change.Resource.ProjectName,
+			ResourceID:   change.Resource.ID,
+			ResourceName: change.Resource.Name,
+			ResourceType: change.Resource.Type,
+			Status:       change.To,
+			PrevStatus:   change.From,
+		})
+	}
+	for _, finding := range d.NewFindings {
+		events = append(events, Event{
+			Kind:         NewFinding,
+			ProjectID:    finding.ProjectID,
+			ProjectName:  finding.ProjectName,
+			ResourceID:   finding.ResourceID,
+			ResourceName: finding.ResourceName,
+			ResourceType: finding.ResourceType,
+			Rule:         finding.Rule,
+			Severity:     finding.Severity,
+			Message:      finding.Message,
+		})
+	}
+	return events
+}
+
+// routes reports whether the target receives the event
+func (t Target) routes(event Event) bool {
+	if len(t.Events) > 0 && !contains(t.Events, event.Kind) {
+		return false
+	}
+	if len(t.Projects) > 0 && !contains(t.Projects, event.ProjectName) && !contains(t.Projects, event.ProjectID) {
+		return false
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// allow applies the per-target hourly rate limit and records the message
+func (n *Notifier) allow(target Target, now time.Time) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if target.MaxPerHour <= 0 {
+		return true
+	}
+
+	var recent []time.Time
+	for _, sent := range n.sent[target.Name] {
+		if now.Sub(sent) < time.Hour {
+			recent = append(recent, sent)
+		}
+	}
+	if len(recent) >= target.MaxPerHour {
+		n.sent[target.Name] = recent
+		return false
+	}
+	n.sent[target.Name] = append(recent, now)
+	return true
+}
+
+// Notify sends events of the delta to every target that routes them, ctx
+// aborts deliveries still running
+func (n *Notifier) Notify(ctx context.Context, d *delta.Delta) []Delivery {
+	events := Events(d)
+	if len(events) == 0 {
+		return nil
+	}
+
+	var deliveries []Delivery
+	for _, target := range n.targets {
+		var routed []Event
+		for _, event := range events {
+			if target.routes(event) {
+				routed = append(routed, event)
+			}
+		}
+		if len(routed) == 0 {
+			continue
+		}
+
+		delivery := Delivery{Target: target.Name, Events: len(routed)}
+		if !n.allow(target, time.Now()) {
+			delivery.Skipped = fmt.Sprintf("rate limit of %d messages per hour reached", target.MaxPerHour)
+		} else if err := n.send(ctx, target, d, routed); err != nil {
+			delivery.Error = err.Error()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+// SendTest sends a test message to every target ignoring routing and rate limits
+func (n *Notifier) SendTest(ctx context.Context) []Delivery {
+	now := time.Now()
+	test := &delta.Delta{From: now, To: now}
+	events := []Event{{Kind: "test", Message: "Test notification from OpenStack Reporter"}}
+
+	var deliveries []Delivery
+	for _, target := range n.targets {
+		delivery := Delivery{Target: target.Name, Events: len(events)}
+		if err := n.send(ctx, target, test, events); err != nil {
+			delivery.Error = err.Error()
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+func (n *Notifier) send(ctx context.Context, target Target, d *delta.Delta, events []Event) error {
+	var payload interface{}
+	switch target.Type {
+	case TypeSlack, TypeMattermost:
+		chat := map[string]string{"text": formatText(target.Type, events, target.MaxEvents)}
+		if target.Channel != "" {
+			chat["channel"] = target.Channel
+		}
+		if target.Username != "" {
+			chat["username"] = target.Username
+		}
+		payload = chat
+	default:
+		truncated := 0
+		if len(events) > target.MaxEvents {
+			truncated = len(events) - target.MaxEvents
+			events = events[:target.MaxEvents]
+		}
+		payload = map[string]interface{}{
+			"source":    "openstack-reporter",
+			"from":      d.From,
+			"to":        d.To,
+			"events":    events,
+			"truncated": truncated,
+		}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range target.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver notification to %s: %w", target.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification to %s rejected with status %d", target.Name, resp.StatusCode)
+	}
+	return nil
+}
+
+// formatText renders events as a chat message, Slack uses *bold* and Mattermost **bold**.
+// The headline counts all events, at most maxEvents are listed.
+func formatText(targetType string, events []Event, maxEvents int) string {
+	bold := "**"
+	if targetType == TypeSlack {
+		bold = "*"
+	}
+
+	counts := make(map[string]int)
+	for _, event := range events {
+		counts[event.Kind]++
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%sOpenStack Reporter%s", bold, bold)
+	var parts []string
+	for _, kind := range []string{ResourceCreated, ResourceDeleted, StatusError, NewFinding} {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kindTitle(kind)))
+		}
+	}
+	if len(parts) > 0 {
+		sb.WriteString(": " + strings.Join(parts, ", "))
+	}
+	sb.WriteString("\n")
+
+	truncated := 0
+	if len(events) > maxEvents {
+		truncated = len(events) - maxEvents
+		events = events[:maxEvents]
+	}
+
+	for _, event := range events {
+		switch event.Kind {
+		case ResourceCreated:
+			fmt.Fprintf(&sb, "• [%s] %s %s created\n", event.ProjectName, event.ResourceType, event.ResourceName)
+		case ResourceDeleted:
+			fmt.Fprintf(&sb, "• [%s] %s %s deleted\n", event.ProjectName, event.ResourceType, event.ResourceName)
+		case StatusError:
+			fmt.Fprintf(&sb, "• [%s] %s %s: %s → %s%s%s\n", event.ProjectName, event.ResourceType, event.ResourceName, event.PrevStatus, bold, event.Status, bold)
+		case NewFinding:
+			fmt.Fprintf(&sb, "• [%s] %s: %s %s — %s\n", event.ProjectName, strings.ToUpper(event.Severity), event.ResourceType, event.ResourceName, event.Message)
+		default:
+			fmt.Fprintf(&sb, "• %s\n", event.Message)
+		}
+	}
+	if truncated > 0 {
+		fmt.Fprintf(&sb, "… and %d more\n", truncated)
+	}
+	return sb.String()
+}
+
+func kindTitle(kind string) string {
+	switch kind {
+	case ResourceCreated:
+		return "new"
+	case ResourceDeleted:
+		return "deleted"
+	case StatusError:
+		return "in ERROR"
+	case NewFinding:
+		return "new findings"
+	}
+	return kind
+}
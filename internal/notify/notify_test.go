@@ -0,0 +1,382 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"openstack-reporter/internal/delta"
+	"openstack-reporter/internal/models"
+	"openstack-reporter/internal/rules"
+)
+
+// request is a notification captured by a stub server
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// stub records every request it receives and answers with status
+type stub struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []request
+}
+
+func newStub(t *testing.T, status int) *stub {
+	t.Helper()
+	s := &stub{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.requests = append(s.requests, request{header: r.Header.Clone(), body: body})
+		s.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *stub) received() []request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]request(nil), s.requests...)
+}
+
+// fixtureDelta has a created server in web, a deleted volume in api, an
+// ERROR transition in web and a new finding in api
+func fixtureDelta() *delta.Delta {
+	return &delta.Delta{
+		From: time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC),
+		To:   time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+		Added: []models.Resource{
+			{ID: "srv-1", Name: "builder", Type: "server", Status: "ACTIVE", ProjectID: "p-web", ProjectName: "web"},
+		},
+		Removed: []models.Resource{
+			{ID: "vol-1", Name: "scratch", Type: "volume", Status: "available", ProjectID: "p-api", ProjectName: "api"},
+		},
+		StatusChanges: []delta.StatusChange{
+			{Resource: models.Resource{ID: "lb-1", Name: "edge", Type: "load_balancer", ProjectID: "p-web", ProjectName: "web"}, From: "ACTIVE", To: "ERROR"},
+			{Resource: models.Resource{ID: "srv-2", Name: "api", Type: "server", ProjectID: "p-api", ProjectName: "api"}, From: "ACTIVE", To: "SHUTOFF"},
+		},
+		NewFindings: []rules.Finding{
+			{Rule: "unattached-volumes", Severity: "info", Message: "volume is not attached", ResourceID: "vol-2", ResourceName: "data", ResourceType: "volume", ProjectID: "p-api", ProjectName: "api"},
+		},
+	}
+}
+
+func loadConfig(t *testing.T, content string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notifications.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	return config
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	server := newStub(t, http.StatusOK)
+	notifier := NewNotifier(loadConfig(t, `
+targets:
+  - name: hook
+    url: `+server.URL+`
+    headers:
+      Authorization: Bearer secret
+`))
+
+	deliveries := notifier.Notify(context.Background(), fixtureDelta())
+	if len(deliveries) != 1 || deliveries[0].Target != "hook" || deliveries[0].Events != 4 || deliveries[0].Error != "" {
+		t.Fatalf("deliveries = %+v", deliveries)
+	}
+
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	if got := requests[0].header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := requests[0].header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q", got)
+	}
+
+	var payload struct {
+		Source    string    `json:"source"`
+		From      time.Time `json:"from"`
+		Events    []Event   `json:"events"`
+		Truncated int       `json:"truncated"`
+	}
+	if err := json.Unmarshal(requests[0].body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Source != "openstack-reporter" || !payload.From.Equal(fixtureDelta().From) || payload.Truncated != 0 {
+		t.Errorf("payload = %+v", payload)
+	}
+	var kinds []string
+	for _, event := range payload.Events {
+		kinds = append(kinds, event.Kind+" "+event.ResourceID)
+	}
+	want := "resource_created srv-1,resource_deleted vol-1,status_error lb-1,new_finding vol-2"
+	if strings.Join(kinds, ",") != want {
+		t.Errorf("events = %v, want %s", kinds, want)
+	}
+}
+
+func TestWebhookTruncation(t *testing.T) {
+	server := newStub(t, http.StatusOK)
+	notifier := NewNotifier(loadConfig(t, "targets:\n  - url: "+server.URL+"\n    max_events: 1\n"))
+
+	notifier.Notify(context.Background(), fixtureDelta())
+
+	var payload struct {
+		Events    []Event `json:"events"`
+		Truncated int     `json:"truncated"`
+	}
+	requests := server.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+	if err := json.Unmarshal(requests[0].body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if len(payload.Events) != 1 || payload.Truncated != 3 {
+		t.Errorf("events = %d, truncated = %d, want 1 and 3", len(payload.Events), payload.Truncated)
+	}
+}
+
+func TestChatDelivery(t *testing.T) {
+	for _, kind := range []string{TypeSlack, TypeMattermost} {
+		server := newStub(t, http.StatusOK)
+		notifier := NewNotifier(loadConfig(t, `
+targets:
+  - name: chat
+    type: `+kind+`
+    url: `+server.URL+`
+    channel: "#ops"
+    username: reporter
+`))
+
+		deliveries := notifier.Notify(context.Background(), fixtureDelta())
+		if len(deliveries) != 1 || deliveries[0].Error != "" {
+			t.Fatalf("%s: deliveries = %+v", kind, deliveries)
+		}
+
+		requests := server.received()
+		if len(requests) != 1 {
+			t.Fatalf("%s: received %d requests, want 1", kind, len(requests))
+		}
+		var payload map[string]string
+		if err := json.Unmarshal(requests[0].body, &payload); err != nil {
+			t.Fatalf("%s: payload: %v", kind, err)
+		}
+		if payload["channel"] != "#ops" || payload["username"] != "reporter" {
+			t.Errorf("%s: payload = %v", kind, payload)
+		}
+
+		bold := "**"
+		if kind == TypeSlack {
+			bold = "*"
+		}
+		text := payload["text"]
+		for _, want := range []string{
+			bold + "OpenStack Reporter" + bold + ": 1 new, 1 deleted, 1 in ERROR, 1 new findings\n",
+			"• [web] server builder created\n",
+			"• [api] volume scratch deleted\n",
+			"• [web] load_balancer edge: ACTIVE → " + bold + "ERROR" + bold + "\n",
+			"• [api] INFO: volume data — volume is not attached\n",
+		} {
+			if !strings.Contains(text, want) {
+				t.Errorf("%s: text %q does not contain %q", kind, text, want)
+			}
+		}
+		if kind == TypeSlack && strings.Contains(text, "**") {
+			t.Errorf("slack: text %q uses Mattermost bold", text)
+		}
+	}
+}
+
+func TestRouting(t *testing.T) {
+	byName := newStub(t, http.StatusOK)
+	byID := newStub(t, http.StatusOK)
+	errors := newStub(t, http.StatusOK)
+	other := newStub(t, http.StatusOK)
+	notifier := NewNotifier(loadConfig(t, `
+targets:
+  - name: web
+    url: `+byName.URL+`
+    projects: [web]
+  - name: api
+    url: `+byID.URL+`
+    projects: [p-api]
+  - name: errors
+    url: `+errors.URL+`
+    events: [status_error]
+  - name: other
+    url: `+other.URL+`
+    projects: [infra]
+`))
+
+	deliveries := notifier.Notify(context.Background(), fixtureDelta())
+	got := make(map[string]int)
+	for _, delivery := range deliveries {
+		got[delivery.Target] = delivery.Events
+	}
+	if len(got) != 3 || got["web"] != 2 || got["api"] != 2 || got["errors"] != 1 {
+		t.Errorf("deliveries = %+v", deliveries)
+	}
+
+	routed := func(s *stub) []string {
+		var ids []string
+		for _, r := range s.received() {
+			var payload struct {
+				Events []Event `json:"events"`
+			}
+			if err := json.Unmarshal(r.body, &payload); err != nil {
+				t.Fatalf("payload: %v", err)
+			}
+			for _, event := range payload.Events {
+				ids = append(ids, event.ResourceID)
+			}
+		}
+		return ids
+	}
+	if ids := routed(byName); strings.Join(ids, ",") != "srv-1,lb-1" {
+		t.Errorf("web received %v", ids)
+	}
+	if ids := routed(byID); strings.Join(ids, ",") != "vol-1,vol-2" {
+		t.Errorf("api received %v", ids)
+	}
+	if ids := routed(errors); strings.Join(ids, ",") != "lb-1" {
+		t.Errorf("errors received %v", ids)
+	}
+	if n := len(other.received()); n != 0 {
+		t.Errorf("other received %d requests, want none", n)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := newStub(t, http.StatusOK)
+	notifier := NewNotifier(loadConfig(t, "targets:\n  - name: hook\n    url: "+server.URL+"\n    max_per_hour: 2\n"))
+
+	for i := 0; i < 3; i++ {
+		deliveries := notifier.Notify(context.Background(), fixtureDelta())
+		if len(deliveries) != 1 {
+			t.Fatalf("notify %d: deliveries = %+v", i+1, deliveries)
+		}
+		skipped := deliveries[0].Skipped
+		if i < 2 && skipped != "" {
+			t.Errorf("notify %d skipped: %s", i+1, skipped)
+		}
+		if i == 2 && skipped != "rate limit of 2 messages per hour reached" {
+			t.Errorf("notify %d: skipped = %q, want rate limit", i+1, skipped)
+		}
+	}
+	if n := len(server.received()); n != 2 {
+		t.Errorf("received %d requests, want 2", n)
+	}
+
+	// Messages older than an hour no longer count
+	if !notifier.allow(notifier.targets[0], time.Now().Add(time.Hour+time.Minute)) {
+		t.Error("rate limit still applied after an hour")
+	}
+}
+
+func TestDeliveryErrors(t *testing.T) {
+	server := newStub(t, http.StatusInternalServerError)
+	notifier := NewNotifier(loadConfig(t, "targets:\n  - name: hook\n    url: "+server.URL+"\n"))
+
+	deliveries := notifier.Notify(context.Background(), fixtureDelta())
+	if len(deliveries) != 1 || deliveries[0].Error != "notification to hook rejected with status 500" {
+		t.Errorf("deliveries = %+v", deliveries)
+	}
+
+	if deliveries := notifier.Notify(context.Background(), &delta.Delta{}); deliveries != nil {
+		t.Errorf("empty delta: deliveries = %+v", deliveries)
+	}
+}
+
+func TestNotifyCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	notifier := NewNotifier(loadConfig(t, "targets:\n  - name: hook\n    url: "+server.URL+"\n"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	deliveries := notifier.Notify(ctx, fixtureDelta())
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Notify returned after %v, the context was ignored", elapsed)
+	}
+	if len(deliveries) != 1 || !strings.Contains(deliveries[0].Error, "context deadline exceeded") {
+		t.Errorf("deliveries = %+v", deliveries)
+	}
+}
+
+func TestSendTest(t *testing.T) {
+	server := newStub(t, http.StatusOK)
+	notifier := NewNotifier(loadConfig(t, `
+targets:
+  - name: chat
+    type: mattermost
+    url: `+server.URL+`
+    projects: [infra]
+    max_per_hour: 1
+`))
+
+	for i := 0; i < 2; i++ {
+		deliveries := notifier.SendTest(context.Background())
+		if len(deliveries) != 1 || deliveries[0].Error != "" || deliveries[0].Skipped != "" {
+			t.Fatalf("send %d: deliveries = %+v", i+1, deliveries)
+		}
+	}
+	requests := server.received()
+	if len(requests) != 2 || !strings.Contains(string(requests[0].body), "Test notification from OpenStack Reporter") {
+		t.Errorf("requests = %d, first %s", len(requests), requests[0].body)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"targets:\n  - name: a\n", `notification target "a" has no url`},
+		{"targets:\n  - url: http://x\n    type: teams\n", `notification target "target-1": unknown type "teams"`},
+		{"targets:\n  - url: http://x\n    events: [resource_updated]\n", `unknown event "resource_updated"`},
+		{"targets: [", "failed to parse notification config"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "notifications.yaml")
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadConfig(path)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error %v, want %q", tt.content, err, tt.want)
+		}
+	}
+
+	config := loadConfig(t, "targets:\n  - url: http://x\n    type: Slack\n")
+	if target := config.Targets[0]; target.Name != "target-1" || target.Type != TypeSlack || target.MaxEvents != defaultMaxEvents {
+		t.Errorf("defaults = %+v", target)
+	}
+}
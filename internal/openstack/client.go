@@ -1,10 +1,10 @@
 package openstack
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"log/slog"
 	"os"
 	"os/exec"
 	"strings"
@@ -12,37 +12,43 @@ import (
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/extensions/volumetenants"
 	"github.com/gophercloud/gophercloud/openstack/blockstorage/v3/volumes"
-	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/compute/v2/flavors"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
 	"github.com/gophercloud/gophercloud/openstack/identity/v3/projects"
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
-	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/services"
-	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/siteconnections"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
-	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
 
+	"openstack-reporter/internal/ipam"
+	"openstack-reporter/internal/logging"
 	"openstack-reporter/internal/models"
 )
 
-// ProgressReporter interface for sending progress updates
+// ProgressReporter interface for sending progress updates, key and args
+// name an i18n message localized for the client that follows the progress
 type ProgressReporter interface {
-	SendProgress(msgType, message string, currentStep, totalSteps int, project, resourceType string, count int, summary map[string]int)
+	SendProgress(msgType, key string, currentStep, totalSteps int, project, resourceType string, count int, summary map[string]int, args ...interface{})
 }
 
-// ProgressMessage represents a progress update message
+// ProgressMessage represents a progress update message. Collection sets Key
+// and Args, the handler fills Message in the language of the client.
 type ProgressMessage struct {
-	Type        string         `json:"type"`
-	Message     string         `json:"message"`
-	CurrentStep int            `json:"current_step,omitempty"`
-	TotalSteps  int            `json:"total_steps,omitempty"`
-	Project     string         `json:"project,omitempty"`
-	ResourceType string        `json:"resource_type,omitempty"`
-	Count       int            `json:"count,omitempty"`
-	Summary     map[string]int `json:"summary,omitempty"`
+	Type         string         `json:"type"`
+	Message      string         `json:"message"`
+	Key          string         `json:"-"`
+	Args         []interface{}  `json:"-"`
+	CurrentStep  int            `json:"current_step,omitempty"`
+	TotalSteps   int            `json:"total_steps,omitempty"`
+	Project      string         `json:"project,omitempty"`
+	ResourceType string         `json:"resource_type,omitempty"`
+	Count        int            `json:"count,omitempty"`
+	Summary      map[string]int `json:"summary,omitempty"`
 }
 
 // ChannelProgressReporter implements ProgressReporter using channels
@@ -56,14 +62,15 @@ func NewChannelProgressReporter(progressChan chan ProgressMessage) *ChannelProgr
 	}
 }
 
-func (r *ChannelProgressReporter) SendProgress(msgType, message string, currentStep, totalSteps int, project, resourceType string, count int, summary map[string]int) {
+func (r *ChannelProgressReporter) SendProgress(msgType, key string, currentStep, totalSteps int, project, resourceType string, count int, summary map[string]int, args ...interface{}) {
 	if r.progressChan == nil {
 		return
 	}
 
 	progressMsg := ProgressMessage{
 		Type:         msgType,
-		Message:      message,
+		Key:          key,
+		Args:         args,
 		CurrentStep:  currentStep,
 		TotalSteps:   totalSteps,
 		Project:      project,
@@ -79,124 +86,157 @@ func (r *ChannelProgressReporter) SendProgress(msgType, message string, currentS
 	}
 }
 
-type Client struct {
-	provider         *gophercloud.ProviderClient
-	computeClient    *gophercloud.ServiceClient
-	blockstorageClient *gophercloud.ServiceClient
-	networkClient    *gophercloud.ServiceClient
-	identityClient   *gophercloud.ServiceClient
-	loadbalancerClient *gophercloud.ServiceClient
-	containerClient  *gophercloud.ServiceClient
+// loggingReporter logs collected resource counts and failures before
+// forwarding progress updates
+type loggingReporter struct {
+	reporter ProgressReporter
+	log      *slog.Logger
 }
 
-// NewClient creates a new OpenStack client
-func NewClient() (*Client, error) {
-	projectName := os.Getenv("OS_PROJECT_NAME")
-
-	// If no project specified, use a default project for initialization
-	// The actual multi-project logic will happen in GetAllResources()
-	if projectName == "" {
-		projectName = "infra" // Use a known project for initialization
-		fmt.Printf("DEBUG: No OS_PROJECT_NAME specified, using '%s' for client initialization\n", projectName)
+func (r *loggingReporter) SendProgress(msgType, key string, currentStep, totalSteps int, project, resourceType string, count int, summary map[string]int, args ...interface{}) {
+	switch msgType {
+	case "resource_complete":
+		r.log.Debug("Collected resources", "resource_type", resourceType, "count", count)
+	case "resource_error":
+		r.log.Warn("Failed to collect resources", "resource_type", resourceType, "error", fmt.Sprint(args...))
 	}
+	r.reporter.SendProgress(msgType, key, currentStep, totalSteps, project, resourceType, count, summary, args...)
+}
+
+type Client struct {
+	config              Config
+	provider            *gophercloud.ProviderClient
+	computeClient       *gophercloud.ServiceClient
+	blockstorageClient  *gophercloud.ServiceClient
+	networkClient       *gophercloud.ServiceClient
+	identityClient      *gophercloud.ServiceClient
+	loadbalancerClient  *gophercloud.ServiceClient
+	containerClient     *gophercloud.ServiceClient
+	objectStorageClient *gophercloud.ServiceClient
+	dnsClient           *gophercloud.ServiceClient
+	orchestrationClient *gophercloud.ServiceClient
+	log                 *slog.Logger
+
+	// project is the project of a per-project client, owners of its
+	// resources fall back to it
+	project *models.Project
+}
+
+// SetLogger sets the logger for collection, e.g. one carrying the refresh session ID
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.log = logger
+}
 
-	opts := gophercloud.AuthOptions{
-		IdentityEndpoint: os.Getenv("OS_AUTH_URL"),
-		Username:         os.Getenv("OS_USERNAME"),
-		Password:         os.Getenv("OS_PASSWORD"),
-		DomainName:       os.Getenv("OS_USER_DOMAIN_NAME"),
-		TenantName:       projectName,
+// logger returns the client logger, the default logger if none is set
+func (c *Client) logger() *slog.Logger {
+	if c.log == nil {
+		return slog.Default()
 	}
+	return c.log
+}
 
-	// Handle insecure connections
-	var provider *gophercloud.ProviderClient
-	var err error
+// NewClient creates a new OpenStack client, authentication is bound to ctx
+func NewClient(ctx context.Context, config Config) (*Client, error) {
+	projectName := config.ProjectName
 
-	if os.Getenv("OS_INSECURE") == "true" {
-		config := &tls.Config{InsecureSkipVerify: true}
-		transport := &http.Transport{TLSClientConfig: config}
-		client := &http.Client{Transport: transport}
-		provider, err = openstack.NewClient(opts.IdentityEndpoint)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create provider client: %w", err)
+	// If no project specified, use a default project for initialization
+	// The actual multi-project logic will happen in GetAllResources()
+	if config.allProjects() {
+		projectName = config.InitProject
+		if projectName == "" {
+			projectName = DefaultInitProject
 		}
-		provider.HTTPClient = *client
-		err = openstack.Authenticate(provider, opts)
-	} else {
-		provider, err = openstack.AuthenticatedClient(opts)
+		slog.Debug("No project specified, using init project for client initialization", "project", projectName)
 	}
 
+	opts := config.authOptions(projectName)
+
+	provider, err := config.authenticate(ctx, opts)
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 	}
 
-	computeClient, err := openstack.NewComputeV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	computeClient, err := openstack.NewComputeV2(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute client: %w", err)
 	}
 
-	blockstorageClient, err := openstack.NewBlockStorageV3(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	blockstorageClient, err := openstack.NewBlockStorageV3(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create block storage client: %w", err)
 	}
 
-	networkClient, err := openstack.NewNetworkV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	networkClient, err := openstack.NewNetworkV2(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network client: %w", err)
 	}
 
-	identityClient, err := openstack.NewIdentityV3(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	identityClient, err := openstack.NewIdentityV3(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create identity client: %w", err)
 	}
 
-	loadbalancerClient, err := openstack.NewLoadBalancerV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	loadbalancerClient, err := openstack.NewLoadBalancerV2(provider, config.endpointOpts())
 	if err != nil {
 		// Load balancer service might not be available
 		loadbalancerClient = nil
 	}
 
-	containerClient, err := openstack.NewContainerInfraV1(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	containerClient, err := openstack.NewContainerInfraV1(provider, config.endpointOpts())
 	if err != nil {
 		// Container service might not be available
 		containerClient = nil
 	}
 
+	objectStorageClient, err := openstack.NewObjectStorageV1(provider, config.endpointOpts())
+	if err != nil {
+		// Object storage service might not be available
+		objectStorageClient = nil
+	}
+
+	dnsClient, err := openstack.NewDNSV2(provider, config.endpointOpts())
+	if err != nil {
+		// DNS service might not be available
+		dnsClient = nil
+	}
+
+	orchestrationClient, err := openstack.NewOrchestrationV1(provider, config.endpointOpts())
+	if err != nil {
+		// Orchestration service might not be available
+		orchestrationClient = nil
+	}
+
 	return &Client{
-		provider:           provider,
-		computeClient:      computeClient,
-		blockstorageClient: blockstorageClient,
-		networkClient:      networkClient,
-		identityClient:     identityClient,
-		loadbalancerClient: loadbalancerClient,
-		containerClient:    containerClient,
+		config:              config,
+		provider:            provider,
+		computeClient:       computeClient,
+		blockstorageClient:  blockstorageClient,
+		networkClient:       networkClient,
+		identityClient:      identityClient,
+		loadbalancerClient:  loadbalancerClient,
+		containerClient:     containerClient,
+		objectStorageClient: objectStorageClient,
+		dnsClient:           dnsClient,
+		orchestrationClient: orchestrationClient,
 	}, nil
 }
 
-// GetAllResources fetches all resources from OpenStack
-func (c *Client) GetAllResources() (*models.ResourceReport, error) {
+// GetAllResources fetches all resources from OpenStack. Every API call is
+// bound to ctx, a cancelled collection returns an error rather than a
+// partial report.
+func (c *Client) GetAllResources(ctx context.Context) (*models.ResourceReport, error) {
+	c.provider.Context = ctx
 	report := &models.ResourceReport{
 		GeneratedAt: time.Now(),
 		Resources:   []models.Resource{},
 	}
 
 	// Check if user wants all projects or specific project
-	projectName := strings.TrimSpace(os.Getenv("OS_PROJECT_NAME"))
+	projectName := strings.TrimSpace(c.config.ProjectName)
 	if projectName != "" {
 		// Single project mode - use current client
-		fmt.Printf("DEBUG: Single project mode: %s\n", projectName)
+		c.logger().Info("Single project mode", "project", projectName)
 		currentProject, err := c.getCurrentProject()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get current project: %w", err)
@@ -208,19 +248,18 @@ func (c *Client) GetAllResources() (*models.ResourceReport, error) {
 		projectNames[currentProject.ID] = currentProject.Name
 
 		// Get resources for current project
-		return c.collectResourcesForProjects(report, projectNames)
+		return c.complete(c.collectResourcesForProjects(report, projectNames))
 	}
 
 	// Multi-project mode - get all projects via API with domain-scoped token
-	fmt.Printf("DEBUG: Multi-project mode - getting all accessible projects via API\n")
-	allProjects, err := c.getProjectsViaAPI()
+	c.logger().Debug("Multi-project mode, listing accessible projects via API")
+	allProjects, err := c.getProjectsViaAPI(ctx)
 	if err != nil {
-		fmt.Printf("DEBUG: API project list failed: %v\n", err)
 		// Fallback to CLI method
-		fmt.Printf("DEBUG: Trying CLI fallback...\n")
-		allProjects, err = getProjectsViaCommand()
+		c.logger().Warn("API project list failed, trying CLI fallback", "error", err)
+		allProjects, err = getProjectsViaCommand(ctx, c.config, c.logger())
 		if err != nil {
-			fmt.Printf("DEBUG: CLI project list also failed: %v\n", err)
+			c.logger().Warn("CLI project list failed, using current project only", "error", err)
 			// Final fallback to current project
 			currentProject, fallbackErr := c.getCurrentProject()
 			if fallbackErr != nil {
@@ -229,51 +268,56 @@ func (c *Client) GetAllResources() (*models.ResourceReport, error) {
 			report.Projects = []models.Project{currentProject}
 			projectNames := make(map[string]string)
 			projectNames[currentProject.ID] = currentProject.Name
-			return c.collectResourcesForProjects(report, projectNames)
+			return c.complete(c.collectResourcesForProjects(report, projectNames))
 		}
 	}
 
 	report.Projects = allProjects
-	fmt.Printf("DEBUG: Found %d projects, collecting resources from each\n", len(allProjects))
+	c.logger().Info("Collecting resources from projects", "projects", len(allProjects))
 
-	// Collect resources from each project separately
+	// Collect resources from each project separately, owners are resolved
+	// against every listed project
+	projectNames := make(map[string]string, len(allProjects))
+	for _, project := range allProjects {
+		projectNames[project.ID] = project.Name
+	}
 	var allResources []models.Resource
 	totalProjects := len(allProjects)
 
 	for i, project := range allProjects {
-		fmt.Printf("🔍 [%d/%d] Collecting resources from project: %s (%s)\n", i+1, totalProjects, project.Name, project.ID)
+		if err := aborted(ctx); err != nil {
+			return nil, err
+		}
+		projectLog := c.logger().With("project", project.Name, "project_id", project.ID)
+		projectLog.Debug("Collecting project resources", "step", i+1, "total_steps", totalProjects)
 
-		projectResources, err := getResourcesForProject(project)
+		projectResources, failures, err := getResourcesForProject(ctx, c.config, project, projectNames, projectLog)
 		if err != nil {
-			fmt.Printf("❌ Failed to get resources for project %s: %v\n", project.Name, err)
+			projectLog.Error("Failed to get project resources", "error", err)
+			report.Failures = append(report.Failures, models.CollectionFailure{ProjectID: project.ID, Error: err.Error()})
 			continue // Skip this project, continue with others
 		}
+		report.Failures = append(report.Failures, failures...)
 
-		fmt.Printf("✅ Found %d resources in project %s\n", len(projectResources), project.Name)
+		projectLog.Info("Collected project resources", "resources", len(projectResources))
 		allResources = append(allResources, projectResources...)
 	}
 
 	report.Resources = allResources
-	report.Summary = c.calculateSummary(report.Resources, len(report.Projects))
-
-	fmt.Printf("\n🎯 SUMMARY: Total %d resources collected from %d projects\n", len(allResources), len(allProjects))
-
-	// Show breakdown by resource type
-	typeCount := make(map[string]int)
-	for _, resource := range allResources {
-		typeCount[resource.Type]++
-	}
-
-	fmt.Printf("📊 Resource breakdown:\n")
-	for resourceType, count := range typeCount {
-		fmt.Printf("   - %s: %d\n", resourceType, count)
-	}
-
-	return report, nil
+	applySubnetUsage(report.Resources)
+	linkDNSRecords(report.Resources)
+	applyStackOwnership(report.Resources)
+	report.UnresolvedOwners = c.checkProjectOwners(report)
+	report.CalculateSummary()
+	c.logSummary(allResources, len(allProjects))
+
+	return c.complete(report, nil)
 }
 
-// GetAllResourcesWithProgress fetches all resources from OpenStack with progress updates
-func (c *Client) GetAllResourcesWithProgress(progressChan chan ProgressMessage) (*models.ResourceReport, error) {
+// GetAllResourcesWithProgress fetches all resources from OpenStack with
+// progress updates, bound to ctx like GetAllResources
+func (c *Client) GetAllResourcesWithProgress(ctx context.Context, progressChan chan ProgressMessage) (*models.ResourceReport, error) {
+	c.provider.Context = ctx
 	reporter := NewChannelProgressReporter(progressChan)
 
 	report := &models.ResourceReport{
@@ -282,10 +326,10 @@ func (c *Client) GetAllResourcesWithProgress(progressChan chan ProgressMessage)
 	}
 
 	// Check if user wants all projects or specific project
-	projectName := strings.TrimSpace(os.Getenv("OS_PROJECT_NAME"))
+	projectName := strings.TrimSpace(c.config.ProjectName)
 	if projectName != "" {
 		// Single project mode - use current client
-		reporter.SendProgress("progress", "Single project mode: "+projectName, 0, 0, "", "", 0, nil)
+		reporter.SendProgress("progress", "api.progress.single_project", 0, 0, "", "", 0, nil, projectName)
 		currentProject, err := c.getCurrentProject()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get current project: %w", err)
@@ -297,22 +341,20 @@ func (c *Client) GetAllResourcesWithProgress(progressChan chan ProgressMessage)
 		projectNames[currentProject.ID] = currentProject.Name
 
 		// Get resources for current project
-		return c.collectResourcesForProjectsWithProgress(report, projectNames, reporter)
+		return c.complete(c.collectResourcesForProjectsWithProgress(report, projectNames, reporter))
 	}
 
 	// Multi-project mode - get all projects via API with domain-scoped token
-	reporter.SendProgress("progress", "Multi-project mode - getting accessible projects", 0, 0, "", "", 0, nil)
-	fmt.Printf("DEBUG: Attempting to get projects via API...\n")
-	allProjects, err := c.getProjectsViaAPI()
-	if err != nil {
-		fmt.Printf("DEBUG: API project list failed: %v\n", err)
-		reporter.SendProgress("progress", "API project list failed, trying CLI fallback", 0, 0, "", "", 0, nil)
-		fmt.Printf("DEBUG: Attempting to get projects via CLI...\n")
-		allProjects, err = getProjectsViaCommand()
+	reporter.SendProgress("progress", "api.progress.multi_project", 0, 0, "", "", 0, nil)
+	c.logger().Debug("Multi-project mode, listing accessible projects via API")
+	allProjects, err := c.getProjectsViaAPI(ctx)
+	if err != nil {
+		c.logger().Warn("API project list failed, trying CLI fallback", "error", err)
+		reporter.SendProgress("progress", "api.progress.projects_api_failed", 0, 0, "", "", 0, nil)
+		allProjects, err = getProjectsViaCommand(ctx, c.config, c.logger())
 		if err != nil {
-			fmt.Printf("DEBUG: CLI project list failed: %v\n", err)
-			fmt.Printf("DEBUG: Using single project fallback mode\n")
-			reporter.SendProgress("progress", "CLI project list failed, using fallback", 0, 0, "", "", 0, nil)
+			c.logger().Warn("CLI project list failed, using current project only", "error", err)
+			reporter.SendProgress("progress", "api.progress.projects_cli_failed", 0, 0, "", "", 0, nil)
 			// Final fallback to current project
 			currentProject, fallbackErr := c.getCurrentProject()
 			if fallbackErr != nil {
@@ -321,51 +363,89 @@ func (c *Client) GetAllResourcesWithProgress(progressChan chan ProgressMessage)
 			report.Projects = []models.Project{currentProject}
 			projectNames := make(map[string]string)
 			projectNames[currentProject.ID] = currentProject.Name
-			return c.collectResourcesForProjectsWithProgress(report, projectNames, reporter)
+			return c.complete(c.collectResourcesForProjectsWithProgress(report, projectNames, reporter))
 		}
 	}
 
 	report.Projects = allProjects
-	fmt.Printf("DEBUG: Successfully found %d projects via API/CLI, entering true multi-project mode\n", len(allProjects))
-	reporter.SendProgress("progress", fmt.Sprintf("Found %d projects, starting resource collection", len(allProjects)), 0, len(allProjects), "", "", 0, nil)
+	c.logger().Info("Collecting resources from projects", "projects", len(allProjects))
+	reporter.SendProgress("progress", "api.progress.projects_found", 0, len(allProjects), "", "", 0, nil, len(allProjects))
 
-	// Collect resources from each project separately
+	// Collect resources from each project separately, owners are resolved
+	// against every listed project
+	projectNames := make(map[string]string, len(allProjects))
+	for _, project := range allProjects {
+		projectNames[project.ID] = project.Name
+	}
 	var allResources []models.Resource
 	totalProjects := len(allProjects)
 
 	for i, project := range allProjects {
-		reporter.SendProgress("project_start", fmt.Sprintf("Collecting resources from project: %s", project.Name), i+1, totalProjects, project.Name, "", 0, nil)
+		if err := aborted(ctx); err != nil {
+			return nil, err
+		}
+		projectLog := c.logger().With("project", project.Name, "project_id", project.ID)
+		projectLog.Debug("Collecting project resources", "step", i+1, "total_steps", totalProjects)
+		reporter.SendProgress("project_start", "api.progress.project_start", i+1, totalProjects, project.Name, "", 0, nil, project.Name)
 
-		projectResources, err := getResourcesForProjectWithProgress(project, reporter)
+		projectResources, failures, err := getResourcesForProjectWithProgress(ctx, c.config, project, projectNames, reporter, projectLog)
 		if err != nil {
-			reporter.SendProgress("project_error", fmt.Sprintf("Failed to get resources for project %s: %v", project.Name, err), i+1, totalProjects, project.Name, "", 0, nil)
+			projectLog.Error("Failed to get project resources", "error", err)
+			reporter.SendProgress("project_error", "api.progress.project_error", i+1, totalProjects, project.Name, "", 0, nil, project.Name, err)
+			report.Failures = append(report.Failures, models.CollectionFailure{ProjectID: project.ID, Error: err.Error()})
 			continue // Skip this project, continue with others
 		}
+		report.Failures = append(report.Failures, failures...)
 
-		reporter.SendProgress("project_complete", fmt.Sprintf("Found %d resources in project %s", len(projectResources), project.Name), i+1, totalProjects, project.Name, "", len(projectResources), nil)
+		projectLog.Info("Collected project resources", "resources", len(projectResources))
+		reporter.SendProgress("project_complete", "api.progress.project_complete", i+1, totalProjects, project.Name, "", len(projectResources), nil, len(projectResources), project.Name)
 		allResources = append(allResources, projectResources...)
 	}
 
 	report.Resources = allResources
-	report.Summary = c.calculateSummary(report.Resources, len(report.Projects))
+	applySubnetUsage(report.Resources)
+	linkDNSRecords(report.Resources)
+	applyStackOwnership(report.Resources)
+	report.UnresolvedOwners = c.checkProjectOwners(report)
+	report.CalculateSummary()
+	typeCount := c.logSummary(allResources, len(allProjects))
+	if err := aborted(ctx); err != nil {
+		return nil, err
+	}
 
 	// Send final summary
-	typeCount := make(map[string]int)
-	for _, resource := range allResources {
-		typeCount[resource.Type]++
-	}
 
-	reporter.SendProgress("summary", fmt.Sprintf("Total %d resources collected from %d projects", len(allResources), len(allProjects)), totalProjects, totalProjects, "", "", len(allResources), typeCount)
+	reporter.SendProgress("summary", "api.progress.summary", totalProjects, totalProjects, "", "", len(allResources), typeCount, len(allResources), len(allProjects))
+
+	return report, nil
+}
+
+// aborted returns an error once ctx is cancelled or its deadline passed
+func aborted(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("collection aborted: %w", err)
+	}
+	return nil
+}
 
+// complete discards the report of a collection whose context ended, failed
+// API calls are skipped during collection and would leave it incomplete
+func (c *Client) complete(report *models.ResourceReport, err error) (*models.ResourceReport, error) {
+	if err != nil {
+		return nil, err
+	}
+	if c.provider.Context != nil {
+		if err := aborted(c.provider.Context); err != nil {
+			return nil, err
+		}
+	}
 	return report, nil
 }
 
 func (c *Client) getAllProjects() ([]models.Project, error) {
 	// Try to list all projects the user has access to
-	fmt.Printf("DEBUG: Attempting to list all accessible projects...\n")
 	allPages, err := projects.List(c.identityClient, projects.ListOpts{}).AllPages()
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to list projects: %v\n", err)
 		return nil, fmt.Errorf("failed to list projects: %w", err)
 	}
 
@@ -386,23 +466,19 @@ func (c *Client) getAllProjects() ([]models.Project, error) {
 	}
 
 	if len(result) == 0 {
-		fmt.Printf("DEBUG: No projects found for user\n")
 		return nil, fmt.Errorf("no projects accessible to user")
 	}
 
-	fmt.Printf("DEBUG: Found %d accessible projects: ", len(result))
-	for i, project := range result {
-		if i > 0 {
-			fmt.Printf(", ")
-		}
-		fmt.Printf("%s (%s)", project.Name, project.ID)
-	}
-	fmt.Printf("\n")
+	c.logger().Debug("Listed accessible projects", "count", len(result), "projects", projectNameList(result))
 
 	return result, nil
 }
 
 func (c *Client) getCurrentProject() (models.Project, error) {
+	if c.project != nil {
+		return *c.project, nil
+	}
+
 	// Get current token to extract project info
 	authResult := c.provider.GetAuthResult()
 	if authResult == nil {
@@ -410,14 +486,14 @@ func (c *Client) getCurrentProject() (models.Project, error) {
 	}
 
 	// Use simple approach - get project from environment or use fallback
-	projectID := os.Getenv("OS_PROJECT_ID")
-	projectName := os.Getenv("OS_PROJECT_NAME")
+	projectID := c.config.ProjectID
+	projectName := c.config.ProjectName
 
 	if projectName == "" {
 		projectName = "Current Project"
 	}
 
-		if projectID == "" {
+	if projectID == "" {
 		projectID = "current-project"
 	}
 
@@ -450,21 +526,21 @@ func (c *Client) getServers(projectNames map[string]string) ([]models.Resource,
 
 	// Check if user wants all projects or specific project
 	var listOpts servers.ListOpts
-	projectName := strings.TrimSpace(os.Getenv("OS_PROJECT_NAME"))
+	projectName := strings.TrimSpace(c.config.ProjectName)
 	if projectName == "" {
 		// No specific project requested - get all accessible projects
-		fmt.Printf("DEBUG: No specific project set, using AllTenants=true\n")
+		c.logger().Debug("No specific project set, listing servers of all tenants")
 		listOpts = servers.ListOpts{AllTenants: true}
 	} else {
 		// Specific project requested - get only current project resources
-		fmt.Printf("DEBUG: Project '%s' specified, getting project-scoped resources\n", projectName)
+		c.logger().Debug("Listing project-scoped servers", "project", projectName)
 		listOpts = servers.ListOpts{}
 	}
 
 	allPages, err := servers.List(c.computeClient, listOpts).AllPages()
 	if err != nil && listOpts.AllTenants {
 		// Fallback to current tenant only if AllTenants fails
-		fmt.Printf("DEBUG: AllTenants failed, falling back to current project only\n")
+		c.logger().Debug("Listing servers of all tenants failed, falling back to current project only", "error", err)
 		listOpts = servers.ListOpts{}
 		allPages, err = servers.List(c.computeClient, listOpts).AllPages()
 	}
@@ -482,15 +558,10 @@ func (c *Client) getServers(projectNames map[string]string) ([]models.Resource,
 		created := server.Created
 		updated := server.Updated
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[server.TenantID]
-		projectID := server.TenantID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
-		}
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, server.TenantID, currentProject)
 
-				// Get detailed flavor information
+		// Get detailed flavor information
 		flavorName, flavorID := c.getFlavorDetails(server.Flavor)
 
 		resources = append(resources, models.Resource{
@@ -500,8 +571,9 @@ func (c *Client) getServers(projectNames map[string]string) ([]models.Resource,
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      server.Status,
-			CreatedAt:   created,
-			UpdatedAt:   updated,
+			Metadata:    mergeMetadata(server.Metadata, serverTags(server.Tags)),
+			CreatedAt:   models.OptionalTime(created),
+			UpdatedAt:   models.OptionalTime(updated),
 			Properties: models.Server{
 				ID:         server.ID,
 				Name:       server.Name,
@@ -518,26 +590,32 @@ func (c *Client) getServers(projectNames map[string]string) ([]models.Resource,
 	return resources, nil
 }
 
+// volumeWithTenant is a volume with its owning project attribute
+type volumeWithTenant struct {
+	volumes.Volume
+	volumetenants.VolumeTenantExt
+}
+
 func (c *Client) getVolumes(projectNames map[string]string) ([]models.Resource, error) {
 	currentProject, _ := c.getCurrentProject()
 
 	// Check if user wants all projects or specific project
 	var listOpts volumes.ListOpts
-	projectName := strings.TrimSpace(os.Getenv("OS_PROJECT_NAME"))
+	projectName := strings.TrimSpace(c.config.ProjectName)
 	if projectName == "" {
 		// No specific project requested - get all accessible projects
-		fmt.Printf("DEBUG: No specific project set, using AllTenants=true for volumes\n")
+		c.logger().Debug("No specific project set, listing volumes of all tenants")
 		listOpts = volumes.ListOpts{AllTenants: true}
 	} else {
 		// Specific project requested - get only current project resources
-		fmt.Printf("DEBUG: Project '%s' specified, getting project-scoped volumes\n", projectName)
+		c.logger().Debug("Listing project-scoped volumes", "project", projectName)
 		listOpts = volumes.ListOpts{}
 	}
 
 	allPages, err := volumes.List(c.blockstorageClient, listOpts).AllPages()
 	if err != nil && listOpts.AllTenants {
 		// Fallback to current tenant only if AllTenants fails
-		fmt.Printf("DEBUG: AllTenants failed for volumes, falling back to current project only\n")
+		c.logger().Debug("Listing volumes of all tenants failed, falling back to current project only", "error", err)
 		listOpts = volumes.ListOpts{}
 		allPages, err = volumes.List(c.blockstorageClient, listOpts).AllPages()
 	}
@@ -545,8 +623,9 @@ func (c *Client) getVolumes(projectNames map[string]string) ([]models.Resource,
 		return nil, err
 	}
 
-	volumeList, err := volumes.ExtractVolumes(allPages)
-	if err != nil {
+	// Volume owner is only exposed via os-vol-tenant-attr extension
+	var volumeList []volumeWithTenant
+	if err := volumes.ExtractVolumesInto(allPages, &volumeList); err != nil {
 		return nil, err
 	}
 
@@ -554,62 +633,8 @@ func (c *Client) getVolumes(projectNames map[string]string) ([]models.Resource,
 	for _, volume := range volumeList {
 		created := volume.CreatedAt
 
-		// Use current project as fallback when we can't determine the actual project
-		projectID := currentProject.ID
-		projectName := currentProject.Name
-
-		// Get detailed attachment information including server names
-		attachments := c.getVolumeAttachments(volume.Attachments)
-		attachedTo := ""
-		if len(attachments) > 0 {
-			attachedTo = attachments[0].ServerName
-		}
-
-		resources = append(resources, models.Resource{
-			ID:          volume.ID,
-			Name:        volume.Name,
-			Type:        "volume",
-			ProjectID:   projectID,
-			ProjectName: projectName,
-			Status:      volume.Status,
-			CreatedAt:   created,
-			Properties: models.Volume{
-				ID:          volume.ID,
-				Name:        volume.Name,
-				Status:      volume.Status,
-				Size:        volume.Size,
-				VolumeType:  volume.VolumeType,
-				Bootable:    volume.Bootable == "true",
-				Attachments: attachments,
-				AttachedTo:  attachedTo,
-				CreatedAt:   created,
-			},
-		})
-	}
-
-	return resources, nil
-}
-
-func (c *Client) getVolumesForProject(projectID, projectName string) ([]models.Resource, error) {
-	// Use TenantID filter to get volumes for specific project
-	listOpts := volumes.ListOpts{
-		AllTenants: true,
-		TenantID:   projectID,
-	}
-
-	allPages, err := volumes.List(c.blockstorageClient, listOpts).AllPages()
-	if err != nil {
-		return nil, err
-	}
-
-	volumeList, err := volumes.ExtractVolumes(allPages)
-	if err != nil {
-		return nil, err
-	}
-
-	var resources []models.Resource
-	for _, volume := range volumeList {
-		created := volume.CreatedAt
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, volume.TenantID, currentProject)
 
 		// Get detailed attachment information including server names
 		attachments := c.getVolumeAttachments(volume.Attachments)
@@ -625,7 +650,8 @@ func (c *Client) getVolumesForProject(projectID, projectName string) ([]models.R
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      volume.Status,
-			CreatedAt:   created,
+			Metadata:    volume.Metadata,
+			CreatedAt:   models.OptionalTime(created),
 			Properties: models.Volume{
 				ID:          volume.ID,
 				Name:        volume.Name,
@@ -640,7 +666,6 @@ func (c *Client) getVolumesForProject(projectID, projectName string) ([]models.R
 		})
 	}
 
-	fmt.Printf("DEBUG: Found %d volumes for project %s (%s)\n", len(resources), projectName, projectID)
 	return resources, nil
 }
 
@@ -662,13 +687,8 @@ func (c *Client) getFloatingIPs(projectNames map[string]string) ([]models.Resour
 		created := fip.CreatedAt
 		updated := fip.UpdatedAt
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[fip.TenantID]
-		projectID := fip.TenantID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
-		}
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, fip.TenantID, currentProject)
 
 		// Get attached resource name if floating IP is attached
 		attachedResourceName := c.getAttachedResourceName(fip.PortID)
@@ -680,8 +700,9 @@ func (c *Client) getFloatingIPs(projectNames map[string]string) ([]models.Resour
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      fip.Status,
-			CreatedAt:   created,
-			UpdatedAt:   updated,
+			Metadata:    tagsToMetadata(fip.Tags),
+			CreatedAt:   models.OptionalTime(created),
+			UpdatedAt:   models.OptionalTime(updated),
 			Properties: models.FloatingIP{
 				ID:                   fip.ID,
 				FloatingIP:           fip.FloatingIP,
@@ -712,18 +733,16 @@ func (c *Client) getRouters(projectNames map[string]string) ([]models.Resource,
 		return nil, err
 	}
 
+	// Router timestamps are not part of gophercloud model, read them from the raw response
+	timestamps := make(map[string]neutronTimestamps)
+	extractNeutronTimestamps(allPages.(routers.RouterPage), "routers", timestamps)
+
 	var resources []models.Resource
 	for _, router := range routerList {
-		created := time.Now() // Router API may not provide created time
-		updated := time.Now()
+		created, updated := timestamps[router.ID].parse()
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[router.TenantID]
-		projectID := router.TenantID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
-		}
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, router.TenantID, currentProject)
 
 		resources = append(resources, models.Resource{
 			ID:          router.ID,
@@ -732,6 +751,7 @@ func (c *Client) getRouters(projectNames map[string]string) ([]models.Resource,
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      router.Status,
+			Metadata:    tagsToMetadata(router.Tags),
 			CreatedAt:   created,
 			UpdatedAt:   updated,
 			Properties: models.Router{
@@ -768,13 +788,8 @@ func (c *Client) getNetworks(projectNames map[string]string) ([]models.Resource,
 		created := network.CreatedAt
 		updated := network.UpdatedAt
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[network.TenantID]
-		projectID := network.TenantID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
-		}
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, network.TenantID, currentProject)
 
 		// Get detailed subnet information
 		subnets := c.getSubnetsForNetwork(network.ID)
@@ -786,15 +801,16 @@ func (c *Client) getNetworks(projectNames map[string]string) ([]models.Resource,
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      network.Status,
-			CreatedAt:   created,
-			UpdatedAt:   updated,
+			Metadata:    tagsToMetadata(network.Tags),
+			CreatedAt:   models.OptionalTime(created),
+			UpdatedAt:   models.OptionalTime(updated),
 			Properties: models.Network{
 				ID:           network.ID,
 				Name:         network.Name,
 				Status:       network.Status,
 				AdminStateUp: network.AdminStateUp,
 				Shared:       network.Shared,
-				External:     false, // Default value, not available in basic Network struct
+				External:     false,   // Default value, not available in basic Network struct
 				NetworkType:  "local", // Default value, not available in basic Network struct
 				Subnets:      subnets,
 				CreatedAt:    created,
@@ -806,6 +822,83 @@ func (c *Client) getNetworks(projectNames map[string]string) ([]models.Resource,
 	return resources, nil
 }
 
+func (c *Client) getPorts(projectNames map[string]string) ([]models.Resource, error) {
+	// Get current project info for fallback
+	currentProject, _ := c.getCurrentProject()
+
+	// Admin tokens list ports of every project, per-project clients only
+	// collect their own so ports are not reported once per project
+	var listOpts ports.ListOpts
+	if c.project != nil {
+		listOpts.ProjectID = c.project.ID
+	}
+	allPages, err := ports.List(c.networkClient, listOpts).AllPages()
+	if err != nil {
+		return nil, err
+	}
+
+	portList, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return nil, err
+	}
+
+	securityGroupNames := c.getSecurityGroupNames()
+
+	var resources []models.Resource
+	for _, port := range portList {
+		created := port.CreatedAt
+		updated := port.UpdatedAt
+
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, port.TenantID, currentProject)
+
+		fixedIPs := make([]models.PortFixedIP, 0, len(port.FixedIPs))
+		for _, ip := range port.FixedIPs {
+			fixedIPs = append(fixedIPs, models.PortFixedIP{
+				SubnetID:  ip.SubnetID,
+				IPAddress: ip.IPAddress,
+			})
+		}
+
+		securityGroups := make([]string, 0, len(port.SecurityGroups))
+		for _, groupID := range port.SecurityGroups {
+			if name := securityGroupNames[groupID]; name != "" {
+				securityGroups = append(securityGroups, name)
+			} else {
+				securityGroups = append(securityGroups, groupID)
+			}
+		}
+
+		resources = append(resources, models.Resource{
+			ID:          port.ID,
+			Name:        port.Name,
+			Type:        "port",
+			ProjectID:   projectID,
+			ProjectName: projectName,
+			Status:      port.Status,
+			Metadata:    tagsToMetadata(port.Tags),
+			CreatedAt:   models.OptionalTime(created),
+			UpdatedAt:   models.OptionalTime(updated),
+			Properties: models.Port{
+				ID:             port.ID,
+				Name:           port.Name,
+				Status:         port.Status,
+				NetworkID:      port.NetworkID,
+				DeviceID:       port.DeviceID,
+				DeviceOwner:    port.DeviceOwner,
+				MACAddress:     port.MACAddress,
+				AdminStateUp:   port.AdminStateUp,
+				FixedIPs:       fixedIPs,
+				SecurityGroups: securityGroups,
+				CreatedAt:      created,
+				UpdatedAt:      updated,
+			},
+		})
+	}
+
+	return resources, nil
+}
+
 func (c *Client) getLoadBalancers(projectNames map[string]string) ([]models.Resource, error) {
 	if c.loadbalancerClient == nil {
 		return []models.Resource{}, nil
@@ -829,13 +922,8 @@ func (c *Client) getLoadBalancers(projectNames map[string]string) ([]models.Reso
 		created := lb.CreatedAt
 		updated := lb.UpdatedAt
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[lb.ProjectID]
-		projectID := lb.ProjectID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
-		}
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, lb.ProjectID, currentProject)
 
 		resources = append(resources, models.Resource{
 			ID:          lb.ID,
@@ -844,8 +932,9 @@ func (c *Client) getLoadBalancers(projectNames map[string]string) ([]models.Reso
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      lb.ProvisioningStatus,
-			CreatedAt:   created,
-			UpdatedAt:   updated,
+			Metadata:    tagsToMetadata(lb.Tags),
+			CreatedAt:   models.OptionalTime(created),
+			UpdatedAt:   models.OptionalTime(updated),
 			Properties: models.LoadBalancer{
 				ID:                 lb.ID,
 				Name:               lb.Name,
@@ -871,56 +960,169 @@ func (c *Client) getSubnetsForNetwork(networkID string) []models.Subnet {
 
 	allPages, err := subnets.List(c.networkClient, listOpts).AllPages()
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to get subnets for network %s: %v\n", networkID, err)
+		c.logger().Debug("Failed to list subnets", "network_id", networkID, "error", err)
 		return []models.Subnet{}
 	}
 
 	subnetList, err := subnets.ExtractSubnets(allPages)
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to extract subnets for network %s: %v\n", networkID, err)
+		c.logger().Debug("Failed to extract subnets", "network_id", networkID, "error", err)
 		return []models.Subnet{}
 	}
 
 	var result []models.Subnet
 	for _, subnet := range subnetList {
+		pools := make([]models.AllocationPool, 0, len(subnet.AllocationPools))
+		for _, pool := range subnet.AllocationPools {
+			pools = append(pools, models.AllocationPool{
+				Start: pool.Start,
+				End:   pool.End,
+			})
+		}
+
 		result = append(result, models.Subnet{
-			ID:        subnet.ID,
-			Name:      subnet.Name,
-			CIDR:      subnet.CIDR,
-			GatewayIP: subnet.GatewayIP,
+			ID:              subnet.ID,
+			Name:            subnet.Name,
+			CIDR:            subnet.CIDR,
+			GatewayIP:       subnet.GatewayIP,
+			IPVersion:       subnet.IPVersion,
+			AllocationPools: pools,
+			TotalIPs:        ipam.PoolSize(pools),
 		})
 	}
 
 	return result
 }
 
-func (c *Client) getVPNServices(projectNames map[string]string) ([]models.Resource, error) {
-	// Get current project info for fallback
-	currentProject, _ := c.getCurrentProject()
-	allPages, err := services.List(c.networkClient, services.ListOpts{}).AllPages()
+// resolveProject returns ID and name of the project owning a resource.
+// Owners missing from projectNames keep their ID, so they can be reported by checkProjectOwners.
+func resolveProject(projectNames map[string]string, ownerID string, currentProject models.Project) (string, string) {
+	if ownerID == "" {
+		return currentProject.ID, currentProject.Name
+	}
+	if name, exists := projectNames[ownerID]; exists && name != "" {
+		return ownerID, name
+	}
+	return ownerID, ownerID
+}
+
+// checkProjectOwners reports resources whose owning project is not part of the report
+func (c *Client) checkProjectOwners(report *models.ResourceReport) []models.UnresolvedOwner {
+	known := make(map[string]bool)
+	for _, project := range report.Projects {
+		known[project.ID] = true
+	}
+
+	issues := []models.UnresolvedOwner{}
+	for _, resource := range report.Resources {
+		if resource.ProjectID != "" && known[resource.ProjectID] {
+			continue
+		}
+		issues = append(issues, models.UnresolvedOwner{
+			ResourceID:   resource.ID,
+			ResourceName: resource.Name,
+			ResourceType: resource.Type,
+			ProjectID:    resource.ProjectID,
+		})
+	}
+
+	if len(issues) > 0 {
+		c.logger().Warn("Resources have an owning project that could not be resolved", "resources", len(issues))
+	}
+	return issues
+}
+
+// logSummary logs the collected resource count by type and returns it
+func (c *Client) logSummary(resources []models.Resource, projects int) map[string]int {
+	typeCount := make(map[string]int)
+	for _, resource := range resources {
+		typeCount[resource.Type]++
+	}
+
+	attrs := []any{"resources", len(resources), "projects", projects}
+	for resourceType, count := range typeCount {
+		attrs = append(attrs, slog.Int("type."+resourceType, count))
+	}
+	c.logger().Info("Resource collection finished", attrs...)
+	return typeCount
+}
+
+// projectNameList formats projects for debug logging
+func projectNameList(projects []models.Project) string {
+	names := make([]string, 0, len(projects))
+	for _, project := range projects {
+		names = append(names, fmt.Sprintf("%s (%s)", project.Name, project.ID))
+	}
+	return strings.Join(names, ", ")
+}
+
+// applySubnetUsage fills used IP counters of network subnets from collected ports
+func applySubnetUsage(resources []models.Resource) {
+	used := ipam.UsedIPsBySubnet(resources)
+	for _, resource := range resources {
+		network, ok := resource.Properties.(models.Network)
+		if !ok {
+			continue
+		}
+		// Subnets slice is shared with the stored properties value
+		for i := range network.Subnets {
+			subnet := &network.Subnets[i]
+			subnet.UsedIPs = ipam.CountUsed(used[subnet.ID], subnet.AllocationPools)
+			subnet.Utilization = ipam.Utilization(subnet.UsedIPs, subnet.TotalIPs)
+		}
+	}
+}
+
+// getSecurityGroupNames returns security group names by ID
+func (c *Client) getSecurityGroupNames() map[string]string {
+	names := make(map[string]string)
+
+	allPages, err := groups.List(c.networkClient, groups.ListOpts{}).AllPages()
 	if err != nil {
-		return []models.Resource{}, nil // VPN might not be available
+		c.logger().Debug("Failed to list security groups", "error", err)
+		return names
 	}
 
-	vpnList, err := services.ExtractServices(allPages)
+	groupList, err := groups.ExtractGroups(allPages)
 	if err != nil {
-		return []models.Resource{}, nil
+		c.logger().Debug("Failed to extract security groups", "error", err)
+		return names
 	}
 
-	var resources []models.Resource
-	for _, vpn := range vpnList {
-		created := time.Now() // VPN API may not provide created time
+	for _, group := range groupList {
+		names[group.ID] = group.Name
+	}
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[vpn.TenantID]
-		projectID := vpn.TenantID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
+	return names
+}
+
+func (c *Client) getVPNServices(projectNames map[string]string, inv *vpnInventory) []models.Resource {
+	// Get current project info for fallback
+	currentProject, _ := c.getCurrentProject()
+
+	var resources []models.Resource
+	for _, vpn := range inv.serviceList {
+		created, updated := inv.timestamps[vpn.ID].parse()
+
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, vpn.TenantID, currentProject)
+
+		// Collect peers from site connections attached to this service
+		var connectionIDs, peerIDs, peerAddresses []string
+		for _, conn := range inv.connectionsForService(vpn.ID) {
+			connectionIDs = append(connectionIDs, conn.ID)
+			if conn.PeerID != "" {
+				peerIDs = append(peerIDs, conn.PeerID)
+			}
+			if conn.PeerAddress != "" {
+				peerAddresses = append(peerAddresses, conn.PeerAddress)
+			}
 		}
 
-		// Get VPN peer ID if available
-		peerID := c.getVPNPeerID(vpn.ID)
+		externalIP := vpn.ExternalV4IP
+		if externalIP == "" {
+			externalIP = vpn.ExternalV6IP
+		}
 
 		resources = append(resources, models.Resource{
 			ID:          vpn.ID,
@@ -930,6 +1132,7 @@ func (c *Client) getVPNServices(projectNames map[string]string) ([]models.Resour
 			ProjectName: projectName,
 			Status:      vpn.Status,
 			CreatedAt:   created,
+			UpdatedAt:   updated,
 			Properties: models.VPNService{
 				ID:          vpn.ID,
 				Name:        vpn.Name,
@@ -937,41 +1140,29 @@ func (c *Client) getVPNServices(projectNames map[string]string) ([]models.Resour
 				Status:      vpn.Status,
 				RouterID:    vpn.RouterID,
 				SubnetID:    vpn.SubnetID,
-				PeerID:      peerID,
+				PeerID:      strings.Join(peerIDs, ", "),
+				PeerAddress: strings.Join(peerAddresses, ", "),
+				ExternalIP:  externalIP,
+				Connections: connectionIDs,
 				CreatedAt:   created,
+				UpdatedAt:   updated,
 			},
 		})
 	}
 
-	return resources, nil
+	return resources
 }
 
-func (c *Client) getVPNConnections(projectNames map[string]string) ([]models.Resource, error) {
+func (c *Client) getVPNConnections(projectNames map[string]string, inv *vpnInventory) []models.Resource {
 	// Get current project info for fallback
 	currentProject, _ := c.getCurrentProject()
 
-		// Get VPN IPSec site connections
-	allPages, err := siteconnections.List(c.networkClient, siteconnections.ListOpts{}).AllPages()
-	if err != nil {
-		return []models.Resource{}, fmt.Errorf("failed to list VPN site connections: %w", err)
-	}
-
-	connectionList, err := siteconnections.ExtractConnections(allPages)
-	if err != nil {
-		return []models.Resource{}, fmt.Errorf("failed to extract VPN site connections: %w", err)
-	}
-
 	var resources []models.Resource
-	for _, conn := range connectionList {
-		created := time.Now() // VPN Connection API may not provide created time
+	for _, conn := range inv.connections {
+		created, updated := inv.timestamps[conn.ID].parse()
 
-		// Get project name, fallback to current project if not found
-		projectName := projectNames[conn.TenantID]
-		projectID := conn.TenantID
-		if projectName == "" {
-			projectName = currentProject.Name
-			projectID = currentProject.ID
-		}
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, conn.TenantID, currentProject)
 
 		// Use connection name as VPN name
 		name := conn.Name
@@ -979,32 +1170,67 @@ func (c *Client) getVPNConnections(projectNames map[string]string) ([]models.Res
 			name = fmt.Sprintf("vpn-connection-%s", conn.ID[:8])
 		}
 
+		props := models.VPNConnection{
+			ID:           conn.ID,
+			Name:         name,
+			Description:  conn.Description,
+			Status:       conn.Status,
+			VPNServiceID: conn.VPNServiceID,
+			PeerID:       conn.PeerID,
+			PeerAddress:  conn.PeerAddress,
+			AuthMode:     conn.AuthMode,
+			Initiator:    conn.Initiator,
+			MTU:          conn.MTU,
+			PeerCIDRs:    conn.PeerCIDRs,
+			CreatedAt:    created,
+			UpdatedAt:    updated,
+		}
+
+		// Link to parent VPN service and its router
+		if service, exists := inv.services[conn.VPNServiceID]; exists {
+			props.VPNServiceName = service.Name
+			props.RouterID = service.RouterID
+			if service.SubnetID != "" {
+				if cidr := c.subnetCIDR(inv, service.SubnetID); cidr != "" {
+					props.LocalCIDRs = []string{cidr}
+				}
+			}
+		}
+
+		if policy, exists := inv.ikePolicies[conn.IKEPolicyID]; exists {
+			props.IKEPolicy = convertIKEPolicy(policy)
+			props.IKEVersion = policy.IKEVersion
+		}
+		if policy, exists := inv.ipsecPolicies[conn.IPSecPolicyID]; exists {
+			props.IPSecPolicy = convertIPSecPolicy(policy)
+		}
+
+		// Endpoint groups replace legacy service subnet and peer_cidrs
+		if group := c.endpointGroup(inv, conn.LocalEPGroupID); group != nil {
+			props.LocalEndpointGroup = group
+			props.LocalCIDRs = group.CIDRs
+		}
+		if group := c.endpointGroup(inv, conn.PeerEPGroupID); group != nil {
+			props.PeerEndpointGroup = group
+			props.PeerCIDRs = group.CIDRs
+		}
+
+		props.WeakCrypto = checkVPNCrypto(props.IKEPolicy, props.IPSecPolicy)
+
 		resources = append(resources, models.Resource{
 			ID:          conn.ID,
 			Name:        name,
-			Type:        "vpn_service",
+			Type:        "vpn_connection",
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      conn.Status,
 			CreatedAt:   created,
-			Properties: models.VPNService{
-				ID:              conn.ID,
-				Name:            name,
-				Description:     conn.Description,
-				Status:          conn.Status,
-				RouterID:        conn.VPNServiceID, // Link to parent VPN service
-				SubnetID:        "",                // Not available in site connection
-				PeerID:          conn.PeerID,
-				PeerAddress:     conn.PeerAddress,
-				AuthMode:        conn.AuthMode,
-				IKEVersion:      "",               // Available in IKE policy, not connection
-				MTU:             conn.MTU,
-				CreatedAt:       created,
-			},
+			UpdatedAt:   updated,
+			Properties:  props,
 		})
 	}
 
-	return resources, nil
+	return resources
 }
 
 func (c *Client) getClusters(projectNames map[string]string) ([]models.Resource, error) {
@@ -1017,35 +1243,6 @@ func (c *Client) getClusters(projectNames map[string]string) ([]models.Resource,
 	return []models.Resource{}, nil
 }
 
-func (c *Client) calculateSummary(resources []models.Resource, totalProjects int) models.Summary {
-	summary := models.Summary{
-		TotalProjects: totalProjects,
-	}
-
-	for _, resource := range resources {
-		switch resource.Type {
-		case "server":
-			summary.TotalServers++
-		case "volume":
-			summary.TotalVolumes++
-		case "load_balancer":
-			summary.TotalLoadBalancers++
-		case "floating_ip":
-			summary.TotalFloatingIPs++
-		case "vpn_service":
-			summary.TotalVPNServices++
-		case "cluster":
-			summary.TotalClusters++
-		case "router":
-			summary.TotalRouters++
-		case "network":
-			summary.TotalNetworks++
-		}
-	}
-
-	return summary
-}
-
 // Helper functions
 func extractNetworks(addresses interface{}) map[string]string {
 	networks := make(map[string]string)
@@ -1112,8 +1309,6 @@ func (c *Client) getFlavorDetails(flavorRef interface{}) (string, string) {
 	return flavor.Name, flavorID
 }
 
-
-
 // getVolumeAttachments gets detailed attachment information including server names
 func (c *Client) getVolumeAttachments(attachments interface{}) []models.VolumeAttachment {
 	var result []models.VolumeAttachment
@@ -1202,27 +1397,18 @@ func (c *Client) getAttachedResourceName(portID string) string {
 	return ""
 }
 
-// getVPNPeerID gets VPN peer ID from IPSec connections
-func (c *Client) getVPNPeerID(vpnServiceID string) string {
-	// This is a simplified approach - in real implementation you might want to
-	// look at IPSec connections or other VPN-related resources
-	// For now, return empty as this requires more complex VPN API calls
-	return ""
-}
-
 // getProjectsViaAPI gets project list using domain-scoped API token
-func (c *Client) getProjectsViaAPI() ([]models.Project, error) {
+func (c *Client) getProjectsViaAPI(ctx context.Context) ([]models.Project, error) {
 	// Create a domain-scoped client for project listing
-	domainClient, err := c.createDomainScopedClient()
+	domainClient, err := c.createDomainScopedClient(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create domain-scoped client: %w", err)
 	}
 
 	// List all projects in the domain
-	fmt.Printf("DEBUG: Attempting to list projects with domain-scoped token...\n")
+	c.logger().Debug("Listing projects with domain-scoped token")
 	allPages, err := projects.List(domainClient.identityClient, projects.ListOpts{}).AllPages()
 	if err != nil {
-		fmt.Printf("DEBUG: Failed to list projects via API: %v\n", err)
 		return nil, fmt.Errorf("failed to list projects via API: %w", err)
 	}
 
@@ -1242,60 +1428,34 @@ func (c *Client) getProjectsViaAPI() ([]models.Project, error) {
 		})
 	}
 
-	fmt.Printf("DEBUG: Found %d projects via API: ", len(result))
-	for i, project := range result {
-		if i > 0 {
-			fmt.Printf(", ")
-		}
-		fmt.Printf("%s", project.Name)
-	}
-	fmt.Printf("\n")
+	c.logger().Debug("Listed projects via API", "count", len(result), "projects", projectNameList(result))
 
 	return result, nil
 }
 
 // createDomainScopedClient creates a domain-scoped OpenStack client for project listing
-func (c *Client) createDomainScopedClient() (*Client, error) {
-	opts := gophercloud.AuthOptions{
-		IdentityEndpoint: os.Getenv("OS_AUTH_URL"),
-		Username:         os.Getenv("OS_USERNAME"),
-		Password:         os.Getenv("OS_PASSWORD"),
-		DomainName:       os.Getenv("OS_USER_DOMAIN_NAME"),
-		// No TenantName = domain-scoped token
-		Scope: &gophercloud.AuthScope{
-			DomainName: os.Getenv("OS_USER_DOMAIN_NAME"),
-		},
-	}
-
-	var provider *gophercloud.ProviderClient
-	var err error
-
-	if os.Getenv("OS_INSECURE") == "true" {
-		config := &tls.Config{InsecureSkipVerify: true}
-		transport := &http.Transport{TLSClientConfig: config}
-		client := &http.Client{Transport: transport}
-		provider, err = openstack.NewClient(opts.IdentityEndpoint)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create provider client: %w", err)
-		}
-		provider.HTTPClient = *client
-		err = openstack.Authenticate(provider, opts)
-	} else {
-		provider, err = openstack.AuthenticatedClient(opts)
+func (c *Client) createDomainScopedClient(ctx context.Context) (*Client, error) {
+	config := c.config
+	// No TenantName = domain-scoped token
+	opts := config.authOptions("")
+	opts.Scope = &gophercloud.AuthScope{
+		DomainName: config.UserDomainName,
 	}
 
+	provider, err := config.authenticate(ctx, opts)
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to create domain-scoped authenticated client: %w", err)
 	}
 
-	identityClient, err := openstack.NewIdentityV3(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	identityClient, err := openstack.NewIdentityV3(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create identity client: %w", err)
 	}
 
 	return &Client{
+		config:         config,
+		log:            c.log,
 		provider:       provider,
 		identityClient: identityClient,
 		// Only identity client needed for project listing
@@ -1303,26 +1463,27 @@ func (c *Client) createDomainScopedClient() (*Client, error) {
 }
 
 // getProjectsViaCommand gets project list using OpenStack CLI (fallback method)
-func getProjectsViaCommand() ([]models.Project, error) {
+func getProjectsViaCommand(ctx context.Context, config Config, log *slog.Logger) ([]models.Project, error) {
 	// Use openstack CLI to get project list (works even without identity:list_projects API permission)
-	cmd := exec.Command("openstack", "project", "list", "-f", "json")
+	cmd := exec.CommandContext(ctx, "openstack", "project", "list", "-f", "json")
 
-	// Set environment variables for the command
-	cmd.Env = os.Environ()
+	// Set environment variables for the command, configured credentials
+	// take precedence over the inherited environment
+	cmd.Env = append(os.Environ(), config.environ()...)
 
-	// Debug: print environment variables being passed to CLI
-	fmt.Printf("DEBUG: CLI environment variables:\n")
+	// Only names of the variables are logged, values include OS_PASSWORD
+	var names []string
 	for _, env := range cmd.Env {
-		if strings.HasPrefix(env, "OS_") {
-			fmt.Printf("  %s\n", env)
+		if name, _, _ := strings.Cut(env, "="); strings.HasPrefix(name, "OS_") {
+			names = append(names, name)
 		}
 	}
+	log.Debug("Listing projects via CLI", "variables", strings.Join(names, ","))
 
 	// Capture both stdout and stderr for better error diagnosis
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Printf("DEBUG: CLI command failed with output: %s\n", string(output))
-		return nil, fmt.Errorf("failed to execute 'openstack project list': %w (output: %s)", err, string(output))
+		return nil, fmt.Errorf("failed to execute 'openstack project list': %w (output: %s)", err, logging.Redact(strings.TrimSpace(string(output))))
 	}
 
 	// Parse JSON output
@@ -1350,352 +1511,519 @@ func getProjectsViaCommand() ([]models.Project, error) {
 	return result, nil
 }
 
-// getResourcesForProject creates a new client for specific project and gets its resources
-func getResourcesForProject(project models.Project) ([]models.Resource, error) {
+// collectorTypes lists the resource types reported by each collector
+var collectorTypes = map[string][]string{
+	"servers":           {"server"},
+	"volumes":           {"volume"},
+	"floating_ips":      {"floating_ip"},
+	"routers":           {"router"},
+	"networks":          {"network"},
+	"ports":             {"port"},
+	"load_balancers":    {"load_balancer"},
+	"vpn_connections":   {"vpn_service", "vpn_connection"},
+	"object_containers": {"object_container"},
+	"dns":               {"dns_zone", "dns_recordset"},
+	"stacks":            {"stack"},
+	"k8s_clusters":      {"cluster"},
+}
+
+// failedTypes returns a collection failure for every resource type of a
+// collector that failed, an empty projectID stands for every project
+func failedTypes(projectID, collector string, err error) []models.CollectionFailure {
+	var failures []models.CollectionFailure
+	for _, resourceType := range collectorTypes[collector] {
+		failures = append(failures, models.CollectionFailure{
+			ProjectID:    projectID,
+			ResourceType: resourceType,
+			Error:        err.Error(),
+		})
+	}
+	return failures
+}
+
+// getResourcesForProject creates a new client for specific project and gets
+// its resources, along with the resource types that failed to collect
+func getResourcesForProject(ctx context.Context, config Config, project models.Project, projectNames map[string]string, log *slog.Logger) ([]models.Resource, []models.CollectionFailure, error) {
 	// Create a new client specifically for this project
-	projectClient, err := createClientForProject(project.Name)
+	projectClient, err := createClientForProject(ctx, config, project)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client for project %s: %w", project.Name, err)
+		return nil, nil, fmt.Errorf("failed to create client for project %s: %w", project.Name, err)
 	}
+	projectClient.SetLogger(log)
 
 	var resources []models.Resource
-	projectNames := make(map[string]string)
-	projectNames[project.ID] = project.Name
+	var failures []models.CollectionFailure
 
-	// Get all resource types for this project with detailed logging
-	fmt.Printf("   📋 Collecting servers...")
+	// Get all resource types for this project
 	serverResources, err := projectClient.getServersForSingleProject(projectNames)
 	if err == nil {
 		resources = append(resources, serverResources...)
-		fmt.Printf(" %d found\n", len(serverResources))
+		log.Debug("Collected resources", "resource_type", "servers", "count", len(serverResources))
 	} else {
-		fmt.Printf(" failed: %v\n", err)
+		log.Warn("Failed to collect resources", "resource_type", "servers", "error", err)
+		failures = append(failures, failedTypes(project.ID, "servers", err)...)
 	}
 
-	fmt.Printf("   💾 Collecting volumes...")
 	volumeResources, err := projectClient.getVolumesForSingleProject(projectNames)
 	if err == nil {
 		resources = append(resources, volumeResources...)
-		fmt.Printf(" %d found\n", len(volumeResources))
+		log.Debug("Collected resources", "resource_type", "volumes", "count", len(volumeResources))
 	} else {
-		fmt.Printf(" failed: %v\n", err)
+		log.Warn("Failed to collect resources", "resource_type", "volumes", "error", err)
+		failures = append(failures, failedTypes(project.ID, "volumes", err)...)
 	}
 
-	fmt.Printf("   🌐 Collecting floating IPs...")
 	floatingIPResources, err := projectClient.getFloatingIPs(projectNames)
 	if err == nil {
 		resources = append(resources, floatingIPResources...)
-		fmt.Printf(" %d found\n", len(floatingIPResources))
+		log.Debug("Collected resources", "resource_type", "floating_ips", "count", len(floatingIPResources))
 	} else {
-		fmt.Printf(" failed: %v\n", err)
+		log.Warn("Failed to collect resources", "resource_type", "floating_ips", "error", err)
+		failures = append(failures, failedTypes(project.ID, "floating_ips", err)...)
 	}
 
-	fmt.Printf("   🔀 Collecting routers...")
 	routerResources, err := projectClient.getRouters(projectNames)
 	if err == nil {
 		resources = append(resources, routerResources...)
-		fmt.Printf(" %d found\n", len(routerResources))
+		log.Debug("Collected resources", "resource_type", "routers", "count", len(routerResources))
 	} else {
-		fmt.Printf(" failed: %v\n", err)
+		log.Warn("Failed to collect resources", "resource_type", "routers", "error", err)
+		failures = append(failures, failedTypes(project.ID, "routers", err)...)
 	}
 
-	fmt.Printf("   🌐 Collecting networks...")
 	networkResources, err := projectClient.getNetworks(projectNames)
 	if err == nil {
 		resources = append(resources, networkResources...)
-		fmt.Printf(" %d found\n", len(networkResources))
+		log.Debug("Collected resources", "resource_type", "networks", "count", len(networkResources))
 	} else {
-		fmt.Printf(" failed: %v\n", err)
+		log.Warn("Failed to collect resources", "resource_type", "networks", "error", err)
+		failures = append(failures, failedTypes(project.ID, "networks", err)...)
+	}
+
+	portResources, err := projectClient.getPorts(projectNames)
+	if err == nil {
+		resources = append(resources, portResources...)
+		log.Debug("Collected resources", "resource_type", "ports", "count", len(portResources))
+	} else {
+		log.Warn("Failed to collect resources", "resource_type", "ports", "error", err)
+		failures = append(failures, failedTypes(project.ID, "ports", err)...)
 	}
 
 	if projectClient.loadbalancerClient != nil {
-		fmt.Printf("   ⚖️  Collecting load balancers...")
 		lbResources, err := projectClient.getLoadBalancers(projectNames)
 		if err == nil {
 			resources = append(resources, lbResources...)
-			fmt.Printf(" %d found\n", len(lbResources))
+			log.Debug("Collected resources", "resource_type", "load_balancers", "count", len(lbResources))
 		} else {
-			fmt.Printf(" failed: %v\n", err)
+			log.Warn("Failed to collect resources", "resource_type", "load_balancers", "error", err)
+			failures = append(failures, failedTypes(project.ID, "load_balancers", err)...)
 		}
 	}
 
-	fmt.Printf("   🔒 Collecting VPN connections...")
-	vpnResources, err := projectClient.getVPNConnections(projectNames)
+	vpnResources, err := projectClient.getVPNResources(projectNames)
 	if err == nil {
 		resources = append(resources, vpnResources...)
-		fmt.Printf(" %d found\n", len(vpnResources))
+		log.Debug("Collected resources", "resource_type", "vpn_connections", "count", len(vpnResources))
 	} else {
-		fmt.Printf(" failed: %v\n", err)
+		log.Warn("Failed to collect resources", "resource_type", "vpn_connections", "error", err)
+		failures = append(failures, failedTypes(project.ID, "vpn_connections", err)...)
+	}
+
+	if projectClient.objectStorageClient != nil {
+		containerResources, err := projectClient.getObjectContainers(projectNames)
+		if err == nil {
+			resources = append(resources, containerResources...)
+			log.Debug("Collected resources", "resource_type", "object_containers", "count", len(containerResources))
+		} else {
+			log.Warn("Failed to collect resources", "resource_type", "object_containers", "error", err)
+			failures = append(failures, failedTypes(project.ID, "object_containers", err)...)
+		}
+	}
+
+	if projectClient.dnsClient != nil {
+		dnsResources, err := projectClient.getDNSResources(projectNames, false)
+		if err == nil {
+			resources = append(resources, dnsResources...)
+			log.Debug("Collected resources", "resource_type", "dns", "count", len(dnsResources))
+		} else {
+			log.Warn("Failed to collect resources", "resource_type", "dns", "error", err)
+			failures = append(failures, failedTypes(project.ID, "dns", err)...)
+		}
+	}
+
+	if projectClient.orchestrationClient != nil {
+		stackResources, err := projectClient.getStacks(projectNames, false)
+		if err == nil {
+			resources = append(resources, stackResources...)
+			log.Debug("Collected resources", "resource_type", "stacks", "count", len(stackResources))
+		} else {
+			log.Warn("Failed to collect resources", "resource_type", "stacks", "error", err)
+			failures = append(failures, failedTypes(project.ID, "stacks", err)...)
+		}
 	}
 
 	if projectClient.containerClient != nil {
-		fmt.Printf("   ☸️  Collecting K8s clusters...")
 		clusterResources, err := projectClient.getClusters(projectNames)
 		if err == nil {
 			resources = append(resources, clusterResources...)
-			fmt.Printf(" %d found\n", len(clusterResources))
+			log.Debug("Collected resources", "resource_type", "k8s_clusters", "count", len(clusterResources))
 		} else {
-			fmt.Printf(" failed: %v\n", err)
+			log.Warn("Failed to collect resources", "resource_type", "k8s_clusters", "error", err)
+			failures = append(failures, failedTypes(project.ID, "k8s_clusters", err)...)
 		}
 	}
 
-	return resources, nil
+	return resources, failures, nil
 }
 
-// getResourcesForProjectWithProgress creates a new client for specific project and gets its resources with progress
-func getResourcesForProjectWithProgress(project models.Project, reporter ProgressReporter) ([]models.Resource, error) {
+// getResourcesForProjectWithProgress creates a new client for specific project and gets its resources with progress,
+// along with the resource types that failed to collect
+func getResourcesForProjectWithProgress(ctx context.Context, config Config, project models.Project, projectNames map[string]string, reporter ProgressReporter, log *slog.Logger) ([]models.Resource, []models.CollectionFailure, error) {
 	// Create a new client specifically for this project
-	projectClient, err := createClientForProject(project.Name)
+	projectClient, err := createClientForProject(ctx, config, project)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create client for project %s: %w", project.Name, err)
+		return nil, nil, fmt.Errorf("failed to create client for project %s: %w", project.Name, err)
 	}
+	projectClient.SetLogger(log)
+	reporter = &loggingReporter{reporter: reporter, log: log}
 
 	var resources []models.Resource
-	projectNames := make(map[string]string)
-	projectNames[project.ID] = project.Name
+	var failures []models.CollectionFailure
 
 	// Get all resource types for this project with detailed progress reporting
-	reporter.SendProgress("resource_start", "Collecting servers", 0, 0, project.Name, "servers", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "servers", 0, nil)
 	serverResources, err := projectClient.getServersForSingleProject(projectNames)
 	if err == nil {
 		resources = append(resources, serverResources...)
-		reporter.SendProgress("resource_complete", "Servers collected", 0, 0, project.Name, "servers", len(serverResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "servers", len(serverResources), nil)
 	} else {
-		reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect servers: %v", err), 0, 0, project.Name, "servers", 0, nil)
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "servers", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "servers", err)...)
 	}
 
-	reporter.SendProgress("resource_start", "Collecting volumes", 0, 0, project.Name, "volumes", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "volumes", 0, nil)
 	volumeResources, err := projectClient.getVolumesForSingleProject(projectNames)
 	if err == nil {
 		resources = append(resources, volumeResources...)
-		reporter.SendProgress("resource_complete", "Volumes collected", 0, 0, project.Name, "volumes", len(volumeResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "volumes", len(volumeResources), nil)
 	} else {
-		reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect volumes: %v", err), 0, 0, project.Name, "volumes", 0, nil)
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "volumes", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "volumes", err)...)
 	}
 
-	reporter.SendProgress("resource_start", "Collecting floating IPs", 0, 0, project.Name, "floating_ips", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "floating_ips", 0, nil)
 	floatingIPResources, err := projectClient.getFloatingIPs(projectNames)
 	if err == nil {
 		resources = append(resources, floatingIPResources...)
-		reporter.SendProgress("resource_complete", "Floating IPs collected", 0, 0, project.Name, "floating_ips", len(floatingIPResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "floating_ips", len(floatingIPResources), nil)
 	} else {
-		reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect floating IPs: %v", err), 0, 0, project.Name, "floating_ips", 0, nil)
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "floating_ips", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "floating_ips", err)...)
 	}
 
-	reporter.SendProgress("resource_start", "Collecting routers", 0, 0, project.Name, "routers", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "routers", 0, nil)
 	routerResources, err := projectClient.getRouters(projectNames)
 	if err == nil {
 		resources = append(resources, routerResources...)
-		reporter.SendProgress("resource_complete", "Routers collected", 0, 0, project.Name, "routers", len(routerResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "routers", len(routerResources), nil)
 	} else {
-		reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect routers: %v", err), 0, 0, project.Name, "routers", 0, nil)
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "routers", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "routers", err)...)
 	}
 
-	reporter.SendProgress("resource_start", "Collecting networks", 0, 0, project.Name, "networks", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "networks", 0, nil)
 	networkResources, err := projectClient.getNetworks(projectNames)
 	if err == nil {
 		resources = append(resources, networkResources...)
-		reporter.SendProgress("resource_complete", "Networks collected", 0, 0, project.Name, "networks", len(networkResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "networks", len(networkResources), nil)
+	} else {
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "networks", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "networks", err)...)
+	}
+
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "ports", 0, nil)
+	portResources, err := projectClient.getPorts(projectNames)
+	if err == nil {
+		resources = append(resources, portResources...)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "ports", len(portResources), nil)
 	} else {
-		reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect networks: %v", err), 0, 0, project.Name, "networks", 0, nil)
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "ports", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "ports", err)...)
 	}
 
 	// Always send load balancer progress (even if client is nil)
-	reporter.SendProgress("resource_start", "Collecting load balancers", 0, 0, project.Name, "load_balancers", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "load_balancers", 0, nil)
 	if projectClient.loadbalancerClient != nil {
 		lbResources, err := projectClient.getLoadBalancers(projectNames)
 		if err == nil {
 			resources = append(resources, lbResources...)
-			reporter.SendProgress("resource_complete", "Load balancers collected", 0, 0, project.Name, "load_balancers", len(lbResources), nil)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "load_balancers", len(lbResources), nil)
 		} else {
-			reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect load balancers: %v", err), 0, 0, project.Name, "load_balancers", 0, nil)
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "load_balancers", 0, nil, err)
+			failures = append(failures, failedTypes(project.ID, "load_balancers", err)...)
 		}
 	} else {
-		reporter.SendProgress("resource_complete", "Load balancers collected", 0, 0, project.Name, "load_balancers", 0, nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "load_balancers", 0, nil)
 	}
 
-	reporter.SendProgress("resource_start", "Collecting VPN connections", 0, 0, project.Name, "vpn_connections", 0, nil)
-	vpnResources, err := projectClient.getVPNConnections(projectNames)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "vpn_connections", 0, nil)
+	vpnResources, err := projectClient.getVPNResources(projectNames)
 	if err == nil {
 		resources = append(resources, vpnResources...)
-		reporter.SendProgress("resource_complete", "VPN connections collected", 0, 0, project.Name, "vpn_connections", len(vpnResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "vpn_connections", len(vpnResources), nil)
+	} else {
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "vpn_connections", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "vpn_connections", err)...)
+	}
+
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "object_containers", 0, nil)
+	containerResources, err := projectClient.getObjectContainers(projectNames)
+	if err == nil {
+		resources = append(resources, containerResources...)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "object_containers", len(containerResources), nil)
+	} else {
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "object_containers", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "object_containers", err)...)
+	}
+
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "dns", 0, nil)
+	dnsResources, err := projectClient.getDNSResources(projectNames, false)
+	if err == nil {
+		resources = append(resources, dnsResources...)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "dns", len(dnsResources), nil)
+	} else {
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "dns", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "dns", err)...)
+	}
+
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "stacks", 0, nil)
+	stackResources, err := projectClient.getStacks(projectNames, false)
+	if err == nil {
+		resources = append(resources, stackResources...)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "stacks", len(stackResources), nil)
 	} else {
-		reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect VPN connections: %v", err), 0, 0, project.Name, "vpn_connections", 0, nil)
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "stacks", 0, nil, err)
+		failures = append(failures, failedTypes(project.ID, "stacks", err)...)
 	}
 
 	// Always send K8s clusters progress (even if client is nil)
-	reporter.SendProgress("resource_start", "Collecting K8s clusters", 0, 0, project.Name, "k8s_clusters", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, project.Name, "k8s_clusters", 0, nil)
 	if projectClient.containerClient != nil {
 		clusterResources, err := projectClient.getClusters(projectNames)
 		if err == nil {
 			resources = append(resources, clusterResources...)
-			reporter.SendProgress("resource_complete", "K8s clusters collected", 0, 0, project.Name, "k8s_clusters", len(clusterResources), nil)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "k8s_clusters", len(clusterResources), nil)
 		} else {
-			reporter.SendProgress("resource_error", fmt.Sprintf("Failed to collect K8s clusters: %v", err), 0, 0, project.Name, "k8s_clusters", 0, nil)
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, project.Name, "k8s_clusters", 0, nil, err)
+			failures = append(failures, failedTypes(project.ID, "k8s_clusters", err)...)
 		}
 	} else {
-		reporter.SendProgress("resource_complete", "K8s clusters collected", 0, 0, project.Name, "k8s_clusters", 0, nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, project.Name, "k8s_clusters", 0, nil)
 	}
 
-	return resources, nil
+	return resources, failures, nil
 }
 
 // collectResourcesForProjectsWithProgress collects resources using current client with progress (single project mode)
 func (c *Client) collectResourcesForProjectsWithProgress(report *models.ResourceReport, projectNames map[string]string, reporter ProgressReporter) (*models.ResourceReport, error) {
 	// Get all resource types with progress updates
-	reporter.SendProgress("resource_start", "Collecting servers", 0, 0, "", "servers", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "servers", 0, nil)
 	serverResources, err := c.getServers(projectNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get servers: %w", err)
 	}
 	report.Resources = append(report.Resources, serverResources...)
-	reporter.SendProgress("resource_complete", "Servers collected", 0, 0, "", "servers", len(serverResources), nil)
+	reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "servers", len(serverResources), nil)
 
-	reporter.SendProgress("resource_start", "Collecting volumes", 0, 0, "", "volumes", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "volumes", 0, nil)
 	volumeResources, err := c.getVolumes(projectNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get volumes: %w", err)
 	}
 	report.Resources = append(report.Resources, volumeResources...)
-	reporter.SendProgress("resource_complete", "Volumes collected", 0, 0, "", "volumes", len(volumeResources), nil)
+	reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "volumes", len(volumeResources), nil)
 
-	reporter.SendProgress("resource_start", "Collecting floating IPs", 0, 0, "", "floating_ips", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "floating_ips", 0, nil)
 	floatingIPResources, err := c.getFloatingIPs(projectNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get floating IPs: %w", err)
 	}
 	report.Resources = append(report.Resources, floatingIPResources...)
-	reporter.SendProgress("resource_complete", "Floating IPs collected", 0, 0, "", "floating_ips", len(floatingIPResources), nil)
+	reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "floating_ips", len(floatingIPResources), nil)
 
-	reporter.SendProgress("resource_start", "Collecting routers", 0, 0, "", "routers", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "routers", 0, nil)
 	routerResources, err := c.getRouters(projectNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get routers: %w", err)
 	}
 	report.Resources = append(report.Resources, routerResources...)
-	reporter.SendProgress("resource_complete", "Routers collected", 0, 0, "", "routers", len(routerResources), nil)
+	reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "routers", len(routerResources), nil)
 
-	reporter.SendProgress("resource_start", "Collecting networks", 0, 0, "", "networks", 0, nil)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "networks", 0, nil)
 	networkResources, err := c.getNetworks(projectNames)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get networks: %w", err)
 	}
 	report.Resources = append(report.Resources, networkResources...)
-	reporter.SendProgress("resource_complete", "Networks collected", 0, 0, "", "networks", len(networkResources), nil)
+	reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "networks", len(networkResources), nil)
+
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "ports", 0, nil)
+	portResources, err := c.getPorts(projectNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ports: %w", err)
+	}
+	report.Resources = append(report.Resources, portResources...)
+	reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "ports", len(portResources), nil)
 
 	// Optional services
 	if c.loadbalancerClient != nil {
-		reporter.SendProgress("resource_start", "Collecting load balancers", 0, 0, "", "load_balancers", 0, nil)
+		reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "load_balancers", 0, nil)
 		lbResources, err := c.getLoadBalancers(projectNames)
 		if err == nil {
 			report.Resources = append(report.Resources, lbResources...)
-			reporter.SendProgress("resource_complete", "Load balancers collected", 0, 0, "", "load_balancers", len(lbResources), nil)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "load_balancers", len(lbResources), nil)
+		} else {
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, "", "load_balancers", 0, nil, err)
+			report.Failures = append(report.Failures, failedTypes("", "load_balancers", err)...)
 		}
 	}
 
-	reporter.SendProgress("resource_start", "Collecting VPN connections", 0, 0, "", "vpn_connections", 0, nil)
-	vpnResources, err := c.getVPNConnections(projectNames)
+	reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "vpn_connections", 0, nil)
+	vpnResources, err := c.getVPNResources(projectNames)
 	if err == nil {
 		report.Resources = append(report.Resources, vpnResources...)
-		reporter.SendProgress("resource_complete", "VPN connections collected", 0, 0, "", "vpn_connections", len(vpnResources), nil)
+		reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "vpn_connections", len(vpnResources), nil)
+	} else {
+		reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, "", "vpn_connections", 0, nil, err)
+		report.Failures = append(report.Failures, failedTypes("", "vpn_connections", err)...)
+	}
+
+	if c.objectStorageClient != nil {
+		reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "object_containers", 0, nil)
+		containerResources, err := c.getObjectContainers(projectNames)
+		if err == nil {
+			report.Resources = append(report.Resources, containerResources...)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "object_containers", len(containerResources), nil)
+		} else {
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, "", "object_containers", 0, nil, err)
+			report.Failures = append(report.Failures, failedTypes("", "object_containers", err)...)
+		}
+	}
+
+	if c.dnsClient != nil {
+		reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "dns", 0, nil)
+		dnsResources, err := c.getDNSResources(projectNames, c.config.allProjects())
+		if err == nil {
+			report.Resources = append(report.Resources, dnsResources...)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "dns", len(dnsResources), nil)
+		} else {
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, "", "dns", 0, nil, err)
+			report.Failures = append(report.Failures, failedTypes("", "dns", err)...)
+		}
+	}
+
+	if c.orchestrationClient != nil {
+		reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "stacks", 0, nil)
+		stackResources, err := c.getStacks(projectNames, c.config.allProjects())
+		if err == nil {
+			report.Resources = append(report.Resources, stackResources...)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "stacks", len(stackResources), nil)
+		} else {
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, "", "stacks", 0, nil, err)
+			report.Failures = append(report.Failures, failedTypes("", "stacks", err)...)
+		}
 	}
 
 	if c.containerClient != nil {
-		reporter.SendProgress("resource_start", "Collecting K8s clusters", 0, 0, "", "k8s_clusters", 0, nil)
+		reporter.SendProgress("resource_start", "api.progress.resource_start", 0, 0, "", "k8s_clusters", 0, nil)
 		clusterResources, err := c.getClusters(projectNames)
 		if err == nil {
 			report.Resources = append(report.Resources, clusterResources...)
-			reporter.SendProgress("resource_complete", "K8s clusters collected", 0, 0, "", "k8s_clusters", len(clusterResources), nil)
+			reporter.SendProgress("resource_complete", "api.progress.resource_complete", 0, 0, "", "k8s_clusters", len(clusterResources), nil)
+		} else {
+			reporter.SendProgress("resource_error", "api.progress.resource_error", 0, 0, "", "k8s_clusters", 0, nil, err)
+			report.Failures = append(report.Failures, failedTypes("", "k8s_clusters", err)...)
 		}
 	}
 
 	// Calculate summary
-	report.Summary = c.calculateSummary(report.Resources, len(report.Projects))
+	applySubnetUsage(report.Resources)
+	linkDNSRecords(report.Resources)
+	applyStackOwnership(report.Resources)
+	report.UnresolvedOwners = c.checkProjectOwners(report)
+	report.CalculateSummary()
 
 	return report, nil
 }
 
 // createClientForProject creates a new OpenStack client for specific project
-func createClientForProject(projectName string) (*Client, error) {
-	opts := gophercloud.AuthOptions{
-		IdentityEndpoint: os.Getenv("OS_AUTH_URL"),
-		Username:         os.Getenv("OS_USERNAME"),
-		Password:         os.Getenv("OS_PASSWORD"),
-		DomainName:       os.Getenv("OS_USER_DOMAIN_NAME"),
-		TenantName:       projectName, // Use specific project name
-	}
-
-	var provider *gophercloud.ProviderClient
-	var err error
-
-	if os.Getenv("OS_INSECURE") == "true" {
-		config := &tls.Config{InsecureSkipVerify: true}
-		transport := &http.Transport{TLSClientConfig: config}
-		client := &http.Client{Transport: transport}
-		provider, err = openstack.NewClient(opts.IdentityEndpoint)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create provider client: %w", err)
-		}
-		provider.HTTPClient = *client
-		err = openstack.Authenticate(provider, opts)
-	} else {
-		provider, err = openstack.AuthenticatedClient(opts)
-	}
+func createClientForProject(ctx context.Context, config Config, project models.Project) (*Client, error) {
+	opts := config.authOptions(project.Name)
+
+	provider, err := config.authenticate(ctx, opts)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to create authenticated client for project %s: %w", projectName, err)
+		return nil, fmt.Errorf("failed to create authenticated client for project %s: %w", project.Name, err)
 	}
 
-	computeClient, err := openstack.NewComputeV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	computeClient, err := openstack.NewComputeV2(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create compute client: %w", err)
 	}
 
-	blockstorageClient, err := openstack.NewBlockStorageV3(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	blockstorageClient, err := openstack.NewBlockStorageV3(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create block storage client: %w", err)
 	}
 
-	networkClient, err := openstack.NewNetworkV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	networkClient, err := openstack.NewNetworkV2(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network client: %w", err)
 	}
 
-	identityClient, err := openstack.NewIdentityV3(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	identityClient, err := openstack.NewIdentityV3(provider, config.endpointOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create identity client: %w", err)
 	}
 
-	loadbalancerClient, err := openstack.NewLoadBalancerV2(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	loadbalancerClient, err := openstack.NewLoadBalancerV2(provider, config.endpointOpts())
 	if err != nil {
 		loadbalancerClient = nil
 	}
 
-	containerClient, err := openstack.NewContainerInfraV1(provider, gophercloud.EndpointOpts{
-		Region: os.Getenv("OS_REGION_NAME"),
-	})
+	containerClient, err := openstack.NewContainerInfraV1(provider, config.endpointOpts())
 	if err != nil {
 		containerClient = nil
 	}
 
+	objectStorageClient, err := openstack.NewObjectStorageV1(provider, config.endpointOpts())
+	if err != nil {
+		objectStorageClient = nil
+	}
+
+	dnsClient, err := openstack.NewDNSV2(provider, config.endpointOpts())
+	if err != nil {
+		dnsClient = nil
+	}
+
+	orchestrationClient, err := openstack.NewOrchestrationV1(provider, config.endpointOpts())
+	if err != nil {
+		orchestrationClient = nil
+	}
+
 	return &Client{
-		provider:           provider,
-		computeClient:      computeClient,
-		blockstorageClient: blockstorageClient,
-		networkClient:      networkClient,
-		identityClient:     identityClient,
-		loadbalancerClient: loadbalancerClient,
-		containerClient:    containerClient,
+		config:              config,
+		provider:            provider,
+		computeClient:       computeClient,
+		blockstorageClient:  blockstorageClient,
+		networkClient:       networkClient,
+		identityClient:      identityClient,
+		loadbalancerClient:  loadbalancerClient,
+		containerClient:     containerClient,
+		objectStorageClient: objectStorageClient,
+		dnsClient:           dnsClient,
+		orchestrationClient: orchestrationClient,
+		project:             &project,
 	}, nil
 }
 
@@ -1732,35 +2060,86 @@ func (c *Client) collectResourcesForProjects(report *models.ResourceReport, proj
 	}
 	report.Resources = append(report.Resources, networkResources...)
 
+	portResources, err := c.getPorts(projectNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ports: %w", err)
+	}
+	report.Resources = append(report.Resources, portResources...)
+
 	// Optional services
 	if c.loadbalancerClient != nil {
 		lbResources, err := c.getLoadBalancers(projectNames)
 		if err == nil {
 			report.Resources = append(report.Resources, lbResources...)
+		} else {
+			c.logger().Warn("Failed to collect resources", "resource_type", "load_balancers", "error", err)
+			report.Failures = append(report.Failures, failedTypes("", "load_balancers", err)...)
 		}
 	}
 
-	// Get VPN IPSec Site Connections (actual VPN tunnels with peer info)
-	vpnResources, err := c.getVPNConnections(projectNames)
+	// Get VPN services and IPSec site connections with their policies
+	vpnResources, err := c.getVPNResources(projectNames)
 	if err == nil {
 		report.Resources = append(report.Resources, vpnResources...)
+	} else {
+		c.logger().Warn("Failed to collect resources", "resource_type", "vpn_connections", "error", err)
+		report.Failures = append(report.Failures, failedTypes("", "vpn_connections", err)...)
+	}
+
+	if c.objectStorageClient != nil {
+		containerResources, err := c.getObjectContainers(projectNames)
+		if err == nil {
+			report.Resources = append(report.Resources, containerResources...)
+		} else {
+			c.logger().Warn("Failed to collect resources", "resource_type", "object_containers", "error", err)
+			report.Failures = append(report.Failures, failedTypes("", "object_containers", err)...)
+		}
+	}
+
+	if c.dnsClient != nil {
+		dnsResources, err := c.getDNSResources(projectNames, c.config.allProjects())
+		if err == nil {
+			report.Resources = append(report.Resources, dnsResources...)
+		} else {
+			c.logger().Warn("Failed to collect resources", "resource_type", "dns", "error", err)
+			report.Failures = append(report.Failures, failedTypes("", "dns", err)...)
+		}
+	}
+
+	if c.orchestrationClient != nil {
+		stackResources, err := c.getStacks(projectNames, c.config.allProjects())
+		if err == nil {
+			report.Resources = append(report.Resources, stackResources...)
+		} else {
+			c.logger().Warn("Failed to collect resources", "resource_type", "stacks", "error", err)
+			report.Failures = append(report.Failures, failedTypes("", "stacks", err)...)
+		}
 	}
 
 	if c.containerClient != nil {
 		clusterResources, err := c.getClusters(projectNames)
 		if err == nil {
 			report.Resources = append(report.Resources, clusterResources...)
+		} else {
+			c.logger().Warn("Failed to collect resources", "resource_type", "k8s_clusters", "error", err)
+			report.Failures = append(report.Failures, failedTypes("", "k8s_clusters", err)...)
 		}
 	}
 
 	// Calculate summary
-	report.Summary = c.calculateSummary(report.Resources, len(report.Projects))
+	applySubnetUsage(report.Resources)
+	linkDNSRecords(report.Resources)
+	applyStackOwnership(report.Resources)
+	report.UnresolvedOwners = c.checkProjectOwners(report)
+	report.CalculateSummary()
 
 	return report, nil
 }
 
 // getServersForSingleProject gets servers without AllTenants (for per-project clients)
 func (c *Client) getServersForSingleProject(projectNames map[string]string) ([]models.Resource, error) {
+	currentProject, _ := c.getCurrentProject()
+
 	// Always use project-scoped request (no AllTenants)
 	listOpts := servers.ListOpts{}
 	allPages, err := servers.List(c.computeClient, listOpts).AllPages()
@@ -1773,26 +2152,12 @@ func (c *Client) getServersForSingleProject(projectNames map[string]string) ([]m
 		return nil, err
 	}
 
-	// Get the project name from the first entry in projectNames map
-	var fallbackProjectName, fallbackProjectID string
-	for id, name := range projectNames {
-		fallbackProjectName = name
-		fallbackProjectID = id
-		break
-	}
-
 	var resources []models.Resource
 	for _, server := range serverList {
 		created := server.Created
 		updated := server.Updated
 
-		projectName := projectNames[server.TenantID]
-		projectID := server.TenantID
-		if projectName == "" {
-			// Use the project name from the scoped client, not "Current Project"
-			projectName = fallbackProjectName
-			projectID = fallbackProjectID
-		}
+		projectID, projectName := resolveProject(projectNames, server.TenantID, currentProject)
 
 		flavorName, flavorID := c.getFlavorDetails(server.Flavor)
 
@@ -1803,8 +2168,9 @@ func (c *Client) getServersForSingleProject(projectNames map[string]string) ([]m
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      server.Status,
-			CreatedAt:   created,
-			UpdatedAt:   updated,
+			Metadata:    mergeMetadata(server.Metadata, serverTags(server.Tags)),
+			CreatedAt:   models.OptionalTime(created),
+			UpdatedAt:   models.OptionalTime(updated),
 			Properties: models.Server{
 				ID:         server.ID,
 				Name:       server.Name,
@@ -1823,6 +2189,8 @@ func (c *Client) getServersForSingleProject(projectNames map[string]string) ([]m
 
 // getVolumesForSingleProject gets volumes without AllTenants (for per-project clients)
 func (c *Client) getVolumesForSingleProject(projectNames map[string]string) ([]models.Resource, error) {
+	currentProject, _ := c.getCurrentProject()
+
 	// Always use project-scoped request (no AllTenants)
 	listOpts := volumes.ListOpts{}
 	allPages, err := volumes.List(c.blockstorageClient, listOpts).AllPages()
@@ -1830,22 +2198,16 @@ func (c *Client) getVolumesForSingleProject(projectNames map[string]string) ([]m
 		return nil, err
 	}
 
-	volumeList, err := volumes.ExtractVolumes(allPages)
-	if err != nil {
+	// Volume owner is only exposed via os-vol-tenant-attr extension
+	var volumeList []volumeWithTenant
+	if err := volumes.ExtractVolumesInto(allPages, &volumeList); err != nil {
 		return nil, err
 	}
 
-	// Get the project name from the first entry in projectNames map
-	var projectID, projectName string
-	for id, name := range projectNames {
-		projectID = id
-		projectName = name
-		break
-	}
-
 	var resources []models.Resource
 	for _, volume := range volumeList {
 		created := volume.CreatedAt
+		projectID, projectName := resolveProject(projectNames, volume.TenantID, currentProject)
 
 		attachments := c.getVolumeAttachments(volume.Attachments)
 		attachedTo := ""
@@ -1860,7 +2222,8 @@ func (c *Client) getVolumesForSingleProject(projectNames map[string]string) ([]m
 			ProjectID:   projectID,
 			ProjectName: projectName,
 			Status:      volume.Status,
-			CreatedAt:   created,
+			Metadata:    volume.Metadata,
+			CreatedAt:   models.OptionalTime(created),
 			Properties: models.Volume{
 				ID:          volume.ID,
 				Name:        volume.Name,
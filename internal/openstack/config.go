@@ -0,0 +1,130 @@
+package openstack
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack"
+)
+
+// DefaultInitProject scopes the initial client when all projects are collected
+const DefaultInitProject = "infra"
+
+// DefaultRequestTimeout bounds a single OpenStack API call
+const DefaultRequestTimeout = 2 * time.Minute
+
+// Config holds OpenStack credentials and the scope of collection
+type Config struct {
+	AuthURL        string `yaml:"auth_url" json:"auth_url"`
+	Username       string `yaml:"username" json:"username"`
+	Password       string `yaml:"password" json:"password"`
+	UserDomainName string `yaml:"user_domain_name" json:"user_domain_name"`
+	// ProjectName limits collection to a single project, empty collects
+	// every project accessible to the user
+	ProjectName string `yaml:"project_name" json:"project_name"`
+	ProjectID   string `yaml:"project_id" json:"project_id"`
+	// InitProject scopes the initial client when ProjectName is empty
+	InitProject string `yaml:"init_project" json:"init_project"`
+	RegionName  string `yaml:"region_name" json:"region_name"`
+	// Insecure skips TLS verification
+	Insecure bool `yaml:"insecure" json:"insecure"`
+	// RequestTimeout bounds every API call, zero means no limit
+	RequestTimeout time.Duration `yaml:"request_timeout" json:"request_timeout"`
+}
+
+// allProjects reports whether resources of all accessible projects are collected
+func (c Config) allProjects() bool {
+	return strings.TrimSpace(c.ProjectName) == ""
+}
+
+// environ returns the configured settings as OS_* variables for the
+// openstack CLI
+func (c Config) environ() []string {
+	var env []string
+	for name, value := range map[string]string{
+		"OS_AUTH_URL":         c.AuthURL,
+		"OS_USERNAME":         c.Username,
+		"OS_PASSWORD":         c.Password,
+		"OS_USER_DOMAIN_NAME": c.UserDomainName,
+		"OS_PROJECT_NAME":     c.ProjectName,
+		"OS_PROJECT_ID":       c.ProjectID,
+		"OS_REGION_NAME":      c.RegionName,
+	} {
+		if value != "" {
+			env = append(env, name+"="+value)
+		}
+	}
+	if c.Insecure {
+		env = append(env, "OS_INSECURE=true")
+	}
+	return env
+}
+
+// authOptions returns password credentials scoped to the project, or
+// unscoped when projectName is empty
+func (c Config) authOptions(projectName string) gophercloud.AuthOptions {
+	return gophercloud.AuthOptions{
+		IdentityEndpoint: c.AuthURL,
+		Username:         c.Username,
+		Password:         c.Password,
+		DomainName:       c.UserDomainName,
+		TenantName:       projectName,
+	}
+}
+
+// endpointOpts selects service endpoints of the configured region
+func (c Config) endpointOpts() gophercloud.EndpointOpts {
+	return gophercloud.EndpointOpts{Region: c.RegionName}
+}
+
+// authenticate creates an authenticated provider client. Requests of the
+// client are bound to ctx and RequestTimeout, TLS verification is skipped
+// when Insecure is set.
+func (c Config) authenticate(ctx context.Context, opts gophercloud.AuthOptions) (*gophercloud.ProviderClient, error) {
+	provider, err := openstack.NewClient(opts.IdentityEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider client: %w", err)
+	}
+	provider.HTTPClient = c.httpClient()
+	provider.Context = ctx
+	return provider, openstack.Authenticate(provider, opts)
+}
+
+// httpClient applies RequestTimeout and skips TLS verification when Insecure is set
+func (c Config) httpClient() http.Client {
+	client := http.Client{Timeout: c.RequestTimeout}
+	if c.Insecure {
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+	}
+	return client
+}
+
+// CheckKeystone requests the identity endpoint without credentials, any
+// answer other than a server error counts as reachable
+func (c Config) CheckKeystone(ctx context.Context) (int, error) {
+	if c.AuthURL == "" {
+		return 0, fmt.Errorf("auth URL is not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.AuthURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invalid auth URL: %w", err)
+	}
+	client := c.httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("keystone is unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return resp.StatusCode, fmt.Errorf("keystone answered with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,185 @@
+package openstack
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/recordsets"
+	"github.com/gophercloud/gophercloud/openstack/dns/v2/zones"
+
+	"openstack-reporter/internal/models"
+)
+
+// getDNSResources collects Designate zones and their recordsets.
+// allProjects requests zones of every project, per-project clients must pass false.
+func (c *Client) getDNSResources(projectNames map[string]string, allProjects bool) ([]models.Resource, error) {
+	if c.dnsClient == nil {
+		return []models.Resource{}, nil
+	}
+
+	// Get current project info for fallback
+	currentProject, _ := c.getCurrentProject()
+
+	client := c.dnsClient
+	if allProjects {
+		// Ask Designate for zones of all projects (admin only)
+		allProjectsClient := *c.dnsClient
+		allProjectsClient.MoreHeaders = map[string]string{"X-Auth-All-Projects": "true"}
+		client = &allProjectsClient
+	}
+
+	allPages, err := zones.List(client, zones.ListOpts{}).AllPages()
+	if err != nil && client != c.dnsClient {
+		// Fallback to current project only if all projects listing is not permitted
+		client = c.dnsClient
+		allPages, err = zones.List(client, zones.ListOpts{}).AllPages()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DNS zones: %w", err)
+	}
+
+	zoneList, err := zones.ExtractZones(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract DNS zones: %w", err)
+	}
+
+	var resources []models.Resource
+	for _, zone := range zoneList {
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, zone.ProjectID, currentProject)
+
+		resources = append(resources, models.Resource{
+			ID:          zone.ID,
+			Name:        zone.Name,
+			Type:        "dns_zone",
+			ProjectID:   projectID,
+			ProjectName: projectName,
+			Status:      zone.Status,
+			CreatedAt:   models.OptionalTime(zone.CreatedAt),
+			UpdatedAt:   models.OptionalTime(zone.UpdatedAt),
+			Properties: models.DNSZone{
+				ID:          zone.ID,
+				Name:        zone.Name,
+				Email:       zone.Email,
+				Description: zone.Description,
+				Type:        zone.Type,
+				TTL:         zone.TTL,
+				Serial:      zone.Serial,
+				Status:      zone.Status,
+				CreatedAt:   zone.CreatedAt,
+				UpdatedAt:   zone.UpdatedAt,
+			},
+		})
+
+		records, err := listRecordSets(client, zone.ID)
+		if err != nil {
+			c.logger().Warn("Failed to list recordsets", "zone", zone.Name, "error", err)
+			continue
+		}
+
+		for _, record := range records {
+			// SOA and NS records are managed by Designate itself
+			if record.Type == "SOA" || record.Type == "NS" {
+				continue
+			}
+
+			resources = append(resources, models.Resource{
+				ID:          record.ID,
+				Name:        record.Name,
+				Type:        "dns_recordset",
+				ProjectID:   projectID,
+				ProjectName: projectName,
+				Status:      record.Status,
+				CreatedAt:   models.OptionalTime(record.CreatedAt),
+				UpdatedAt:   models.OptionalTime(record.UpdatedAt),
+				Properties: models.DNSRecordSet{
+					ID:          record.ID,
+					Name:        record.Name,
+					ZoneID:      record.ZoneID,
+					ZoneName:    record.ZoneName,
+					Type:        record.Type,
+					Records:     record.Records,
+					TTL:         record.TTL,
+					Status:      record.Status,
+					Description: record.Description,
+					CreatedAt:   record.CreatedAt,
+					UpdatedAt:   record.UpdatedAt,
+				},
+			})
+		}
+	}
+
+	return resources, nil
+}
+
+func listRecordSets(client *gophercloud.ServiceClient, zoneID string) ([]recordsets.RecordSet, error) {
+	allPages, err := recordsets.ListByZone(client, zoneID, recordsets.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, err
+	}
+	return recordsets.ExtractRecordSets(allPages)
+}
+
+// linkDNSRecords cross-links A/AAAA recordsets with floating IPs and flags
+// records pointing to addresses inside cloud subnets that are not allocated.
+// Such dangling records may allow subdomain takeover once the address is reused.
+func linkDNSRecords(resources []models.Resource) {
+	floatingIPs := make(map[string]int)
+	allocated := make(map[string]bool)
+	var prefixes []netip.Prefix
+
+	for i, resource := range resources {
+		switch props := resource.Properties.(type) {
+		case models.FloatingIP:
+			floatingIPs[props.FloatingIP] = i
+			allocated[props.FloatingIP] = true
+		case models.Port:
+			for _, ip := range props.FixedIPs {
+				allocated[ip.IPAddress] = true
+			}
+		case models.Network:
+			for _, subnet := range props.Subnets {
+				if prefix, err := netip.ParsePrefix(subnet.CIDR); err == nil {
+					prefixes = append(prefixes, prefix)
+				}
+			}
+		}
+	}
+
+	inCloud := func(address string) bool {
+		addr, err := netip.ParseAddr(address)
+		if err != nil {
+			return false
+		}
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for i := range resources {
+		record, ok := resources[i].Properties.(models.DNSRecordSet)
+		if !ok || (record.Type != "A" && record.Type != "AAAA") {
+			continue
+		}
+
+		record.FloatingIPs = nil
+		record.Dangling = nil
+		for _, address := range record.Records {
+			if index, exists := floatingIPs[address]; exists {
+				fip := resources[index].Properties.(models.FloatingIP)
+				record.FloatingIPs = append(record.FloatingIPs, fip.ID)
+				fip.DNSNames = append(fip.DNSNames, record.Name)
+				resources[index].Properties = fip
+				continue
+			}
+			if !allocated[address] && inCloud(address) {
+				record.Dangling = append(record.Dangling, address)
+			}
+		}
+		resources[i].Properties = record
+	}
+}
@@ -0,0 +1,139 @@
+package openstack
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/orchestration/v1/stackresources"
+	"github.com/gophercloud/gophercloud/openstack/orchestration/v1/stacks"
+
+	"openstack-reporter/internal/models"
+)
+
+// stackNestedDepth limits recursion into nested stacks when listing stack resources
+const stackNestedDepth = 5
+
+// getStacks collects Heat stacks together with resources they manage.
+// allProjects requests stacks of every project, per-project clients must pass false.
+func (c *Client) getStacks(projectNames map[string]string, allProjects bool) ([]models.Resource, error) {
+	if c.orchestrationClient == nil {
+		return []models.Resource{}, nil
+	}
+
+	// Get current project info for fallback
+	currentProject, _ := c.getCurrentProject()
+
+	listOpts := stacks.ListOpts{AllTenants: allProjects}
+	allPages, err := stacks.List(c.orchestrationClient, listOpts).AllPages()
+	if err != nil && listOpts.AllTenants {
+		// Fallback to current project only if global listing is not permitted
+		listOpts = stacks.ListOpts{}
+		allPages, err = stacks.List(c.orchestrationClient, listOpts).AllPages()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stacks: %w", err)
+	}
+
+	stackList, err := stacks.ExtractStacks(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract stacks: %w", err)
+	}
+
+	// Owning project is returned by Heat but not exposed by gophercloud
+	var owners struct {
+		Stacks []struct {
+			ID      string `json:"id"`
+			Project string `json:"project"`
+		} `json:"stacks"`
+	}
+	stackProjects := make(map[string]string)
+	if err := allPages.(stacks.StackPage).ExtractInto(&owners); err == nil {
+		for _, owner := range owners.Stacks {
+			stackProjects[owner.ID] = owner.Project
+		}
+	}
+
+	var resources []models.Resource
+	for _, stack := range stackList {
+		// Resolve owning project, fallback to current project if owner is not set
+		projectID, projectName := resolveProject(projectNames, stackProjects[stack.ID], currentProject)
+
+		props := models.Stack{
+			ID:           stack.ID,
+			Name:         stack.Name,
+			Description:  stack.Description,
+			Status:       stack.Status,
+			StatusReason: stack.StatusReason,
+			Tags:         stack.Tags,
+			CreatedAt:    stack.CreationTime,
+			UpdatedAt:    stack.UpdatedTime,
+		}
+
+		resourcePages, err := stackresources.List(c.orchestrationClient, stack.Name, stack.ID, stackresources.ListOpts{Depth: stackNestedDepth}).AllPages()
+		if err == nil {
+			if stackResources, err := stackresources.ExtractResources(resourcePages); err == nil {
+				for _, resource := range stackResources {
+					props.Resources = append(props.Resources, models.StackResource{
+						LogicalID:  resource.LogicalID,
+						PhysicalID: resource.PhysicalID,
+						Type:       resource.Type,
+						Status:     resource.Status,
+					})
+				}
+			}
+		} else {
+			c.logger().Warn("Failed to list stack resources", "stack", stack.Name, "error", err)
+		}
+
+		resources = append(resources, models.Resource{
+			ID:          stack.ID,
+			Name:        stack.Name,
+			Type:        "stack",
+			ProjectID:   projectID,
+			ProjectName: projectName,
+			Status:      stack.Status,
+			Metadata:    tagsToMetadata(stack.Tags),
+			CreatedAt:   models.OptionalTime(stack.CreationTime),
+			UpdatedAt:   models.OptionalTime(stack.UpdatedTime),
+			Properties:  props,
+		})
+	}
+
+	return resources, nil
+}
+
+// applyStackOwnership tags resources with the Heat stack that manages them.
+// Resources of nested stacks are attributed to the top-level stack.
+func applyStackOwnership(resources []models.Resource) {
+	type owner struct {
+		id   string
+		name string
+	}
+
+	owners := make(map[string]owner)
+	for _, resource := range resources {
+		stack, ok := resource.Properties.(models.Stack)
+		if !ok {
+			continue
+		}
+		for _, managed := range stack.Resources {
+			if managed.PhysicalID == "" {
+				continue
+			}
+			owners[managed.PhysicalID] = owner{id: stack.ID, name: stack.Name}
+			// Swift containers are identified by name inside the project account
+			if managed.Type == "OS::Swift::Container" {
+				owners[fmt.Sprintf("%s/%s", resource.ProjectID, managed.PhysicalID)] = owner{id: stack.ID, name: stack.Name}
+			}
+		}
+	}
+
+	for i := range resources {
+		if resources[i].Type == "stack" {
+			continue
+		}
+		if stack, exists := owners[resources[i].ID]; exists {
+			resources[i].StackID = stack.id
+			resources[i].StackName = stack.name
+		}
+	}
+}
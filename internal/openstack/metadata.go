@@ -0,0 +1,51 @@
+package openstack
+
+import "strings"
+
+// tagSeparators are accepted between key and value of a key/value tag
+const tagSeparators = "=:"
+
+// tagsToMetadata converts string tags (Neutron, Octavia, Nova, Heat) to metadata.
+// Tags like "owner=alice" or "env:prod" are split into key and value,
+// plain tags are kept as keys with an empty value.
+func tagsToMetadata(tags []string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	metadata := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if index := strings.IndexAny(tag, tagSeparators); index > 0 {
+			metadata[strings.TrimSpace(tag[:index])] = strings.TrimSpace(tag[index+1:])
+			continue
+		}
+		metadata[tag] = ""
+	}
+	return metadata
+}
+
+// mergeMetadata combines metadata maps, later maps override earlier keys
+func mergeMetadata(sources ...map[string]string) map[string]string {
+	var result map[string]string
+	for _, source := range sources {
+		for key, value := range source {
+			if result == nil {
+				result = make(map[string]string)
+			}
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// serverTags dereferences Nova server tags, available since microversion 2.26
+func serverTags(tags *[]string) map[string]string {
+	if tags == nil {
+		return nil
+	}
+	return tagsToMetadata(*tags)
+}
@@ -0,0 +1,83 @@
+package openstack
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+
+	"openstack-reporter/internal/models"
+)
+
+// getObjectContainers collects Swift containers of the current project account.
+// Swift has no all-tenants listing, so containers always belong to the scoped project.
+func (c *Client) getObjectContainers(projectNames map[string]string) ([]models.Resource, error) {
+	if c.objectStorageClient == nil {
+		return []models.Resource{}, nil
+	}
+
+	currentProject, _ := c.getCurrentProject()
+	projectID, projectName := resolveProject(projectNames, currentProject.ID, currentProject)
+
+	allPages, err := containers.List(c.objectStorageClient, containers.ListOpts{Full: true}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list object containers: %w", err)
+	}
+
+	containerList, err := containers.ExtractInfo(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract object containers: %w", err)
+	}
+
+	var resources []models.Resource
+	for _, container := range containerList {
+		props := models.ObjectContainer{
+			Name:        container.Name,
+			ObjectCount: container.Count,
+			Bytes:       container.Bytes,
+		}
+
+		// ACLs and creation time are only returned by container HEAD request
+		var created *time.Time
+		if header, err := containers.Get(c.objectStorageClient, container.Name, nil).Extract(); err == nil {
+			props.ReadACL = header.Read
+			props.WriteACL = header.Write
+			props.Public = isPublicReadACL(header.Read)
+			props.StoragePolicy = header.StoragePolicy
+			if header.Timestamp > 0 {
+				created = models.OptionalTime(time.Unix(int64(header.Timestamp), 0).UTC())
+			}
+		}
+
+		status := "PRIVATE"
+		if props.Public {
+			status = "PUBLIC"
+		}
+
+		resources = append(resources, models.Resource{
+			ID:          fmt.Sprintf("%s/%s", projectID, container.Name),
+			Name:        container.Name,
+			Type:        "object_container",
+			ProjectID:   projectID,
+			ProjectName: projectName,
+			Status:      status,
+			CreatedAt:   created,
+			Properties:  props,
+		})
+	}
+
+	return resources, nil
+}
+
+// isPublicReadACL reports whether a container read ACL grants anonymous access
+// via referrer rules such as ".r:*" or ".r:example.com"
+func isPublicReadACL(acl []string) bool {
+	for _, entry := range acl {
+		entry = strings.TrimSpace(entry)
+		if strings.HasPrefix(entry, ".r:") && !strings.HasPrefix(entry, ".r:-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package openstack
+
+import (
+	"time"
+
+	"openstack-reporter/internal/models"
+)
+
+// neutronTimestamps holds Neutron standard attributes that gophercloud
+// does not expose for some networking objects (routers, VPNaaS)
+type neutronTimestamps struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// neutronTimeLayouts lists timestamp formats returned by different Neutron releases
+var neutronTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// parse returns creation and update times, nil when Neutron did not report them
+func (t neutronTimestamps) parse() (*time.Time, *time.Time) {
+	return parseNeutronTime(t.CreatedAt), parseNeutronTime(t.UpdatedAt)
+}
+
+func parseNeutronTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	for _, layout := range neutronTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return models.OptionalTime(parsed)
+		}
+	}
+	return nil
+}
+
+// extractNeutronTimestamps reads timestamps of objects listed under label in a list response
+func extractNeutronTimestamps(page interface{ ExtractInto(interface{}) error }, label string, into map[string]neutronTimestamps) {
+	var body map[string][]neutronTimestamps
+	if err := page.ExtractInto(&body); err != nil {
+		return
+	}
+	for _, item := range body[label] {
+		into[item.ID] = item
+	}
+}
@@ -0,0 +1,231 @@
+package openstack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/endpointgroups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/ikepolicies"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/ipsecpolicies"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/services"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/vpnaas/siteconnections"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+
+	"openstack-reporter/internal/models"
+)
+
+// weakCryptoAlgorithms lists VPN algorithms and DH groups considered weak
+var weakCryptoAlgorithms = map[string]bool{
+	"3des":   true,
+	"sha1":   true,
+	"group2": true,
+}
+
+// vpnInventory holds all VPNaaS objects needed to link site connections
+// to their services, routers, policies and endpoint groups
+type vpnInventory struct {
+	services       map[string]services.Service
+	serviceList    []services.Service
+	ikePolicies    map[string]ikepolicies.Policy
+	ipsecPolicies  map[string]ipsecpolicies.Policy
+	endpointGroups map[string]endpointgroups.EndpointGroup
+	connections    []siteconnections.Connection
+	subnetCIDRs    map[string]string
+	timestamps     map[string]neutronTimestamps
+}
+
+// getVPNInventory lists VPN services, policies, endpoint groups and site connections
+func (c *Client) getVPNInventory() (*vpnInventory, error) {
+	inv := &vpnInventory{
+		services:       make(map[string]services.Service),
+		ikePolicies:    make(map[string]ikepolicies.Policy),
+		ipsecPolicies:  make(map[string]ipsecpolicies.Policy),
+		endpointGroups: make(map[string]endpointgroups.EndpointGroup),
+		subnetCIDRs:    make(map[string]string),
+		timestamps:     make(map[string]neutronTimestamps),
+	}
+
+	allPages, err := services.List(c.networkClient, services.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VPN services: %w", err)
+	}
+	inv.serviceList, err = services.ExtractServices(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract VPN services: %w", err)
+	}
+	for _, service := range inv.serviceList {
+		inv.services[service.ID] = service
+	}
+	extractNeutronTimestamps(allPages.(services.ServicePage), "vpnservices", inv.timestamps)
+
+	// Policies and endpoint groups are optional - older deployments may not expose them
+	if allPages, err := ikepolicies.List(c.networkClient, ikepolicies.ListOpts{}).AllPages(); err == nil {
+		if policyList, err := ikepolicies.ExtractPolicies(allPages); err == nil {
+			for _, policy := range policyList {
+				inv.ikePolicies[policy.ID] = policy
+			}
+		}
+	}
+
+	if allPages, err := ipsecpolicies.List(c.networkClient, ipsecpolicies.ListOpts{}).AllPages(); err == nil {
+		if policyList, err := ipsecpolicies.ExtractPolicies(allPages); err == nil {
+			for _, policy := range policyList {
+				inv.ipsecPolicies[policy.ID] = policy
+			}
+		}
+	}
+
+	if allPages, err := endpointgroups.List(c.networkClient, endpointgroups.ListOpts{}).AllPages(); err == nil {
+		if groupList, err := endpointgroups.ExtractEndpointGroups(allPages); err == nil {
+			for _, group := range groupList {
+				inv.endpointGroups[group.ID] = group
+			}
+		}
+	}
+
+	allPages, err = siteconnections.List(c.networkClient, siteconnections.ListOpts{}).AllPages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VPN site connections: %w", err)
+	}
+	inv.connections, err = siteconnections.ExtractConnections(allPages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract VPN site connections: %w", err)
+	}
+	extractNeutronTimestamps(allPages.(siteconnections.ConnectionPage), "ipsec_site_connections", inv.timestamps)
+
+	return inv, nil
+}
+
+// getVPNResources collects VPN services and site connections as report resources
+func (c *Client) getVPNResources(projectNames map[string]string) ([]models.Resource, error) {
+	inv, err := c.getVPNInventory()
+	if err != nil {
+		return []models.Resource{}, err
+	}
+
+	serviceResources := c.getVPNServices(projectNames, inv)
+	connectionResources := c.getVPNConnections(projectNames, inv)
+
+	return append(serviceResources, connectionResources...), nil
+}
+
+// connectionsForService returns site connections that belong to the VPN service
+func (inv *vpnInventory) connectionsForService(serviceID string) []siteconnections.Connection {
+	var result []siteconnections.Connection
+	for _, conn := range inv.connections {
+		if conn.VPNServiceID == serviceID {
+			result = append(result, conn)
+		}
+	}
+	return result
+}
+
+// endpointGroup converts an endpoint group and resolves subnet endpoints to CIDRs
+func (c *Client) endpointGroup(inv *vpnInventory, groupID string) *models.EndpointGroup {
+	if groupID == "" {
+		return nil
+	}
+
+	group, exists := inv.endpointGroups[groupID]
+	if !exists {
+		return &models.EndpointGroup{ID: groupID}
+	}
+
+	result := &models.EndpointGroup{
+		ID:        group.ID,
+		Name:      group.Name,
+		Type:      group.Type,
+		Endpoints: group.Endpoints,
+	}
+
+	switch group.Type {
+	case "cidr":
+		result.CIDRs = group.Endpoints
+	case "subnet":
+		for _, subnetID := range group.Endpoints {
+			if cidr := c.subnetCIDR(inv, subnetID); cidr != "" {
+				result.CIDRs = append(result.CIDRs, cidr)
+			}
+		}
+	}
+
+	return result
+}
+
+// subnetCIDR looks up subnet CIDR, caching results in the inventory
+func (c *Client) subnetCIDR(inv *vpnInventory, subnetID string) string {
+	if subnetID == "" {
+		return ""
+	}
+	if cidr, exists := inv.subnetCIDRs[subnetID]; exists {
+		return cidr
+	}
+
+	cidr := ""
+	if subnet, err := subnets.Get(c.networkClient, subnetID).Extract(); err == nil {
+		cidr = subnet.CIDR
+	}
+	inv.subnetCIDRs[subnetID] = cidr
+	return cidr
+}
+
+// convertIKEPolicy converts gophercloud IKE policy to model
+func convertIKEPolicy(policy ikepolicies.Policy) *models.IKEPolicy {
+	return &models.IKEPolicy{
+		ID:                  policy.ID,
+		Name:                policy.Name,
+		IKEVersion:          policy.IKEVersion,
+		EncryptionAlgorithm: policy.EncryptionAlgorithm,
+		AuthAlgorithm:       policy.AuthAlgorithm,
+		PFS:                 policy.PFS,
+		Lifetime:            formatLifetime(policy.Lifetime.Value, policy.Lifetime.Units),
+	}
+}
+
+// convertIPSecPolicy converts gophercloud IPsec policy to model
+func convertIPSecPolicy(policy ipsecpolicies.Policy) *models.IPSecPolicy {
+	return &models.IPSecPolicy{
+		ID:                  policy.ID,
+		Name:                policy.Name,
+		EncryptionAlgorithm: policy.EncryptionAlgorithm,
+		AuthAlgorithm:       policy.AuthAlgorithm,
+		PFS:                 policy.PFS,
+		TransformProtocol:   policy.TransformProtocol,
+		EncapsulationMode:   policy.EncapsulationMode,
+		Lifetime:            formatLifetime(policy.Lifetime.Value, policy.Lifetime.Units),
+	}
+}
+
+func formatLifetime(value int, units string) string {
+	if value == 0 {
+		return ""
+	}
+	if units == "" {
+		units = "seconds"
+	}
+	return fmt.Sprintf("%d %s", value, units)
+}
+
+// checkVPNCrypto returns human readable findings for weak IKE/IPsec algorithms
+func checkVPNCrypto(ike *models.IKEPolicy, ipsec *models.IPSecPolicy) []string {
+	var findings []string
+
+	check := func(phase, field, value string) {
+		if weakCryptoAlgorithms[strings.ToLower(value)] {
+			findings = append(findings, fmt.Sprintf("%s %s: %s", phase, field, value))
+		}
+	}
+
+	if ike != nil {
+		check("IKE", "encryption", ike.EncryptionAlgorithm)
+		check("IKE", "auth", ike.AuthAlgorithm)
+		check("IKE", "PFS", ike.PFS)
+	}
+	if ipsec != nil {
+		check("IPsec", "encryption", ipsec.EncryptionAlgorithm)
+		check("IPsec", "auth", ipsec.AuthAlgorithm)
+		check("IPsec", "PFS", ipsec.PFS)
+	}
+
+	return findings
+}
@@ -0,0 +1,178 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"openstack-reporter/internal/models"
+)
+
+// chartValue is a labelled value of a chart
+type chartValue struct {
+	label string
+	value int
+}
+
+// maxPieSlices limits pie chart slices, smaller values are merged into OTHER
+const maxPieSlices = 7
+
+// chartColors is the palette shared by all charts
+var chartColors = [][3]int{
+	{31, 119, 180}, {255, 127, 14}, {44, 160, 44}, {214, 39, 40},
+	{148, 103, 189}, {140, 86, 75}, {227, 119, 194}, {127, 127, 127},
+}
+
+// typeDistribution counts resources by type display name, largest first
+func (g *Generator) typeDistribution(doc *document, resources []models.Resource) []chartValue {
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[doc.typeName(resource.Type)]++
+	}
+	return sortedValues(counts)
+}
+
+// statusDistribution counts resources by upper-cased status, largest first
+func (g *Generator) statusDistribution(doc *document, resources []models.Resource) []chartValue {
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		status := strings.ToUpper(strings.TrimSpace(resource.Status))
+		if status == "" {
+			status = doc.t("pdf.chart.unknown")
+		}
+		counts[status]++
+	}
+
+	values := sortedValues(counts)
+	if len(values) > maxPieSlices {
+		other := chartValue{label: doc.t("pdf.chart.other")}
+		for _, value := range values[maxPieSlices-1:] {
+			other.value += value.value
+		}
+		values = append(values[:maxPieSlices-1], other)
+	}
+	return values
+}
+
+func sortedValues(counts map[string]int) []chartValue {
+	values := make([]chartValue, 0, len(counts))
+	for label, value := range counts {
+		values = append(values, chartValue{label: label, value: value})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].value != values[j].value {
+			return values[i].value > values[j].value
+		}
+		return values[i].label < values[j].label
+	})
+	return values
+}
+
+// addBarChart draws a horizontal bar chart with labels and values, title is a message key
+func (g *Generator) addBarChart(doc *document, title string, values []chartValue) {
+	if len(values) == 0 {
+		return
+	}
+
+	pdf := doc.pdf
+	const barHeight, labelWidth, valueWidth = 5.0, 45.0, 15.0
+	g.ensureSpace(doc, 10+float64(len(values))*(barHeight+1.5))
+
+	pdf.SetFont(fontFamily, "B", 10)
+	pdf.Cell(0, 6, doc.t(title))
+	pdf.Ln(8)
+
+	maxValue := values[0].value
+	for _, value := range values {
+		if value.value > maxValue {
+			maxValue = value.value
+		}
+	}
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	barWidth := pageWidth - left - right - labelWidth - valueWidth
+
+	pdf.SetFont(fontFamily, "", 8)
+	for i, value := range values {
+		y := pdf.GetY()
+		pdf.SetX(left)
+		pdf.CellFormat(labelWidth, barHeight, g.fitText(pdf, value.label, labelWidth-2), "", 0, "L", false, 0, "")
+
+		width := 0.0
+		if maxValue > 0 {
+			width = barWidth * float64(value.value) / float64(maxValue)
+		}
+		setFillColor(pdf, i)
+		pdf.Rect(left+labelWidth, y+0.5, math.Max(width, 0.5), barHeight-1, "F")
+
+		pdf.SetXY(left+labelWidth+width+1, y)
+		pdf.CellFormat(valueWidth, barHeight, fmt.Sprintf("%d", value.value), "", 0, "L", false, 0, "")
+		pdf.SetXY(left, y+barHeight+1.5)
+	}
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.Ln(6)
+}
+
+// addPieChart draws a pie chart with a legend to the right, title is a message key
+func (g *Generator) addPieChart(doc *document, title string, values []chartValue) {
+	total := 0
+	for _, value := range values {
+		total += value.value
+	}
+	if total == 0 {
+		return
+	}
+
+	pdf := doc.pdf
+	const radius = 28.0
+	g.ensureSpace(doc, 12+2*radius)
+
+	pdf.SetFont(fontFamily, "B", 10)
+	pdf.Cell(0, 6, doc.t(title))
+	pdf.Ln(8)
+
+	left := leftMargin(pdf)
+	top := pdf.GetY()
+	cx, cy := left+radius+5, top+radius
+
+	// Sectors are drawn as polygons, starting at 12 o'clock clockwise
+	angle := -math.Pi / 2
+	for i, value := range values {
+		sweep := 2 * math.Pi * float64(value.value) / float64(total)
+		points := []gofpdf.PointType{{X: cx, Y: cy}}
+		steps := int(math.Ceil(sweep/(math.Pi/90))) + 1
+		for step := 0; step <= steps; step++ {
+			a := angle + sweep*float64(step)/float64(steps)
+			points = append(points, gofpdf.PointType{X: cx + radius*math.Cos(a), Y: cy + radius*math.Sin(a)})
+		}
+		setFillColor(pdf, i)
+		pdf.SetDrawColor(255, 255, 255)
+		pdf.Polygon(points, "FD")
+		angle += sweep
+	}
+	pdf.SetDrawColor(0, 0, 0)
+
+	// Legend
+	legendX := cx + radius + 15
+	pdf.SetFont(fontFamily, "", 8)
+	for i, value := range values {
+		y := top + 4 + float64(i)*6
+		setFillColor(pdf, i)
+		pdf.Rect(legendX, y+1, 4, 4, "F")
+		pdf.SetXY(legendX+6, y)
+		pdf.CellFormat(80, 6, fmt.Sprintf("%s: %d (%.1f%%)", safeText(value.label), value.value, 100*float64(value.value)/float64(total)), "", 0, "L", false, 0, "")
+	}
+
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetXY(left, top+2*radius+6)
+}
+
+func setFillColor(pdf *gofpdf.Fpdf, index int) {
+	color := chartColors[index%len(chartColors)]
+	pdf.SetFillColor(color[0], color[1], color[2])
+}
@@ -0,0 +1,282 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"openstack-reporter/internal/models"
+)
+
+// typeTable describes detailed resource table columns of a resource type
+type typeTable struct {
+	columns []tableColumn
+	row     func(doc *document, resource models.Resource) []string
+}
+
+// typeTables defines type specific columns, widths add up to the A4 content width
+var typeTables = map[string]typeTable{
+	"server": {
+		columns: []tableColumn{
+			{"pdf.column.name", 55, "L"}, {"pdf.column.status", 22, "C"}, {"pdf.column.flavor", 33, "L"}, {"pdf.column.ip_addresses", 58, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var server models.Server
+			resource.DecodeProperties(&server)
+
+			networks := make([]string, 0, len(server.Networks))
+			for name, addresses := range server.Networks {
+				networks = append(networks, fmt.Sprintf("%s: %s", name, addresses))
+			}
+			sort.Strings(networks)
+
+			return []string{resourceName(doc, resource), resource.Status, server.FlavorName, strings.Join(networks, "\n"), createdDate(doc, resource)}
+		},
+	},
+	"volume": {
+		columns: []tableColumn{
+			{"pdf.column.name", 55, "L"}, {"pdf.column.status", 22, "C"}, {"pdf.column.size", 15, "R"}, {"pdf.column.type", 26, "L"}, {"pdf.column.attached_to", 50, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var volume models.Volume
+			resource.DecodeProperties(&volume)
+
+			var attached []string
+			for _, attachment := range volume.Attachments {
+				server := attachment.ServerName
+				if server == "" {
+					server = attachment.ServerID
+				}
+				if attachment.Device != "" {
+					server += " (" + attachment.Device + ")"
+				}
+				attached = append(attached, server)
+			}
+			if len(attached) == 0 && volume.AttachedTo != "" {
+				attached = append(attached, volume.AttachedTo)
+			}
+
+			return []string{resourceName(doc, resource), resource.Status, doc.t("pdf.size_gb", volume.Size), volume.VolumeType, strings.Join(attached, "\n"), createdDate(doc, resource)}
+		},
+	},
+	"load_balancer": {
+		columns: []tableColumn{
+			{"pdf.column.name", 70, "L"}, {"pdf.column.status", 25, "C"}, {"pdf.column.operating", 25, "C"}, {"pdf.column.vip", 48, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var lb models.LoadBalancer
+			resource.DecodeProperties(&lb)
+			return []string{resourceName(doc, resource), resource.Status, lb.OperatingStatus, lb.VipAddress, createdDate(doc, resource)}
+		},
+	},
+	"floating_ip": {
+		columns: []tableColumn{
+			{"pdf.column.address", 30, "L"}, {"pdf.column.status", 20, "C"}, {"pdf.column.fixed_ip", 28, "L"}, {"pdf.column.attached_to", 45, "L"}, {"pdf.column.dns_names", 45, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var fip models.FloatingIP
+			resource.DecodeProperties(&fip)
+
+			address := fip.FloatingIP
+			if address == "" {
+				address = resourceName(doc, resource)
+			}
+			return []string{address, resource.Status, fip.FixedIP, fip.AttachedResourceName, strings.Join(fip.DNSNames, "\n"), createdDate(doc, resource)}
+		},
+	},
+	"network": {
+		columns: []tableColumn{
+			{"pdf.column.name", 55, "L"}, {"pdf.column.status", 20, "C"}, {"pdf.column.subnets", 70, "L"}, {"pdf.column.flags", 23, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var network models.Network
+			resource.DecodeProperties(&network)
+
+			var subnets []string
+			for _, subnet := range network.Subnets {
+				line := subnet.CIDR
+				if subnet.Name != "" {
+					line = fmt.Sprintf("%s (%s)", subnet.CIDR, subnet.Name)
+				}
+				subnets = append(subnets, line)
+			}
+			if len(subnets) == 0 {
+				subnets = append(subnets, doc.t("pdf.no_subnets"))
+			}
+
+			var flags []string
+			if network.External {
+				flags = append(flags, doc.t("pdf.flag.external"))
+			}
+			if network.Shared {
+				flags = append(flags, doc.t("pdf.flag.shared"))
+			}
+
+			return []string{resourceName(doc, resource), resource.Status, strings.Join(subnets, "\n"), strings.Join(flags, ", "), createdDate(doc, resource)}
+		},
+	},
+	"port": {
+		columns: []tableColumn{
+			{"pdf.column.name", 40, "L"}, {"pdf.column.status", 18, "C"}, {"pdf.column.device_owner", 38, "L"}, {"pdf.column.fixed_ips", 40, "L"}, {"pdf.column.mac", 32, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var port models.Port
+			resource.DecodeProperties(&port)
+
+			var addresses []string
+			for _, fixedIP := range port.FixedIPs {
+				addresses = append(addresses, fixedIP.IPAddress)
+			}
+			return []string{resourceName(doc, resource), resource.Status, port.DeviceOwner, strings.Join(addresses, "\n"), port.MACAddress, createdDate(doc, resource)}
+		},
+	},
+	"router": {
+		columns: []tableColumn{
+			{"pdf.column.name", 70, "L"}, {"pdf.column.status", 25, "C"}, {"pdf.column.external_ips", 73, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var router models.Router
+			resource.DecodeProperties(&router)
+			return []string{resourceName(doc, resource), resource.Status, strings.Join(routerExternalIPs(router), "\n"), createdDate(doc, resource)}
+		},
+	},
+	"object_container": {
+		columns: []tableColumn{
+			{"pdf.column.name", 70, "L"}, {"pdf.column.access", 22, "C"}, {"pdf.column.objects", 25, "R"}, {"pdf.column.size", 28, "R"}, {"pdf.column.policy", 45, "L"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var container models.ObjectContainer
+			resource.DecodeProperties(&container)
+
+			access := doc.t("pdf.access.private")
+			if container.Public {
+				access = doc.t("pdf.access.public")
+			}
+			return []string{resourceName(doc, resource), access, strconv.FormatInt(container.ObjectCount, 10), formatBytes(container.Bytes), container.StoragePolicy}
+		},
+	},
+	"dns_zone": {
+		columns: []tableColumn{
+			{"pdf.column.name", 70, "L"}, {"pdf.column.status", 22, "C"}, {"pdf.column.type", 22, "C"}, {"pdf.column.email", 54, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var zone models.DNSZone
+			resource.DecodeProperties(&zone)
+			return []string{resourceName(doc, resource), resource.Status, zone.Type, zone.Email, createdDate(doc, resource)}
+		},
+	},
+	"dns_recordset": {
+		columns: []tableColumn{
+			{"pdf.column.name", 60, "L"}, {"pdf.column.status", 20, "C"}, {"pdf.column.type", 15, "C"}, {"pdf.column.records", 73, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var record models.DNSRecordSet
+			resource.DecodeProperties(&record)
+			return []string{resourceName(doc, resource), resource.Status, record.Type, strings.Join(record.Records, "\n"), createdDate(doc, resource)}
+		},
+	},
+	"stack": {
+		columns: []tableColumn{
+			{"pdf.column.name", 55, "L"}, {"pdf.column.status", 35, "C"}, {"pdf.column.resources", 20, "R"}, {"pdf.column.status_reason", 58, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var stack models.Stack
+			resource.DecodeProperties(&stack)
+			return []string{resourceName(doc, resource), resource.Status, strconv.Itoa(len(stack.Resources)), stack.StatusReason, createdDate(doc, resource)}
+		},
+	},
+	"vpn_service": {
+		columns: []tableColumn{
+			{"pdf.column.name", 60, "L"}, {"pdf.column.status", 22, "C"}, {"pdf.column.external_ip", 38, "L"}, {"pdf.column.connections", 48, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var service models.VPNService
+			resource.DecodeProperties(&service)
+			return []string{resourceName(doc, resource), resource.Status, service.ExternalIP, strings.Join(service.Connections, "\n"), createdDate(doc, resource)}
+		},
+	},
+	"vpn_connection": {
+		columns: []tableColumn{
+			{"pdf.column.name", 50, "L"}, {"pdf.column.status", 20, "C"}, {"pdf.column.peer", 30, "L"}, {"pdf.column.peer_cidrs", 35, "L"}, {"pdf.column.weak_crypto", 33, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var conn models.VPNConnection
+			resource.DecodeProperties(&conn)
+			return []string{resourceName(doc, resource), resource.Status, conn.PeerAddress, strings.Join(conn.PeerCIDRs, "\n"), strings.Join(conn.WeakCrypto, ", "), createdDate(doc, resource)}
+		},
+	},
+	"cluster": {
+		columns: []tableColumn{
+			{"pdf.column.name", 70, "L"}, {"pdf.column.status", 40, "C"}, {"pdf.column.masters", 20, "R"}, {"pdf.column.nodes", 20, "R"}, {"pdf.column.key_pair", 18, "L"}, {"pdf.column.created", 22, "C"},
+		},
+		row: func(doc *document, resource models.Resource) []string {
+			var cluster models.Cluster
+			resource.DecodeProperties(&cluster)
+			return []string{resourceName(doc, resource), resource.Status, strconv.Itoa(cluster.MasterCount), strconv.Itoa(cluster.NodeCount), cluster.KeyPair, createdDate(doc, resource)}
+		},
+	},
+}
+
+// defaultTypeTable is used for resource types without specific columns
+var defaultTypeTable = typeTable{
+	columns: []tableColumn{
+		{"pdf.column.name", 120, "L"}, {"pdf.column.status", 40, "C"}, {"pdf.column.created", 30, "C"},
+	},
+	row: func(doc *document, resource models.Resource) []string {
+		return []string{resourceName(doc, resource), resource.Status, createdDate(doc, resource)}
+	},
+}
+
+func tableForType(resourceType string) typeTable {
+	if table, exists := typeTables[resourceType]; exists {
+		return table
+	}
+	return defaultTypeTable
+}
+
+// resourceName returns the display name, marking IaC-managed resources
+func resourceName(doc *document, resource models.Resource) string {
+	name := resource.Name
+	if name == "" {
+		name = doc.t("pdf.unnamed")
+	}
+	if resource.StackName != "" {
+		name = doc.t("pdf.stack_suffix", name, resource.StackName)
+	}
+	return name
+}
+
+func createdDate(doc *document, resource models.Resource) string {
+	if resource.CreatedAt == nil {
+		return doc.t("pdf.unknown")
+	}
+	return resource.CreatedAt.Format("2006-01-02")
+}
+
+// routerExternalIPs extracts gateway addresses from external_gateway_info
+func routerExternalIPs(router models.Router) []string {
+	var addresses []string
+	fixedIPs, _ := router.ExternalGatewayInfo["external_fixed_ips"].([]interface{})
+	for _, fixedIP := range fixedIPs {
+		if entry, ok := fixedIP.(map[string]interface{}); ok {
+			if address, ok := entry["ip_address"].(string); ok {
+				addresses = append(addresses, address)
+			}
+		}
+	}
+	return addresses
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,39 @@
+package pdf
+
+import (
+	_ "embed"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// fontFamily is the embedded Unicode font used for all report text.
+// Core PDF fonts only cover Latin-1, so Cyrillic names were unreadable.
+const fontFamily = "DejaVu"
+
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	fontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	fontBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	fontItalic []byte
+)
+
+// addFonts registers the embedded font styles, only used glyphs are embedded in the output
+func addFonts(pdf *gofpdf.Fpdf) {
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fontBold)
+	pdf.AddUTF8FontFromBytes(fontFamily, "I", fontItalic)
+}
+
+// safeText replaces characters outside the Basic Multilingual Plane (e.g. emoji),
+// which the font width tables do not cover
+func safeText(text string) string {
+	return strings.Map(func(ch rune) rune {
+		if ch > 0xFFFF {
+			return '?'
+		}
+		return ch
+	}, text)
+}
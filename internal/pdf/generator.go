@@ -3,53 +3,147 @@ package pdf
 import (
 	"bytes"
 	"fmt"
+	"log/slog"
+	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jung-kurt/gofpdf"
 
+	"openstack-reporter/internal/compliance"
+	"openstack-reporter/internal/filter"
+	"openstack-reporter/internal/i18n"
 	"openstack-reporter/internal/models"
 )
 
-type Generator struct{}
+type Generator struct {
+	log *slog.Logger
+}
+
+// Options controls optional layout of the PDF report
+type Options struct {
+	// GroupByTag groups detailed resources by value of this tag key instead of project
+	GroupByTag string
+	// Compliance adds the tagging compliance section if set
+	Compliance *compliance.Report
+	// Scope describes filters applied to the report, empty for the whole cloud
+	Scope string
+	// Language of report texts, the configured default language if empty
+	Language string
+}
+
+// maxRenderPasses bounds re-rendering until table of contents page numbers settle
+const maxRenderPasses = 3
 
 func NewGenerator() *Generator {
-	return &Generator{}
+	return &Generator{log: slog.Default()}
+}
+
+// SetLogger sets the logger for generation, e.g. the request logger
+func (g *Generator) SetLogger(logger *slog.Logger) {
+	g.log = logger
 }
 
 // GenerateReport creates a PDF report from the resource data
 func (g *Generator) GenerateReport(report *models.ResourceReport) ([]byte, error) {
+	return g.GenerateReportWithOptions(report, Options{})
+}
+
+// GenerateReportWithOptions creates a PDF report using the given layout options.
+// The document is rendered again while table of contents page numbers change,
+// since they are only known after the sections were laid out.
+func (g *Generator) GenerateReportWithOptions(report *models.ResourceReport, opts Options) ([]byte, error) {
+	start := time.Now()
+	var entries []tocEntry
+	var doc *document
+	passes := 0
+	for pass := 0; pass < maxRenderPasses; pass++ {
+		passes++
+		doc = g.render(report, opts, entries)
+		if err := doc.pdf.Error(); err != nil {
+			return nil, fmt.Errorf("failed to generate PDF: %w", err)
+		}
+		if pass > 0 && reflect.DeepEqual(entries, doc.toc) {
+			break
+		}
+		entries = doc.toc
+	}
+
+	// Generate PDF bytes
+	var buf bytes.Buffer
+	err := doc.pdf.Output(&buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	}
+
+	g.log.Info("PDF generated", "resources", len(report.Resources), "bytes", buf.Len(),
+		"pages", doc.pdf.PageCount(), "passes", passes, "duration", time.Since(start))
+	return buf.Bytes(), nil
+}
+
+// render lays out the whole report, entries are the contents of the previous pass
+func (g *Generator) render(report *models.ResourceReport, opts Options, entries []tocEntry) *document {
+	lang := opts.Language
+	if lang == "" {
+		lang = i18n.Default()
+	}
+
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
+	addFonts(pdf)
+	doc := &document{pdf: pdf, lang: lang}
 
-	// Add title
-	g.addTitle(pdf, "OpenStack Resources Report")
+	title := doc.t("pdf.title")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("OpenStack Reporter", true)
+	g.setHeaderFooter(doc, title, doc.t("pdf.generated", report.GeneratedAt.Format("2006-01-02 15:04")))
+	pdf.AddPage()
 
-	// Add generation info
-	g.addGenerationInfo(pdf, report.GeneratedAt)
+	// Cover page with title, generation info and contents
+	g.addTitle(pdf, title)
+	g.addGenerationInfo(doc, report.GeneratedAt, opts.Scope)
+	g.addTableOfContents(doc, entries)
+	pdf.AddPage()
 
-	// Add summary section
-	g.addSummary(pdf, report.Summary)
+	// Add summary section with charts
+	g.addSummary(doc, report)
 
 	// Add projects section
-	g.addProjectsSection(pdf, report.Projects)
+	g.addProjectsSection(doc, report.Projects)
 
-	// Add detailed resources by project and type
-	g.addDetailedResourcesByProject(pdf, report.Resources)
+	// Add VPN crypto findings
+	g.addVPNFindings(doc, report.Resources)
+	g.addDanglingDNS(doc, report.Resources)
+	g.addUnresolvedOwners(doc, report.UnresolvedOwners)
 
-	// Generate PDF bytes
-	var buf bytes.Buffer
-	err := pdf.Output(&buf)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate PDF: %w", err)
+	// Add tagging compliance
+	if opts.Compliance != nil {
+		g.addCompliance(doc, opts.Compliance)
 	}
 
-	return buf.Bytes(), nil
+	// Add detailed resources by project (or tag value) and type
+	if opts.GroupByTag != "" {
+		g.addTagSummary(doc, report.Resources, opts.GroupByTag)
+		g.addDetailedResources(doc, report.Resources, func(group string) string {
+			return doc.t("pdf.group.tag", opts.GroupByTag, group)
+		}, func(resource models.Resource) string {
+			return filter.TagValue(resource, opts.GroupByTag)
+		})
+	} else {
+		g.addDetailedResources(doc, report.Resources, func(group string) string {
+			return doc.t("pdf.group.project", group)
+		}, func(resource models.Resource) string {
+			return resource.ProjectName
+		})
+	}
+
+	return doc
 }
 
 func (g *Generator) addTitle(pdf *gofpdf.Fpdf, title string) {
-	pdf.SetFont("Arial", "B", 20)
+	title = safeText(title)
+	pdf.SetFont(fontFamily, "B", 20)
 	pdf.SetTextColor(0, 50, 100)
 
 	// Center the title
@@ -62,132 +156,283 @@ func (g *Generator) addTitle(pdf *gofpdf.Fpdf, title string) {
 	pdf.Ln(25)
 }
 
-func (g *Generator) addGenerationInfo(pdf *gofpdf.Fpdf, generatedAt time.Time) {
-	pdf.SetFont("Arial", "", 10)
+func (g *Generator) addGenerationInfo(doc *document, generatedAt time.Time, scope string) {
+	pdf := doc.pdf
+	pdf.SetFont(fontFamily, "", 10)
 	pdf.SetTextColor(100, 100, 100)
 
-	info := fmt.Sprintf("Generated: %s", generatedAt.Format("2006-01-02 15:04:05"))
-	pdf.Cell(0, 8, info)
-	pdf.Ln(15)
-}
-
-func (g *Generator) addSummary(pdf *gofpdf.Fpdf, summary models.Summary) {
-	// Section title
-	pdf.SetFont("Arial", "B", 14)
+	pdf.Cell(0, 8, doc.t("pdf.generated", generatedAt.Format("2006-01-02 15:04:05")))
+	pdf.Ln(8)
+	if scope != "" {
+		pdf.MultiCell(0, 5, safeText(doc.t("pdf.scope", scope)), "", "L", false)
+	}
 	pdf.SetTextColor(0, 0, 0)
-	pdf.Cell(0, 10, "Summary")
-	pdf.Ln(12)
+	pdf.Ln(10)
+}
 
-	// Summary data
-	pdf.SetFont("Arial", "", 10)
+func (g *Generator) addSummary(doc *document, report *models.ResourceReport) {
+	summary := report.Summary
+	g.addSection(doc, doc.t("pdf.section.summary"), 0)
 
 	summaryData := [][]string{
-		{"Projects", strconv.Itoa(summary.TotalProjects)},
-		{"Virtual Machines", strconv.Itoa(summary.TotalServers)},
-		{"Volumes", strconv.Itoa(summary.TotalVolumes)},
-		{"Networks", strconv.Itoa(summary.TotalNetworks)},
-		{"Load Balancers", strconv.Itoa(summary.TotalLoadBalancers)},
-		{"Floating IPs", strconv.Itoa(summary.TotalFloatingIPs)},
-		{"VPN Services", strconv.Itoa(summary.TotalVPNServices)},
-		{"Clusters", strconv.Itoa(summary.TotalClusters)},
-		{"Routers", strconv.Itoa(summary.TotalRouters)},
+		{doc.t("pdf.summary.projects"), strconv.Itoa(summary.TotalProjects)},
+		{doc.t("pdf.summary.servers"), strconv.Itoa(summary.TotalServers)},
+		{doc.t("pdf.summary.volumes"), strconv.Itoa(summary.TotalVolumes)},
+		{doc.t("pdf.summary.networks"), strconv.Itoa(summary.TotalNetworks)},
+		{doc.t("pdf.summary.load_balancers"), strconv.Itoa(summary.TotalLoadBalancers)},
+		{doc.t("pdf.summary.floating_ips"), strconv.Itoa(summary.TotalFloatingIPs)},
+		{doc.t("pdf.summary.vpn_services"), strconv.Itoa(summary.TotalVPNServices)},
+		{doc.t("pdf.summary.vpn_connections"), strconv.Itoa(summary.TotalVPNConnections)},
+		{doc.t("pdf.summary.weak_vpn_crypto"), strconv.Itoa(summary.TotalWeakVPNCrypto)},
+		{doc.t("pdf.summary.clusters"), strconv.Itoa(summary.TotalClusters)},
+		{doc.t("pdf.summary.routers"), strconv.Itoa(summary.TotalRouters)},
+		{doc.t("pdf.summary.ports"), strconv.Itoa(summary.TotalPorts)},
+		{doc.t("pdf.summary.object_containers"), strconv.Itoa(summary.TotalObjectContainers)},
+		{doc.t("pdf.summary.public_containers"), strconv.Itoa(summary.TotalPublicContainers)},
+		{doc.t("pdf.summary.dns_zones"), strconv.Itoa(summary.TotalDNSZones)},
+		{doc.t("pdf.summary.dns_recordsets"), strconv.Itoa(summary.TotalDNSRecordSets)},
+		{doc.t("pdf.summary.dangling_dns"), strconv.Itoa(summary.TotalDanglingDNS)},
+		{doc.t("pdf.summary.stacks"), strconv.Itoa(summary.TotalStacks)},
+		{doc.t("pdf.summary.stack_managed"), strconv.Itoa(summary.TotalStackManaged)},
+		{doc.t("pdf.summary.unresolved_owners"), strconv.Itoa(summary.TotalUnresolvedOwners)},
 	}
 
-	// Create summary table
-	colWidth := 80.0
-	for _, row := range summaryData {
-		pdf.SetFillColor(240, 240, 240)
-		pdf.CellFormat(colWidth, 8, row[0], "1", 0, "L", true, 0, "")
-		pdf.CellFormat(colWidth, 8, row[1], "1", 1, "R", false, 0, "")
-	}
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.resource", 80, "L"}, {"pdf.column.count", 30, "R"},
+	}, summaryData)
 
-	pdf.Ln(10)
+	// Distribution charts
+	g.addBarChart(doc, "pdf.chart.by_type", g.typeDistribution(doc, report.Resources))
+	g.addPieChart(doc, "pdf.chart.by_status", g.statusDistribution(doc, report.Resources))
 }
 
-func (g *Generator) addProjectsSection(pdf *gofpdf.Fpdf, projects []models.Project) {
-	// Section title
-	pdf.SetFont("Arial", "B", 14)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.Cell(0, 10, "Projects")
-	pdf.Ln(12)
+func (g *Generator) addProjectsSection(doc *document, projects []models.Project) {
+	pdf := doc.pdf
+	g.addSection(doc, doc.t("pdf.section.projects"), 0)
 
 	if len(projects) == 0 {
-		pdf.SetFont("Arial", "I", 10)
-		pdf.Cell(0, 8, "No projects found")
+		pdf.SetFont(fontFamily, "I", 10)
+		pdf.Cell(0, 8, doc.t("pdf.empty.projects"))
 		pdf.Ln(15)
 		return
 	}
 
-	// Table header
-	pdf.SetFont("Arial", "B", 10)
-	pdf.SetFillColor(200, 200, 200)
-	pdf.CellFormat(60, 8, "Name", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(40, 8, "ID", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(60, 8, "Description", "1", 0, "L", true, 0, "")
-	pdf.CellFormat(30, 8, "Enabled", "1", 1, "C", true, 0, "")
+	var rows [][]string
+	for _, project := range projects {
+		enabledText := doc.t("pdf.no")
+		if project.Enabled {
+			enabledText = doc.t("pdf.yes")
+		}
+		rows = append(rows, []string{project.Name, project.ID, project.Description, enabledText})
+	}
 
-	// Table data
-	pdf.SetFont("Arial", "", 9)
-	pdf.SetFillColor(255, 255, 255)
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.name", 50, "L"}, {"pdf.column.id", 62, "L"}, {"pdf.column.description", 60, "L"}, {"pdf.column.enabled", 18, "C"},
+	}, rows)
+}
 
-	for _, project := range projects {
-		description := project.Description
-		if len(description) > 30 {
-			description = description[:30] + "..."
+func (g *Generator) addVPNFindings(doc *document, resources []models.Resource) {
+	var connections []models.Resource
+	for _, resource := range resources {
+		if resource.Type != "vpn_connection" {
+			continue
+		}
+		var conn models.VPNConnection
+		if resource.DecodeProperties(&conn) != nil || len(conn.WeakCrypto) == 0 {
+			continue
 		}
+		resource.Properties = conn
+		connections = append(connections, resource)
+	}
 
-		enabledText := "No"
-		if project.Enabled {
-			enabledText = "Yes"
+	if len(connections) == 0 {
+		return
+	}
+
+	sort.Slice(connections, func(i, j int) bool {
+		if connections[i].ProjectName != connections[j].ProjectName {
+			return connections[i].ProjectName < connections[j].ProjectName
 		}
+		return connections[i].Name < connections[j].Name
+	})
 
-		pdf.CellFormat(60, 6, g.truncateString(project.Name, 25), "1", 0, "L", false, 0, "")
-		pdf.CellFormat(40, 6, g.truncateString(project.ID, 15), "1", 0, "L", false, 0, "")
-		pdf.CellFormat(60, 6, description, "1", 0, "L", false, 0, "")
-		pdf.CellFormat(30, 6, enabledText, "1", 1, "C", false, 0, "")
+	var rows [][]string
+	for _, resource := range connections {
+		conn := resource.Properties.(models.VPNConnection)
+		rows = append(rows, []string{resource.ProjectName, resource.Name, conn.PeerAddress, strings.Join(conn.WeakCrypto, ", ")})
 	}
 
-	pdf.Ln(10)
+	g.addFindingSection(doc, doc.t("pdf.section.vpn_findings"))
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.project", 40, "L"}, {"pdf.column.connection", 45, "L"}, {"pdf.column.peer", 35, "L"}, {"pdf.column.weak_algorithms", 70, "L"},
+	}, rows)
+}
+
+func (g *Generator) addDanglingDNS(doc *document, resources []models.Resource) {
+	var records []models.Resource
+	for _, resource := range resources {
+		if resource.Type != "dns_recordset" {
+			continue
+		}
+		var record models.DNSRecordSet
+		if resource.DecodeProperties(&record) != nil || len(record.Dangling) == 0 {
+			continue
+		}
+		resource.Properties = record
+		records = append(records, resource)
+	}
+
+	if len(records) == 0 {
+		return
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	var rows [][]string
+	for _, resource := range records {
+		record := resource.Properties.(models.DNSRecordSet)
+		rows = append(rows, []string{resource.ProjectName, record.Name, record.Type, strings.Join(record.Dangling, ", ")})
+	}
+
+	g.addFindingSection(doc, doc.t("pdf.section.dangling_dns"))
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.project", 40, "L"}, {"pdf.column.record", 70, "L"}, {"pdf.column.type", 15, "C"}, {"pdf.column.unallocated", 65, "L"},
+	}, rows)
+}
+
+func (g *Generator) addUnresolvedOwners(doc *document, owners []models.UnresolvedOwner) {
+	if len(owners) == 0 {
+		return
+	}
+
+	var rows [][]string
+	for _, owner := range owners {
+		projectID := owner.ProjectID
+		if projectID == "" {
+			projectID = doc.t("pdf.not_set")
+		}
+		rows = append(rows, []string{doc.typeName(owner.ResourceType), owner.ResourceName, owner.ResourceID, projectID})
+	}
+
+	g.addFindingSection(doc, doc.t("pdf.section.unresolved"))
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.type", 35, "L"}, {"pdf.column.name", 55, "L"}, {"pdf.column.resource_id", 50, "L"}, {"pdf.column.owner_project_id", 50, "L"},
+	}, rows)
+}
+
+func (g *Generator) addCompliance(doc *document, report *compliance.Report) {
+	pdf := doc.pdf
+	g.addSection(doc, doc.t("pdf.section.compliance"), 0)
+
+	pdf.SetFont(fontFamily, "", 10)
+	pdf.Cell(0, 6, doc.t("pdf.compliance.stats",
+		report.Evaluated, report.Compliant, report.NonCompliant, report.TotalViolations))
+	pdf.Ln(8)
+
+	// Per project summary
+	var rows [][]string
+	for _, project := range report.Projects {
+		rows = append(rows, []string{
+			project.ProjectName,
+			strconv.Itoa(project.Evaluated),
+			strconv.Itoa(project.Compliant),
+			strconv.Itoa(project.NonCompliant),
+			strconv.Itoa(len(project.Violations)),
+		})
+	}
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.project", 90, "L"}, {"pdf.column.evaluated", 25, "R"}, {"pdf.column.compliant", 25, "R"}, {"pdf.column.non_compliant", 25, "R"}, {"pdf.column.violations", 25, "R"},
+	}, rows)
+
+	// Violations by project
+	for _, project := range report.Projects {
+		if len(project.Violations) == 0 {
+			continue
+		}
+
+		var rows [][]string
+		for _, violation := range project.Violations {
+			problem := doc.t("pdf.compliance.missing")
+			if violation.Kind == compliance.InvalidValue {
+				problem = doc.t("pdf.compliance.invalid", violation.Value, strings.Join(violation.Allowed, ", "))
+			}
+			name := violation.ResourceName
+			if name == "" {
+				name = violation.ResourceID
+			}
+			rows = append(rows, []string{doc.typeName(violation.ResourceType), name, violation.Key, problem})
+		}
+
+		g.addSection(doc, doc.t("pdf.section.violations", project.ProjectName), 1)
+		g.addTable(doc, []tableColumn{
+			{"pdf.column.type", 30, "L"}, {"pdf.column.name", 55, "L"}, {"pdf.column.key", 35, "L"}, {"pdf.column.problem", 70, "L"},
+		}, rows)
+	}
 }
 
-func (g *Generator) addDetailedResourcesByProject(pdf *gofpdf.Fpdf, resources []models.Resource) {
+func (g *Generator) addTagSummary(doc *document, resources []models.Resource, key string) {
+	groups := filter.GroupByTag(resources, key, false)
+
+	var rows [][]string
+	for _, group := range groups {
+		var types []string
+		for resourceType := range group.ByType {
+			types = append(types, resourceType)
+		}
+		sort.Strings(types)
+
+		var byType []string
+		for _, resourceType := range types {
+			byType = append(byType, fmt.Sprintf("%s: %d", doc.typeName(resourceType), group.ByType[resourceType]))
+		}
+
+		rows = append(rows, []string{group.Value, strconv.Itoa(group.Count), strings.Join(byType, ", ")})
+	}
+
+	g.addSection(doc, doc.t("pdf.section.tag_summary", key), 0)
+	g.addTable(doc, []tableColumn{
+		{"pdf.column.value", 60, "L"}, {"pdf.column.count", 20, "R"}, {"pdf.column.by_type", 110, "L"},
+	}, rows)
+}
+
+// addDetailedResources lists resources grouped by groupKey (project or tag value) and type,
+// groupTitle formats the group heading
+func (g *Generator) addDetailedResources(doc *document, resources []models.Resource, groupTitle func(string) string, groupKey func(models.Resource) string) {
+	pdf := doc.pdf
+
 	// Add new page for detailed resources
 	pdf.AddPage()
-
-	// Section title
-	pdf.SetFont("Arial", "B", 14)
-	pdf.SetTextColor(0, 0, 0)
-	pdf.Cell(0, 10, "Detailed Resources")
-	pdf.Ln(15)
+	g.addSection(doc, doc.t("pdf.section.detailed"), 0)
 
 	if len(resources) == 0 {
-		pdf.SetFont("Arial", "I", 10)
-		pdf.Cell(0, 8, "No resources found")
+		pdf.SetFont(fontFamily, "I", 10)
+		pdf.Cell(0, 8, doc.t("pdf.empty.resources"))
 		return
 	}
 
-	// Group resources by project
-	projectGroups := make(map[string][]models.Resource)
+	// Group resources by project or tag value
+	groups := make(map[string][]models.Resource)
 	for _, resource := range resources {
-		projectGroups[resource.ProjectName] = append(projectGroups[resource.ProjectName], resource)
+		key := groupKey(resource)
+		groups[key] = append(groups[key], resource)
 	}
 
-	// Sort projects alphabetically
-	var projects []string
-	for projectName := range projectGroups {
-		projects = append(projects, projectName)
+	// Sort groups alphabetically
+	var groupNames []string
+	for groupName := range groups {
+		groupNames = append(groupNames, groupName)
 	}
-	sort.Strings(projects)
+	sort.Strings(groupNames)
 
-	for _, projectName := range projects {
-		resources := projectGroups[projectName]
+	for _, groupName := range groupNames {
+		resources := groups[groupName]
 
-		// Project subsection
-		pdf.SetFont("Arial", "B", 12)
-		pdf.Cell(0, 8, fmt.Sprintf("Project: %s (%d resources)", projectName, len(resources)))
-		pdf.Ln(10)
+		// Group subsection, listed in contents and bookmarks
+		g.addSection(doc, fmt.Sprintf("%s (%s)", groupTitle(groupName), doc.n("pdf.resources", len(resources))), 1)
 
-		// Group resources by type within project
+		// Group resources by type within group
 		typeGroups := make(map[string][]models.Resource)
 		for _, resource := range resources {
 			typeGroups[resource.Type] = append(typeGroups[resource.Type], resource)
@@ -203,119 +448,20 @@ func (g *Generator) addDetailedResourcesByProject(pdf *gofpdf.Fpdf, resources []
 		for _, resourceType := range types {
 			resources := typeGroups[resourceType]
 
-			// Type subsection
-			pdf.SetFont("Arial", "B", 10)
-			pdf.Cell(0, 8, fmt.Sprintf("%s (%d)", g.getTypeDisplayName(resourceType), len(resources)))
-			pdf.Ln(10)
+			// Type subsection, bookmark only
+			g.addSection(doc, fmt.Sprintf("%s (%d)", doc.typeName(resourceType), len(resources)), 2)
 
 			// Sort resources by creation date
 			sort.Slice(resources, func(i, j int) bool {
-				return resources[i].CreatedAt.Before(resources[j].CreatedAt)
+				return resources[i].CreatedBefore(resources[j])
 			})
 
-			// Table header
-			pdf.SetFont("Arial", "B", 9)
-			pdf.SetFillColor(200, 200, 200)
-			pdf.CellFormat(80, 7, "Name", "1", 0, "L", true, 0, "")
-			pdf.CellFormat(30, 7, "Status", "1", 0, "C", true, 0, "")
-			pdf.CellFormat(35, 7, "Created", "1", 1, "C", true, 0, "")
-
-			// Table data
-			pdf.SetFont("Arial", "", 8)
-			pdf.SetFillColor(255, 255, 255)
-
+			table := tableForType(resourceType)
+			rows := make([][]string, 0, len(resources))
 			for _, resource := range resources {
-				name := resource.Name
-				if name == "" {
-					name = "unnamed"
-				}
-
-				createdDate := resource.CreatedAt.Format("2006-01-02")
-
-				// Add subnet info to name for networks
-				displayName := name
-				if resourceType == "network" {
-					// Properties приходят как map[string]interface{}
-					if props, ok := resource.Properties.(map[string]interface{}); ok {
-						if subnetsData, exists := props["subnets"]; exists {
-							if subnets, ok := subnetsData.([]interface{}); ok {
-								fmt.Printf("DEBUG: Network %s has %d subnets\n", name, len(subnets))
-								if len(subnets) > 0 {
-									subnetInfo := ""
-									for i, subnetData := range subnets {
-										if i >= 2 {
-											subnetInfo += fmt.Sprintf(" (+%d)", len(subnets)-2)
-											break
-										}
-										if i > 0 {
-											subnetInfo += ", "
-										}
-										if subnet, ok := subnetData.(map[string]interface{}); ok {
-											if cidr, exists := subnet["cidr"]; exists {
-												subnetInfo += cidr.(string)
-											}
-										}
-									}
-									displayName = fmt.Sprintf("%s\nSubnets: %s", name, subnetInfo)
-									fmt.Printf("DEBUG: Display name for %s: %s\n", name, displayName)
-								} else {
-									displayName = fmt.Sprintf("%s\nNo subnets", name)
-									fmt.Printf("DEBUG: No subnets for %s\n", name)
-								}
-							}
-						} else {
-							displayName = fmt.Sprintf("%s\nNo subnets", name)
-							fmt.Printf("DEBUG: No subnets field for %s\n", name)
-						}
-					} else {
-						fmt.Printf("DEBUG: Failed to cast properties to map for %s\n", name)
-					}
-				}
-
-				// Увеличиваем высоту ячейки для сетей с подсетями
-				cellHeight := 6.0
-				if resourceType == "network" {
-					cellHeight = 8.0
-				}
-
-				// Не обрезаем текст для сетей, чтобы подсети были видны
-				if resourceType == "network" {
-					pdf.CellFormat(80, cellHeight, displayName, "1", 0, "L", false, 0, "")
-				} else {
-					pdf.CellFormat(80, cellHeight, g.truncateString(displayName, 60), "1", 0, "L", false, 0, "")
-				}
-				pdf.CellFormat(30, cellHeight, g.truncateString(resource.Status, 12), "1", 0, "C", false, 0, "")
-				pdf.CellFormat(35, cellHeight, createdDate, "1", 1, "C", false, 0, "")
+				rows = append(rows, table.row(doc, resource))
 			}
-			pdf.Ln(5)
+			g.addTable(doc, table.columns, rows)
 		}
-		pdf.Ln(5)
-	}
-}
-
-func (g *Generator) getTypeDisplayName(resourceType string) string {
-	types := map[string]string{
-		"server":         "Virtual Machine",
-		"volume":         "Volume",
-		"floating_ip":    "Floating IP",
-		"router":         "Router",
-		"network":        "Network",
-		"load_balancer":  "Load Balancer",
-		"vpn_service":    "VPN Service",
-		"cluster":        "K8s Cluster",
-	}
-
-	if displayName, exists := types[resourceType]; exists {
-		return displayName
-	}
-	return resourceType
-}
-
-
-
-func (g *Generator) truncateString(str string, maxLen int) string {
-	if len(str) <= maxLen {
-		return str
 	}
-	return str[:maxLen-3] + "..."
 }
@@ -0,0 +1,306 @@
+package pdf
+
+import (
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"openstack-reporter/internal/i18n"
+)
+
+const (
+	// Table row text line height and cell padding
+	tableLineHeight  = 4.0
+	tableCellPadding = 1.0
+	// maxCellLines limits wrapped lines of a single table cell
+	maxCellLines = 8
+)
+
+// tocEntry is a table of contents line pointing to a report section
+type tocEntry struct {
+	title string
+	level int
+	page  int
+}
+
+// document is the state of a single rendering pass
+type document struct {
+	pdf  *gofpdf.Fpdf
+	lang string
+	// toc collects sections in order of appearance
+	toc []tocEntry
+	// links are TOC link IDs of the entries from the previous pass
+	links []int
+}
+
+// t returns the message of key in the document language
+func (d *document) t(key string, args ...interface{}) string {
+	return i18n.T(d.lang, key, args...)
+}
+
+// n returns the plural message of key for count in the document language
+func (d *document) n(key string, count int) string {
+	return i18n.N(d.lang, key, count)
+}
+
+// typeName returns the display name of a resource type
+func (d *document) typeName(resourceType string) string {
+	key := "type." + resourceType
+	if name := d.t(key); name != key {
+		return name
+	}
+	return resourceType
+}
+
+// tableColumn describes a table column, title is a message key
+type tableColumn struct {
+	title string
+	width float64
+	align string
+}
+
+// tocLevels is the number of section levels listed in the table of contents,
+// deeper sections only get a bookmark
+const tocLevels = 2
+
+// addSection starts a report section listed in PDF bookmarks and, for the
+// top levels, in the table of contents
+func (g *Generator) addSection(doc *document, title string, level int) {
+	g.startSection(doc, title, level, false)
+}
+
+// addFindingSection starts a top level section highlighting problems
+func (g *Generator) addFindingSection(doc *document, title string) {
+	g.startSection(doc, title, 0, true)
+}
+
+func (g *Generator) startSection(doc *document, title string, level int, alert bool) {
+	// Keep the heading together with at least a table header and a row
+	g.ensureSpace(doc, 30)
+
+	pdf := doc.pdf
+	title = safeText(title)
+	pdf.Bookmark(title, level, -1)
+	if level < tocLevels {
+		index := len(doc.toc)
+		doc.toc = append(doc.toc, tocEntry{title: title, level: level, page: pdf.PageNo()})
+		if index < len(doc.links) {
+			pdf.SetLink(doc.links[index], -1, -1)
+		}
+	}
+
+	sizes := []float64{14, 12, 10}
+	size := sizes[len(sizes)-1]
+	if level < len(sizes) {
+		size = sizes[level]
+	}
+
+	pdf.SetFont(fontFamily, "B", size)
+	if alert {
+		pdf.SetTextColor(150, 0, 0)
+	} else {
+		pdf.SetTextColor(0, 0, 0)
+	}
+	pdf.Cell(0, size*0.7, title)
+	pdf.Ln(size * 0.85)
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// ensureSpace starts a new page if less than height is left on the current one
+func (g *Generator) ensureSpace(doc *document, height float64) {
+	_, pageHeight := doc.pdf.GetPageSize()
+	_, bottomMargin := doc.pdf.GetAutoPageBreak()
+	if doc.pdf.GetY()+height > pageHeight-bottomMargin {
+		doc.pdf.AddPage()
+	}
+}
+
+// addTable draws a table with wrapped cells, repeating the header after page breaks
+func (g *Generator) addTable(doc *document, columns []tableColumn, rows [][]string) {
+	pdf := doc.pdf
+	_, pageHeight := pdf.GetPageSize()
+	_, bottomMargin := pdf.GetAutoPageBreak()
+
+	drawHeader := func() {
+		pdf.SetFont(fontFamily, "B", 9)
+		pdf.SetFillColor(200, 200, 200)
+		pdf.SetTextColor(0, 0, 0)
+		for i, column := range columns {
+			ln := 0
+			if i == len(columns)-1 {
+				ln = 1
+			}
+			pdf.CellFormat(column.width, 7, doc.t(column.title), "1", ln, alignOrLeft(column.align), true, 0, "")
+		}
+	}
+
+	drawHeader()
+	pdf.SetFont(fontFamily, "", 8)
+
+	for _, row := range rows {
+		// Wrap every cell to its column width
+		cells := make([][]string, len(columns))
+		lines := 1
+		for i, column := range columns {
+			text := ""
+			if i < len(row) {
+				text = row[i]
+			}
+			cells[i] = g.wrapText(pdf, text, column.width-2*tableCellPadding)
+			if len(cells[i]) > lines {
+				lines = len(cells[i])
+			}
+		}
+		height := float64(lines)*tableLineHeight + 2*tableCellPadding
+
+		if pdf.GetY()+height > pageHeight-bottomMargin {
+			pdf.AddPage()
+			drawHeader()
+			pdf.SetFont(fontFamily, "", 8)
+		}
+
+		x, y := pdf.GetXY()
+		for i, column := range columns {
+			pdf.Rect(x, y, column.width, height, "D")
+			for j, line := range cells[i] {
+				pdf.SetXY(x+tableCellPadding, y+tableCellPadding+float64(j)*tableLineHeight)
+				pdf.CellFormat(column.width-2*tableCellPadding, tableLineHeight, line, "", 0, alignOrLeft(column.align), false, 0, "")
+			}
+			x += column.width
+		}
+		pdf.SetXY(pdf.GetX(), y+height)
+		pdf.SetX(leftMargin(pdf))
+	}
+
+	pdf.Ln(6)
+}
+
+// wrapText splits text into lines fitting the width, at most maxCellLines
+func (g *Generator) wrapText(pdf *gofpdf.Fpdf, text string, width float64) []string {
+	if text == "" {
+		return []string{""}
+	}
+	text = safeText(text)
+
+	var lines []string
+	for _, paragraph := range splitLines(text) {
+		if paragraph == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, pdf.SplitText(paragraph, width)...)
+	}
+	if len(lines) > maxCellLines {
+		lines = append(lines[:maxCellLines-1], g.fitText(pdf, lines[maxCellLines-1]+" ...", width))
+	}
+	return lines
+}
+
+// fitText shortens text with an ellipsis to fit the width on a single line
+func (g *Generator) fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	text = safeText(text)
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func splitLines(text string) []string {
+	var lines []string
+	start := 0
+	for i, ch := range text {
+		if ch == '\n' {
+			lines = append(lines, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(lines, text[start:])
+}
+
+func alignOrLeft(align string) string {
+	if align == "" {
+		return "L"
+	}
+	return align
+}
+
+func leftMargin(pdf *gofpdf.Fpdf) float64 {
+	left, _, _, _ := pdf.GetMargins()
+	return left
+}
+
+// addTableOfContents lists sections with page numbers and links.
+// Entries come from the previous rendering pass.
+func (g *Generator) addTableOfContents(doc *document, entries []tocEntry) {
+	pdf := doc.pdf
+	if len(entries) == 0 {
+		return
+	}
+
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Cell(0, 10, doc.t("pdf.contents"))
+	pdf.Ln(12)
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageWidth - left - right
+
+	doc.links = make([]int, len(entries))
+	for i, entry := range entries {
+		link := pdf.AddLink()
+		doc.links[i] = link
+
+		indent := float64(entry.level) * 6
+		if entry.level == 0 {
+			pdf.SetFont(fontFamily, "B", 10)
+		} else {
+			pdf.SetFont(fontFamily, "", 9)
+		}
+
+		g.ensureSpace(doc, 6)
+		pdf.SetX(left + indent)
+		pdf.CellFormat(width-indent-15, 6, g.fitText(pdf, entry.title, width-indent-17), "", 0, "L", false, link, "")
+		pdf.CellFormat(15, 6, strconv.Itoa(entry.page), "", 1, "R", false, link, "")
+	}
+}
+
+// setHeaderFooter adds running header and page numbers to every page but the first
+func (g *Generator) setHeaderFooter(doc *document, title, generated string) {
+	pdf := doc.pdf
+	pdf.AliasNbPages("")
+
+	pdf.SetHeaderFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pageWidth, _ := pdf.GetPageSize()
+		left, _, right, _ := pdf.GetMargins()
+
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.SetY(8)
+		pdf.CellFormat(0, 5, safeText(title), "", 0, "L", false, 0, "")
+		pdf.SetX(left)
+		pdf.CellFormat(0, 5, generated, "", 1, "R", false, 0, "")
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Line(left, 14, pageWidth-right, 14)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetY(18)
+	})
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, "OpenStack Reporter", "", 0, "L", false, 0, "")
+		pdf.SetX(leftMargin(pdf))
+		pdf.CellFormat(0, 5, doc.t("pdf.page", pdf.PageNo(), "{nb}"), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+}
@@ -0,0 +1,528 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Expressions are evaluated over resource fields, for example:
+//
+//	type == "server" && status == "SHUTOFF" && age > 30d
+//	tag.env != "prod" || !has(tag.owner)
+//	name =~ "^tmp-" && project != "infra"
+//
+// Supported operators are == != < <= > >= =~ (regexp match), && || ! and
+// parentheses. Literals are strings, numbers, booleans, null and durations
+// with d, w, h, m or s suffix. Both sides of a comparison must have the same
+// type, age > 30d compiles while age > "30" is an error; null only equals null.
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenDuration
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "<", ">", "!"}
+
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		ch := rune(input[i])
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case ch == '"' || ch == '\'':
+			end := i + 1
+			var sb strings.Builder
+			for ; end < len(input) && rune(input[end]) != ch; end++ {
+				if input[end] == '\\' && end+1 < len(input) {
+					end++
+				}
+				sb.WriteByte(input[end])
+			}
+			if end >= len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokenString, sb.String(), i})
+			i = end + 1
+		case unicode.IsDigit(ch):
+			end := i
+			for end < len(input) && (unicode.IsDigit(rune(input[end])) || input[end] == '.') {
+				end++
+			}
+			kind := tokenNumber
+			if end < len(input) && strings.ContainsRune("dwhms", rune(input[end])) {
+				end++
+				kind = tokenDuration
+			}
+			tokens = append(tokens, token{kind, input[i:end], i})
+			i = end
+		case unicode.IsLetter(ch) || ch == '_':
+			end := i
+			for end < len(input) && isIdentChar(rune(input[end])) {
+				end++
+			}
+			tokens = append(tokens, token{tokenIdent, input[i:end], i})
+			i = end
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(input[i:], op) {
+					tokens = append(tokens, token{tokenOperator, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", ch, i)
+			}
+		}
+	}
+	return append(tokens, token{tokenEOF, "", len(input)}), nil
+}
+
+func isIdentChar(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_' || ch == '.' || ch == '-'
+}
+
+// node is a parsed expression evaluated against resource fields
+type node interface {
+	eval(fields Fields) interface{}
+}
+
+type literal struct{ value interface{} }
+
+type field struct{ name string }
+
+type has struct{ name string }
+
+type not struct{ operand node }
+
+type logical struct {
+	op          string
+	left, right node
+}
+
+type comparison struct {
+	op          string
+	left, right node
+	pattern     *regexp.Regexp
+}
+
+// valueType is the static type of a node, comparisons of different types
+// can never match and are rejected by Compile
+type valueType int
+
+const (
+	typeNull valueType = iota
+	typeString
+	typeNumber
+	typeDuration
+	typeBool
+)
+
+func (t valueType) String() string {
+	switch t {
+	case typeString:
+		return "string"
+	case typeNumber:
+		return "number"
+	case typeDuration:
+		return "duration"
+	case typeBool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func typeOf(n node) valueType {
+	switch n := n.(type) {
+	case literal:
+		switch n.value.(type) {
+		case string:
+			return typeString
+		case float64:
+			return typeNumber
+		case time.Duration:
+			return typeDuration
+		case bool:
+			return typeBool
+		}
+		return typeNull
+	case field:
+		return fieldType(n.name)
+	}
+	return typeBool
+}
+
+func (n literal) eval(Fields) interface{} { return n.value }
+
+func (n field) eval(fields Fields) interface{} { return fields.Get(n.name) }
+
+func (n has) eval(fields Fields) interface{} { return fields.Get(n.name) != nil }
+
+func (n not) eval(fields Fields) interface{} { return !truthy(n.operand.eval(fields)) }
+
+func (n logical) eval(fields Fields) interface{} {
+	left := truthy(n.left.eval(fields))
+	if n.op == "&&" {
+		return left && truthy(n.right.eval(fields))
+	}
+	return left || truthy(n.right.eval(fields))
+}
+
+func (n comparison) eval(fields Fields) interface{} {
+	left := n.left.eval(fields)
+	if n.pattern != nil {
+		s, ok := left.(string)
+		return ok && n.pattern.MatchString(s)
+	}
+	return compare(n.op, left, n.right.eval(fields))
+}
+
+// compare applies a comparison operator. Unknown values (nil) are only equal
+// to null, ordering comparisons with unknown values are false.
+func compare(op string, left, right interface{}) bool {
+	if left == nil || right == nil {
+		switch op {
+		case "==":
+			return left == nil && right == nil
+		case "!=":
+			return (left == nil) != (right == nil)
+		}
+		return false
+	}
+
+	var order int
+	switch l := left.(type) {
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return op == "!="
+		}
+		if op == "==" || op == "!=" {
+			return strings.EqualFold(l, r) == (op == "==")
+		}
+		order = strings.Compare(l, r)
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return op == "!="
+		}
+		order = compareOrdered(l, r)
+	case time.Duration:
+		r, ok := right.(time.Duration)
+		if !ok {
+			return op == "!="
+		}
+		order = compareOrdered(l, r)
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return op == "!="
+		}
+		if op == "==" || op == "!=" {
+			return (l == r) == (op == "==")
+		}
+		return false
+	default:
+		return false
+	}
+
+	switch op {
+	case "==":
+		return order == 0
+	case "!=":
+		return order != 0
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+	return false
+}
+
+func compareOrdered[T float64 | time.Duration](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	case time.Duration:
+		return v != 0
+	}
+	return true
+}
+
+// Expression is a compiled rule condition
+type Expression struct {
+	source string
+	root   node
+}
+
+// String returns the source of the expression
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Match evaluates the expression against resource fields
+func (e *Expression) Match(fields Fields) bool {
+	return truthy(e.root.eval(fields))
+}
+
+// Compile parses an expression
+func Compile(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind != tokenOperator || t.text == "&&" || t.text == "||" || t.text == "!" {
+		return left, nil
+	}
+	p.next()
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := comparison{op: t.text, left: left, right: right}
+	leftType, rightType := typeOf(left), typeOf(right)
+	if t.text == "=~" {
+		if leftType != typeString {
+			return nil, fmt.Errorf("=~ at position %d requires a string operand, got %s", t.pos, leftType)
+		}
+		pattern, ok := right.(literal)
+		if !ok {
+			return nil, fmt.Errorf("=~ at position %d requires a string pattern", t.pos)
+		}
+		source, ok := pattern.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("=~ at position %d requires a string pattern", t.pos)
+		}
+		if cmp.pattern, err = regexp.Compile(source); err != nil {
+			return nil, fmt.Errorf("invalid pattern at position %d: %w", t.pos, err)
+		}
+		return cmp, nil
+	}
+
+	// null equals only null, other types must match and be ordered for < <= > >=
+	if leftType != typeNull && rightType != typeNull && leftType != rightType {
+		return nil, fmt.Errorf("cannot compare %s with %s at position %d", leftType, rightType, t.pos)
+	}
+	if t.text != "==" && t.text != "!=" {
+		for _, operandType := range []valueType{leftType, rightType} {
+			if operandType == typeNull || operandType == typeBool {
+				return nil, fmt.Errorf("%s at position %d cannot order a %s", t.text, t.pos, operandType)
+			}
+		}
+	}
+	return cmp, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, fmt.Errorf("missing ) for ( at position %d", t.pos)
+		}
+		return inner, nil
+	case tokenString:
+		return literal{t.text}, nil
+	case tokenNumber:
+		value, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", t.text, t.pos)
+		}
+		return literal{value}, nil
+	case tokenDuration:
+		value, err := ParseDuration(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q at position %d", t.text, t.pos)
+		}
+		return literal{value}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		case "has":
+			if p.next().kind != tokenLParen {
+				return nil, fmt.Errorf("has at position %d requires (field)", t.pos)
+			}
+			name := p.next()
+			if name.kind != tokenIdent || p.next().kind != tokenRParen {
+				return nil, fmt.Errorf("has at position %d requires (field)", t.pos)
+			}
+			if !isKnownField(name.text) {
+				return nil, fmt.Errorf("unknown field %q at position %d", name.text, name.pos)
+			}
+			return has{name: name.text}, nil
+		}
+		if !isKnownField(t.text) {
+			return nil, fmt.Errorf("unknown field %q at position %d", t.text, t.pos)
+		}
+		return field{name: t.text}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+}
+
+// ParseDuration parses durations like 30d, 2w, 12h, 15m or 90s
+func ParseDuration(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("unknown duration unit in %q", value)
+	}
+	amount, err := strconv.ParseFloat(value[:len(value)-1], 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(amount * float64(unit)), nil
+}
@@ -0,0 +1,132 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"openstack-reporter/internal/models"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) *time.Time {
+	t := now.AddDate(0, 0, -days)
+	return &t
+}
+
+func TestMatch(t *testing.T) {
+	stopped := models.Resource{
+		ID:          "srv-1",
+		Name:        "tmp-builder",
+		Type:        "server",
+		Status:      "SHUTOFF",
+		ProjectName: "web",
+		ProjectID:   "p-web",
+		CreatedAt:   daysAgo(45),
+		Metadata:    map[string]string{"env": "prod"},
+	}
+	fresh := stopped
+	fresh.CreatedAt = daysAgo(3)
+	unknownAge := stopped
+	unknownAge.CreatedAt = nil
+	owned := stopped
+	owned.Metadata = map[string]string{"env": "prod", "owner": "alice"}
+	stack := stopped
+	stack.StackName = "web-stack"
+
+	tests := []struct {
+		expr     string
+		resource models.Resource
+		want     bool
+	}{
+		{`type == "server" && status == "SHUTOFF" && age > 30d`, stopped, true},
+		{`type == "server" && status == "SHUTOFF" && age > 30d`, fresh, false},
+		{`type == "server" && status == "SHUTOFF" && age > 30d`, unknownAge, false},
+		{`type == "volume" && age > 30d`, stopped, false},
+		{`status == "shutoff"`, stopped, true},
+		{`age == null`, unknownAge, true},
+		{`age != null`, stopped, true},
+		{`tag.env == "prod"`, stopped, true},
+		{`tag.env != "prod"`, stopped, false},
+		{`tag.missing == null`, stopped, true},
+		{`tag.env == "prod" && !has(tag.owner)`, stopped, true},
+		{`tag.env == "prod" && !has(tag.owner)`, owned, false},
+		{`has(stack)`, stopped, false},
+		{`has(stack) && stack == "web-stack"`, stack, true},
+		{`name =~ "^tmp-" && project != "infra"`, stopped, true},
+		{`name =~ "^prod-"`, stopped, false},
+		{`(project == "infra" || project_id == "p-web") && age <= 45d`, stopped, true},
+		{`age >= 6w && age < 7w`, stopped, true},
+		{`has(tag.owner) == false`, stopped, true},
+	}
+
+	for _, tt := range tests {
+		expr, err := Compile(tt.expr)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", tt.expr, err)
+		}
+		if got := expr.Match(Fields{Resource: tt.resource, Now: now}); got != tt.want {
+			t.Errorf("%q on %s created %v: got %v, want %v", tt.expr, tt.resource.ID, tt.resource.CreatedAt, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`flavor == "m1.small"`, `unknown field "flavor"`},
+		{`has(foo)`, `unknown field "foo"`},
+		{`has(tag.)`, `unknown field "tag."`},
+		{`has(status`, `has at position 0 requires (field)`},
+		{`age > "x"`, `cannot compare duration with string`},
+		{`status == 1`, `cannot compare string with number`},
+		{`age > 30`, `cannot compare duration with number`},
+		{`has(tag.owner) == "yes"`, `cannot compare boolean with string`},
+		{`has(tag.owner) > false`, `cannot order a boolean`},
+		{`age > null`, `cannot order a null`},
+		{`age =~ "^1"`, `=~ at position 4 requires a string operand, got duration`},
+		{`name =~ status`, `requires a string pattern`},
+		{`name =~ "("`, `invalid pattern`},
+		{`status == "ACTIVE`, `unterminated string`},
+		{`(status == "ACTIVE"`, `missing )`},
+		{`status == "ACTIVE")`, `unexpected ")"`},
+		{`status ==`, `unexpected end of expression`},
+		{`status # "ACTIVE"`, `unexpected character '#'`},
+		{``, `unexpected end of expression`},
+	}
+
+	for _, tt := range tests {
+		_, err := Compile(tt.expr)
+		if err == nil {
+			t.Errorf("Compile(%q) succeeded, want error containing %q", tt.expr, tt.want)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compile(%q) = %q, want error containing %q", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := map[string]time.Duration{
+		"30d":  30 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"1.5h": 90 * time.Minute,
+		"90s":  90 * time.Second,
+	}
+	for value, want := range tests {
+		got, err := ParseDuration(value)
+		if err != nil || got != want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"", "30", "30y", "xd"} {
+		if _, err := ParseDuration(value); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded, want error", value)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"strings"
+	"time"
+
+	"openstack-reporter/internal/models"
+)
+
+// fieldNames lists fields available in expressions besides tag.<key>
+var fieldNames = map[string]string{
+	"id":           "Resource ID",
+	"name":         "Resource name",
+	"type":         "Resource type: server, volume, port, ...",
+	"status":       "Resource status, e.g. ACTIVE or SHUTOFF",
+	"project":      "Project name",
+	"project_id":   "Project ID",
+	"stack":        "Name of the owning Heat stack, null if not stack-managed",
+	"age":          "Time since creation, null if unknown",
+	"updated_age":  "Time since last update, null if unknown",
+	"tag.<key>":    "Value of a metadata/tag key, null if missing",
+	"has(<field>)": "True if the field is known, e.g. has(tag.owner)",
+}
+
+// FieldNames describes fields available in rule expressions
+func FieldNames() map[string]string {
+	return fieldNames
+}
+
+func isKnownField(name string) bool {
+	if strings.HasPrefix(name, "tag.") && len(name) > len("tag.") {
+		return true
+	}
+	_, exists := fieldNames[name]
+	return exists && !strings.ContainsAny(name, "<(")
+}
+
+// fieldType is the type of a known field, durations for ages and strings
+// for the rest
+func fieldType(name string) valueType {
+	if name == "age" || name == "updated_age" {
+		return typeDuration
+	}
+	return typeString
+}
+
+// Fields exposes resource fields to expressions
+type Fields struct {
+	Resource models.Resource
+	Now      time.Time
+}
+
+// Get returns field value as string, float64, bool or time.Duration, nil if unknown
+func (f Fields) Get(name string) interface{} {
+	r := f.Resource
+	if key, ok := strings.CutPrefix(name, "tag."); ok {
+		if value, exists := r.Metadata[key]; exists {
+			return value
+		}
+		return nil
+	}
+
+	switch name {
+	case "id":
+		return r.ID
+	case "name":
+		return r.Name
+	case "type":
+		return r.Type
+	case "status":
+		return r.Status
+	case "project":
+		return r.ProjectName
+	case "project_id":
+		return r.ProjectID
+	case "stack":
+		if r.StackName == "" {
+			return nil
+		}
+		return r.StackName
+	case "age":
+		if r.CreatedAt == nil {
+			return nil
+		}
+		return f.Now.Sub(*r.CreatedAt)
+	case "updated_age":
+		if r.UpdatedAt == nil {
+			return nil
+		}
+		return f.Now.Sub(*r.UpdatedAt)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package rules
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"openstack-reporter/internal/models"
+)
+
+// DefaultRulesFile is used when the configuration sets no file
+const DefaultRulesFile = "rules.yaml"
+
+// Severities ordered from the most important
+var severities = []string{"critical", "warning", "info"}
+
+// Rule is a named expression, every matching resource produces a finding
+type Rule struct {
+	Name     string `yaml:"name" json:"name"`
+	Severity string `yaml:"severity" json:"severity"`
+	Message  string `yaml:"message" json:"message"`
+	Expr     string `yaml:"expr" json:"expr"`
+
+	expression *Expression
+}
+
+// RuleSet is a list of compiled rules
+type RuleSet struct {
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// Finding is a resource matched by a rule
+type Finding struct {
+	Rule         string `json:"rule"`
+	Severity     string `json:"severity"`
+	Message      string `json:"message"`
+	ResourceID   string `json:"resource_id"`
+	ResourceName string `json:"resource_name"`
+	ResourceType string `json:"resource_type"`
+	ProjectID    string `json:"project_id"`
+	ProjectName  string `json:"project_name"`
+}
+
+// RuleSummary is the number of resources matched by a rule
+type RuleSummary struct {
+	Rule
+	Matches int `json:"matches"`
+}
+
+// Result is the outcome of evaluating rules against a report
+type Result struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	EvaluatedAt time.Time      `json:"evaluated_at"`
+	Rules       []RuleSummary  `json:"rules"`
+	BySeverity  map[string]int `json:"by_severity"`
+	Findings    []Finding      `json:"findings"`
+}
+
+// LoadRules reads a YAML rules file and compiles every expression
+func LoadRules(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+
+	var set RuleSet
+	if err := yaml.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i := range set.Rules {
+		rule := &set.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule name %q", rule.Name)
+		}
+		names[rule.Name] = true
+
+		rule.Severity = strings.ToLower(strings.TrimSpace(rule.Severity))
+		if rule.Severity == "" {
+			rule.Severity = "warning"
+		}
+		if severityRank(rule.Severity) == len(severities) {
+			return nil, fmt.Errorf("rule %q: unknown severity %q, expected one of %s",
+				rule.Name, rule.Severity, strings.Join(severities, ", "))
+		}
+
+		if rule.expression, err = Compile(rule.Expr); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+
+	return &set, nil
+}
+
+func severityRank(severity string) int {
+	for i, s := range severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severities)
+}
+
+// Evaluate matches every resource of the report against all rules
+func (s *RuleSet) Evaluate(report *models.ResourceReport, now time.Time) *Result {
+	result := &Result{
+		GeneratedAt: report.GeneratedAt,
+		EvaluatedAt: now,
+		Rules:       make([]RuleSummary, 0, len(s.Rules)),
+		BySeverity:  make(map[string]int),
+		Findings:    []Finding{},
+	}
+
+	for _, rule := range s.Rules {
+		summary := RuleSummary{Rule: rule}
+		for _, resource := range report.Resources {
+			if !rule.expression.Match(Fields{Resource: resource, Now: now}) {
+				continue
+			}
+			summary.Matches++
+			result.BySeverity[rule.Severity]++
+			result.Findings = append(result.Findings, Finding{
+				Rule:         rule.Name,
+				Severity:     rule.Severity,
+				Message:      rule.Message,
+				ResourceID:   resource.ID,
+				ResourceName: resource.Name,
+				ResourceType: resource.Type,
+				ProjectID:    resource.ProjectID,
+				ProjectName:  resource.ProjectName,
+			})
+		}
+		result.Rules = append(result.Rules, summary)
+	}
+
+	// Most severe findings first, then by project and resource name
+	sort.SliceStable(result.Findings, func(i, j int) bool {
+		a, b := result.Findings[i], result.Findings[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) < severityRank(b.Severity)
+		}
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		return a.ResourceName < b.ResourceName
+	})
+
+	return result
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"openstack-reporter/internal/models"
+)
+
+// fixtureReport has a stopped server, an old unattached volume, a resource
+// in ERROR and untagged production resources across two projects
+func fixtureReport() *models.ResourceReport {
+	return &models.ResourceReport{
+		GeneratedAt: now,
+		Resources: []models.Resource{
+			{ID: "srv-old", Name: "old-builder", Type: "server", Status: "SHUTOFF",
+				ProjectID: "p-web", ProjectName: "web", CreatedAt: daysAgo(90)},
+			{ID: "srv-new", Name: "new-builder", Type: "server", Status: "SHUTOFF",
+				ProjectID: "p-web", ProjectName: "web", CreatedAt: daysAgo(2)},
+			{ID: "srv-active", Name: "api", Type: "server", Status: "ACTIVE",
+				ProjectID: "p-api", ProjectName: "api", CreatedAt: daysAgo(300),
+				Metadata: map[string]string{"env": "prod", "owner": "alice"}},
+			{ID: "vol-free", Name: "scratch", Type: "volume", Status: "available",
+				ProjectID: "p-api", ProjectName: "api", CreatedAt: daysAgo(20),
+				Metadata: map[string]string{"env": "prod"}},
+			{ID: "lb-broken", Name: "edge", Type: "load_balancer", Status: "ERROR",
+				ProjectID: "p-web", ProjectName: "web"},
+		},
+	}
+}
+
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEvaluateExampleRules(t *testing.T) {
+	set, err := LoadRules(filepath.Join("..", "..", "rules.example.yaml"))
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	result := set.Evaluate(fixtureReport(), now)
+
+	var got []string
+	for _, finding := range result.Findings {
+		got = append(got, finding.Severity+" "+finding.Rule+" "+finding.ResourceID)
+	}
+	want := []string{
+		"critical error-state lb-broken",
+		"warning untagged-production vol-free",
+		"warning stopped-servers srv-old",
+		"info unattached-volumes vol-free",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if result.BySeverity["critical"] != 1 || result.BySeverity["warning"] != 2 || result.BySeverity["info"] != 1 {
+		t.Errorf("by severity = %v", result.BySeverity)
+	}
+	matches := make(map[string]int)
+	for _, rule := range result.Rules {
+		matches[rule.Name] = rule.Matches
+	}
+	if matches["stopped-servers"] != 1 || matches["error-state"] != 1 {
+		t.Errorf("rule matches = %v", matches)
+	}
+	if !result.GeneratedAt.Equal(now) {
+		t.Errorf("generated at = %v, want %v", result.GeneratedAt, now)
+	}
+}
+
+func TestEvaluateNoMatches(t *testing.T) {
+	set, err := LoadRules(writeRules(t, `
+rules:
+  - name: owners
+    expr: has(tag.owner) && tag.owner == "bob"
+`))
+	if err != nil {
+		t.Fatalf("LoadRules: %v", err)
+	}
+	result := set.Evaluate(fixtureReport(), now)
+	if result.Findings == nil || len(result.Findings) != 0 {
+		t.Errorf("findings = %#v, want empty slice", result.Findings)
+	}
+	if set.Rules[0].Severity != "warning" {
+		t.Errorf("default severity = %q, want warning", set.Rules[0].Severity)
+	}
+}
+
+func TestLoadRulesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"no name", "rules:\n  - expr: age > 1d\n", "rule 1 has no name"},
+		{"duplicate", "rules:\n  - name: a\n    expr: age > 1d\n  - name: a\n    expr: age > 2d\n", `duplicate rule name "a"`},
+		{"severity", "rules:\n  - name: a\n    severity: fatal\n    expr: age > 1d\n", `unknown severity "fatal"`},
+		{"unknown field", "rules:\n  - name: a\n    expr: flavor == \"big\"\n", `rule "a": unknown field "flavor"`},
+		{"type mismatch", "rules:\n  - name: a\n    expr: age > \"x\"\n", `rule "a": cannot compare duration with string`},
+		{"yaml", "rules: [", "failed to parse rules"},
+	}
+	for _, tt := range tests {
+		_, err := LoadRules(writeRules(t, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := LoadRules(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("missing file: no error")
+	}
+}
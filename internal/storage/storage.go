@@ -3,29 +3,58 @@ package storage
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"openstack-reporter/internal/models"
 )
 
 const (
-	dataDir      = "data"
 	reportFile   = "openstack_report.json"
 	backupPrefix = "backup_"
 )
 
+// Defaults used when the configuration leaves settings empty
+const (
+	DefaultDataDir         = "data"
+	DefaultBackupRetention = 7 * 24 * time.Hour
+)
+
+// Config controls where reports are kept and how long backups survive
+type Config struct {
+	DataDir string `yaml:"data_dir" json:"data_dir"`
+	// BackupRetention removes backups older than this, zero keeps them
+	BackupRetention time.Duration `yaml:"backup_retention" json:"backup_retention"`
+	// MaxBackups keeps only the newest backups, zero means no limit
+	MaxBackups int `yaml:"max_backups" json:"max_backups"`
+}
+
 type Storage struct {
 	dataPath string
+	config   Config
 }
 
-func NewStorage() *Storage {
+func NewStorage(config Config) *Storage {
+	if config.DataDir == "" {
+		config.DataDir = DefaultDataDir
+	}
 	return &Storage{
-		dataPath: dataDir,
+		dataPath: config.DataDir,
+		config:   config,
 	}
 }
 
+// DataDir returns the directory reports are kept in
+func (s *Storage) DataDir() string {
+	return s.dataPath
+}
+
 // Initialize creates the data directory if it doesn't exist
 func (s *Storage) Initialize() error {
 	if err := os.MkdirAll(s.dataPath, 0755); err != nil {
@@ -34,10 +63,24 @@ func (s *Storage) Initialize() error {
 	return nil
 }
 
-// SaveReport saves the resource report to JSON file
+// SaveReport saves the resource report to JSON file. The report is written
+// to a temporary file first, so an interrupted save never leaves a truncated
+// report behind.
 func (s *Storage) SaveReport(report *models.ResourceReport) error {
 	reportPath := filepath.Join(s.dataPath, reportFile)
 
+	// Marshal report to JSON
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal report: %w", err)
+	}
+
+	tempPath, err := writeTemp(reportPath, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	defer os.Remove(tempPath)
+
 	// Create backup of existing report
 	if _, err := os.Stat(reportPath); err == nil {
 		backupPath := filepath.Join(s.dataPath, fmt.Sprintf("%s%d_%s",
@@ -45,19 +88,14 @@ func (s *Storage) SaveReport(report *models.ResourceReport) error {
 		if err := os.Rename(reportPath, backupPath); err != nil {
 			return fmt.Errorf("failed to create backup: %w", err)
 		}
+		slog.Debug("Backed up previous report", "path", backupPath)
 	}
 
-	// Marshal report to JSON
-	data, err := json.MarshalIndent(report, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal report: %w", err)
-	}
-
-	// Write to file
-	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+	if err := os.Rename(tempPath, reportPath); err != nil {
 		return fmt.Errorf("failed to write report file: %w", err)
 	}
 
+	slog.Debug("Saved report", "path", reportPath, "resources", len(report.Resources), "bytes", len(data))
 	return nil
 }
 
@@ -81,6 +119,68 @@ func (s *Storage) LoadReport() (*models.ResourceReport, error) {
 	return &report, nil
 }
 
+// WriteReport encodes the report as indented JSON in the format of SaveReport
+func WriteReport(w io.Writer, report *models.ResourceReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// ReadReport decodes a report written by WriteReport or SaveReport
+func ReadReport(r io.Reader) (*models.ResourceReport, error) {
+	var report models.ResourceReport
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report: %w", err)
+	}
+	return &report, nil
+}
+
+// LoadPreviousReport loads the most recent backup created by SaveReport
+func (s *Storage) LoadPreviousReport() (*models.ResourceReport, error) {
+	files, err := os.ReadDir(s.dataPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	// Backups are named backup_<unix time>_openstack_report.json
+	var latest string
+	var latestTime int64
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, reportFile) {
+			continue
+		}
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), "_"+reportFile)
+		created, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			continue
+		}
+		if latest == "" || created > latestTime {
+			latest = name
+			latestTime = created
+		}
+	}
+
+	if latest == "" {
+		return nil, fmt.Errorf("no previous report found")
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dataPath, latest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous report: %w", err)
+	}
+
+	var report models.ResourceReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal previous report: %w", err)
+	}
+
+	return &report, nil
+}
+
 // ReportExists checks if a saved report exists
 func (s *Storage) ReportExists() bool {
 	reportPath := filepath.Join(s.dataPath, reportFile)
@@ -100,29 +200,127 @@ func (s *Storage) GetReportAge() (time.Duration, error) {
 	return time.Since(info.ModTime()), nil
 }
 
-// CleanupBackups removes backup files older than specified duration
-func (s *Storage) CleanupBackups(maxAge time.Duration) error {
+// CheckWritable creates and removes a temporary file in the data directory
+func (s *Storage) CheckWritable() error {
+	if err := s.Initialize(); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(s.dataPath, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("data directory is not writable: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString("ok"); err != nil {
+		file.Close()
+		return fmt.Errorf("data directory is not writable: %w", err)
+	}
+	return file.Close()
+}
+
+// CleanupBackups removes backups older than the configured retention and
+// beyond the configured number of backups, newest backups are kept
+func (s *Storage) CleanupBackups() error {
 	files, err := os.ReadDir(s.dataPath)
 	if err != nil {
 		return fmt.Errorf("failed to read data directory: %w", err)
 	}
 
+	var backups []os.FileInfo
 	for _, file := range files {
-		if !file.IsDir() && len(file.Name()) > len(backupPrefix) &&
-		   file.Name()[:len(backupPrefix)] == backupPrefix {
-
-			filePath := filepath.Join(s.dataPath, file.Name())
-			info, err := file.Info()
-			if err != nil {
-				continue
-			}
-
-			if time.Since(info.ModTime()) > maxAge {
-				if err := os.Remove(filePath); err != nil {
-					return fmt.Errorf("failed to remove backup file %s: %w", filePath, err)
-				}
-			}
+		if file.IsDir() || !strings.HasPrefix(file.Name(), backupPrefix) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, info)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ModTime().After(backups[j].ModTime())
+	})
+
+	removed := 0
+	for i, info := range backups {
+		expired := s.config.BackupRetention > 0 && time.Since(info.ModTime()) > s.config.BackupRetention
+		excess := s.config.MaxBackups > 0 && i >= s.config.MaxBackups
+		if !expired && !excess {
+			continue
+		}
+		filePath := filepath.Join(s.dataPath, info.Name())
+		if err := os.Remove(filePath); err != nil {
+			return fmt.Errorf("failed to remove backup file %s: %w", filePath, err)
 		}
+		removed++
+	}
+
+	if removed > 0 {
+		slog.Info("Removed old report backups", "count", removed,
+			"retention", s.config.BackupRetention, "max_backups", s.config.MaxBackups)
+	}
+	return nil
+}
+
+// SaveJSON saves an auxiliary document (e.g. rule results) to the data
+// directory with permissions perm, e.g. 0600 for documents holding secrets
+func (s *Storage) SaveJSON(name string, value interface{}, perm os.FileMode) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	path := filepath.Join(s.dataPath, name)
+	tempPath, err := writeTemp(path, data, perm)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	defer os.Remove(tempPath)
+
+	if err := os.Rename(tempPath, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// writeTemp writes data to a synced temporary file with permissions perm
+// next to path, to be renamed over path once complete
+func writeTemp(path string, data []byte, perm os.FileMode) (string, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := os.Chmod(file.Name(), perm); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// LoadJSON loads an auxiliary document saved with SaveJSON
+func (s *Storage) LoadJSON(name string, value interface{}) error {
+	data, err := os.ReadFile(filepath.Join(s.dataPath, name))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", name, err)
 	}
 
 	return nil
@@ -0,0 +1,404 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"openstack-reporter/internal/models"
+)
+
+// Node types used in the topology graph
+const (
+	NodeServer          = "server"
+	NodePort            = "port"
+	NodeSubnet          = "subnet"
+	NodeNetwork         = "network"
+	NodeExternalNetwork = "external_network"
+	NodeRouter          = "router"
+	NodeLoadBalancer    = "load_balancer"
+	NodeFloatingIP      = "floating_ip"
+)
+
+// Node represents a vertex of the topology graph
+type Node struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Label       string `json:"label"`
+	ProjectID   string `json:"project_id,omitempty"`
+	ProjectName string `json:"project_name,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Details     string `json:"details,omitempty"`
+}
+
+// Edge represents a link between two nodes
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+	Label  string `json:"label,omitempty"`
+}
+
+// Graph is the network topology built from a resource report
+type Graph struct {
+	Project string `json:"project,omitempty"`
+	Nodes   []Node `json:"nodes"`
+	Edges   []Edge `json:"edges"`
+}
+
+type builder struct {
+	graph   *Graph
+	nodes   map[string]int
+	edges   map[string]bool
+	project string
+}
+
+// Build creates topology graph from the report.
+// If project is not empty, only resources of that project (matched by ID or name)
+// are used as starting points; shared networks and external gateways they
+// reference are still included.
+func Build(report *models.ResourceReport, project string) *Graph {
+	b := &builder{
+		graph:   &Graph{Project: project, Nodes: []Node{}, Edges: []Edge{}},
+		nodes:   make(map[string]int),
+		edges:   make(map[string]bool),
+		project: project,
+	}
+
+	var (
+		servers       = make(map[string]models.Resource)
+		loadBalancers = make(map[string]models.LoadBalancer)
+		portList      []models.Resource
+		subnetNetwork = make(map[string]string)
+	)
+
+	// First pass: networks, subnets, routers and compute/LB endpoints
+	for _, resource := range report.Resources {
+		switch resource.Type {
+		case "network":
+			var network models.Network
+			if resource.DecodeProperties(&network) != nil {
+				continue
+			}
+			for _, subnet := range network.Subnets {
+				subnetNetwork[subnet.ID] = network.ID
+			}
+			if !b.inScope(resource) {
+				continue
+			}
+			nodeType := NodeNetwork
+			if network.External {
+				nodeType = NodeExternalNetwork
+			}
+			b.addResourceNode(resource, nodeType, "")
+			for _, subnet := range network.Subnets {
+				b.addNode(Node{
+					ID:          subnet.ID,
+					Type:        NodeSubnet,
+					Label:       subnetLabel(subnet),
+					ProjectID:   resource.ProjectID,
+					ProjectName: resource.ProjectName,
+					Details:     subnet.GatewayIP,
+				})
+				b.addEdge(subnet.ID, network.ID, "member", "")
+			}
+
+		case "router":
+			if !b.inScope(resource) {
+				continue
+			}
+			var router models.Router
+			if resource.DecodeProperties(&router) != nil {
+				continue
+			}
+			b.addResourceNode(resource, NodeRouter, "")
+			if networkID, ok := router.ExternalGatewayInfo["network_id"].(string); ok && networkID != "" {
+				b.ensureNode(networkID, NodeExternalNetwork, "external "+shortID(networkID))
+				b.addEdge(router.ID, networkID, "gateway", gatewayIPs(router.ExternalGatewayInfo))
+			}
+
+		case "server":
+			servers[resource.ID] = resource
+			if b.inScope(resource) {
+				var server models.Server
+				resource.DecodeProperties(&server)
+				b.addResourceNode(resource, NodeServer, server.FlavorName)
+			}
+
+		case "load_balancer":
+			var lb models.LoadBalancer
+			if resource.DecodeProperties(&lb) != nil {
+				continue
+			}
+			loadBalancers[lb.ID] = lb
+			if !b.inScope(resource) {
+				continue
+			}
+			b.addResourceNode(resource, NodeLoadBalancer, lb.VipAddress)
+			if lb.VipSubnetID != "" {
+				b.ensureNode(lb.VipSubnetID, NodeSubnet, "subnet "+shortID(lb.VipSubnetID))
+				b.addEdge(lb.ID, lb.VipSubnetID, "vip", lb.VipAddress)
+			}
+
+		case "port":
+			portList = append(portList, resource)
+		}
+	}
+
+	// Second pass: ports connect devices to subnets
+	for _, resource := range portList {
+		var port models.Port
+		if resource.DecodeProperties(&port) != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(port.DeviceOwner, "network:router_interface"):
+			if !b.hasNode(port.DeviceID) {
+				continue
+			}
+			for _, ip := range port.FixedIPs {
+				b.ensureSubnet(ip.SubnetID, subnetNetwork)
+				b.addEdge(port.DeviceID, ip.SubnetID, "interface", ip.IPAddress)
+			}
+
+		case strings.HasPrefix(port.DeviceOwner, "compute:"):
+			server, exists := servers[port.DeviceID]
+			if !exists || !b.inScope(server) {
+				continue
+			}
+			b.addPortNode(resource, port)
+			b.addEdge(port.DeviceID, port.ID, "attached", "")
+			for _, ip := range port.FixedIPs {
+				b.ensureSubnet(ip.SubnetID, subnetNetwork)
+				b.addEdge(port.ID, ip.SubnetID, "fixed_ip", ip.IPAddress)
+			}
+
+		default:
+			// Load balancer VIP ports are matched by VIP address
+			for _, lb := range loadBalancers {
+				if !b.hasNode(lb.ID) || !portHasIP(port, lb.VipAddress) {
+					continue
+				}
+				b.addPortNode(resource, port)
+				b.addEdge(lb.ID, port.ID, "vip_port", "")
+				for _, ip := range port.FixedIPs {
+					b.ensureSubnet(ip.SubnetID, subnetNetwork)
+					b.addEdge(port.ID, ip.SubnetID, "fixed_ip", ip.IPAddress)
+				}
+			}
+		}
+	}
+
+	// Third pass: floating IPs attach to ports and external networks
+	for _, resource := range report.Resources {
+		if resource.Type != "floating_ip" || !b.inScope(resource) {
+			continue
+		}
+		var fip models.FloatingIP
+		if resource.DecodeProperties(&fip) != nil {
+			continue
+		}
+		b.addResourceNode(resource, NodeFloatingIP, fip.AttachedResourceName)
+		if fip.FloatingNetworkID != "" {
+			b.ensureNode(fip.FloatingNetworkID, NodeExternalNetwork, "external "+shortID(fip.FloatingNetworkID))
+			b.addEdge(fip.ID, fip.FloatingNetworkID, "allocated_from", "")
+		}
+		if fip.PortID != "" && b.hasNode(fip.PortID) {
+			b.addEdge(fip.ID, fip.PortID, "nat", fip.FixedIP)
+		}
+	}
+
+	sort.Slice(b.graph.Nodes, func(i, j int) bool {
+		if b.graph.Nodes[i].Type != b.graph.Nodes[j].Type {
+			return b.graph.Nodes[i].Type < b.graph.Nodes[j].Type
+		}
+		return b.graph.Nodes[i].Label < b.graph.Nodes[j].Label
+	})
+
+	return b.graph
+}
+
+// DOT renders the graph in Graphviz DOT format
+func (g *Graph) DOT() string {
+	shapes := map[string]string{
+		NodeServer:          "box",
+		NodePort:            "point",
+		NodeSubnet:          "ellipse",
+		NodeNetwork:         "ellipse",
+		NodeExternalNetwork: "doubleoctagon",
+		NodeRouter:          "diamond",
+		NodeLoadBalancer:    "hexagon",
+		NodeFloatingIP:      "note",
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph openstack {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, node := range g.Nodes {
+		label := node.Label
+		if node.Details != "" {
+			label += "\\n" + node.Details
+		}
+		shape := shapes[node.Type]
+		if shape == "" {
+			shape = "box"
+		}
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=%s];\n", quoteDOT(node.ID), quoteDOT(label), shape)
+	}
+
+	for _, edge := range g.Edges {
+		if edge.Label != "" {
+			fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", quoteDOT(edge.Source), quoteDOT(edge.Target), quoteDOT(edge.Label))
+		} else {
+			fmt.Fprintf(&sb, "  %s -> %s;\n", quoteDOT(edge.Source), quoteDOT(edge.Target))
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (b *builder) inScope(resource models.Resource) bool {
+	if b.project == "" {
+		return true
+	}
+	return resource.ProjectID == b.project || resource.ProjectName == b.project
+}
+
+func (b *builder) hasNode(id string) bool {
+	_, exists := b.nodes[id]
+	return exists
+}
+
+func (b *builder) addNode(node Node) {
+	if node.ID == "" {
+		return
+	}
+	if index, exists := b.nodes[node.ID]; exists {
+		// Replace placeholder nodes with real data
+		if b.graph.Nodes[index].ProjectID == "" {
+			b.graph.Nodes[index] = node
+		}
+		return
+	}
+	b.nodes[node.ID] = len(b.graph.Nodes)
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+// ensureNode adds a placeholder node for objects outside the report or the project scope
+func (b *builder) ensureNode(id, nodeType, label string) {
+	if id == "" || b.hasNode(id) {
+		return
+	}
+	b.nodes[id] = len(b.graph.Nodes)
+	b.graph.Nodes = append(b.graph.Nodes, Node{ID: id, Type: nodeType, Label: label})
+}
+
+func (b *builder) ensureSubnet(subnetID string, subnetNetwork map[string]string) {
+	if b.hasNode(subnetID) {
+		return
+	}
+	b.ensureNode(subnetID, NodeSubnet, "subnet "+shortID(subnetID))
+	if networkID := subnetNetwork[subnetID]; networkID != "" {
+		b.ensureNode(networkID, NodeNetwork, "network "+shortID(networkID))
+		b.addEdge(subnetID, networkID, "member", "")
+	}
+}
+
+func (b *builder) addResourceNode(resource models.Resource, nodeType, details string) {
+	label := resource.Name
+	if label == "" {
+		label = shortID(resource.ID)
+	}
+	b.addNode(Node{
+		ID:          resource.ID,
+		Type:        nodeType,
+		Label:       label,
+		ProjectID:   resource.ProjectID,
+		ProjectName: resource.ProjectName,
+		Status:      resource.Status,
+		Details:     details,
+	})
+}
+
+func (b *builder) addPortNode(resource models.Resource, port models.Port) {
+	var ips []string
+	for _, ip := range port.FixedIPs {
+		ips = append(ips, ip.IPAddress)
+	}
+	label := port.Name
+	if label == "" {
+		label = "port " + shortID(port.ID)
+	}
+	b.addNode(Node{
+		ID:          port.ID,
+		Type:        NodePort,
+		Label:       label,
+		ProjectID:   resource.ProjectID,
+		ProjectName: resource.ProjectName,
+		Status:      port.Status,
+		Details:     strings.Join(ips, ", "),
+	})
+}
+
+func (b *builder) addEdge(source, target, edgeType, label string) {
+	if source == "" || target == "" {
+		return
+	}
+	key := source + "|" + target + "|" + edgeType
+	if b.edges[key] {
+		return
+	}
+	b.edges[key] = true
+	b.graph.Edges = append(b.graph.Edges, Edge{Source: source, Target: target, Type: edgeType, Label: label})
+}
+
+func portHasIP(port models.Port, address string) bool {
+	if address == "" {
+		return false
+	}
+	for _, ip := range port.FixedIPs {
+		if ip.IPAddress == address {
+			return true
+		}
+	}
+	return false
+}
+
+func subnetLabel(subnet models.Subnet) string {
+	if subnet.Name != "" {
+		return fmt.Sprintf("%s (%s)", subnet.Name, subnet.CIDR)
+	}
+	return subnet.CIDR
+}
+
+func gatewayIPs(info map[string]interface{}) string {
+	fixedIPs, ok := info["external_fixed_ips"].([]interface{})
+	if !ok {
+		return ""
+	}
+	var ips []string
+	for _, item := range fixedIPs {
+		if ip, ok := item.(map[string]interface{}); ok {
+			if address, ok := ip["ip_address"].(string); ok {
+				ips = append(ips, address)
+			}
+		}
+	}
+	return strings.Join(ips, ", ")
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func quoteDOT(value string) string {
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	return "\"" + value + "\""
+}
@@ -1,113 +1,262 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"html/template"
+	"io/fs"
+	"log/slog"
 	"net/http"
 	"os"
-	"time"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 
+	"openstack-reporter/internal/auth"
+	"openstack-reporter/internal/config"
 	"openstack-reporter/internal/handlers"
+	"openstack-reporter/internal/i18n"
+	"openstack-reporter/internal/logging"
+	"openstack-reporter/internal/storage"
 	"openstack-reporter/internal/version"
 )
 
 func main() {
-	// Print version information
-	log.Println(version.GetFullVersionString())
-
 	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
+	envErr := godotenv.Load()
+
+	// Serve is the default, so existing deployments keep working without a command
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		usage(os.Stdout)
+		os.Exit(exitOK)
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(exitUsage)
 	}
 
-	// Initialize web server
-	r := gin.Default()
+	// Configuration file, environment and flags, in increasing precedence
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.Usage = func() { cmd.usage(flags) }
+	run := cmd.setup(flags)
+	cfg, err := config.Load(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(exitOK)
+	}
+	if err != nil {
+		slog.Error("Failed to load configuration", "error", err)
+		os.Exit(exitUsage)
+	}
+	if flags.NArg() != len(cmd.args) {
+		fmt.Fprintf(os.Stderr, "%s expects %d arguments, got %d\n\n", name, len(cmd.args), flags.NArg())
+		cmd.usage(flags)
+		os.Exit(exitUsage)
+	}
+	if err := logging.Setup(cfg.Logging.Level, cfg.Logging.Format); err != nil {
+		slog.Warn("Invalid logging configuration, using defaults", "error", err)
+	}
+	if err := i18n.SetDefault(cfg.Language); err != nil {
+		slog.Warn("Invalid default language", "error", err)
+	}
+	slog.Info(version.GetFullVersionString(), "command", name)
+	if envErr != nil {
+		slog.Debug("No .env file found, using system environment variables")
+	}
+	slog.Info("Configuration loaded", cfg.LogAttrs()...)
+
+	os.Exit(run(cfg, flags.Args()))
+}
+
+// serve runs the web server until it fails or receives SIGINT or SIGTERM.
+// On a signal, connections are drained and running refreshes finished
+// within the shutdown timeout, refreshes still running are then aborted.
+func serve(cfg *config.Config, _ []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Initialize web server, requests are logged by logging.Middleware
+	if os.Getenv("GIN_MODE") == "" && !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	r := gin.New()
+	r.Use(gin.Recovery())
 
 	// Configure trusted proxies for security
-	// Set to localhost and private networks only
-	trustedProxies := []string{
-		"127.0.0.1",
-		"::1",
-		"10.0.0.0/8",
-		"172.16.0.0/12",
-		"192.168.0.0/16",
-	}
-	err := r.SetTrustedProxies(trustedProxies)
-	if err != nil {
-		log.Printf("Warning: Failed to set trusted proxies: %v", err)
+	// Localhost and private networks only by default
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		slog.Warn("Failed to set trusted proxies", "error", err)
 	}
 
 	// Setup routes
-	setupRoutes(r)
+	handler := setupRoutes(ctx, r, cfg)
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	port := cfg.Server.Port
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	served := make(chan error, 1)
+	go func() {
+		served <- server.ListenAndServe()
+	}()
+
+	slog.Info("Starting server", "port", port, "version", version.GetVersionString())
+	select {
+	case err := <-served:
+		slog.Error("Server stopped", "error", err)
+		return exitFailure
+	case <-ctx.Done():
 	}
 
-	log.Printf("Starting server on port %s (version: %s)", port, version.GetVersionString())
-	log.Fatal(r.Run(":" + port))
+	// A second signal terminates immediately
+	stop()
+	slog.Info("Shutting down", "timeout", cfg.Server.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	// Progress streams end with their refresh, so draining may take as long
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Closing connections still active", "error", err)
+	}
+	if err := handler.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Running refreshes aborted", "error", err)
+	}
+	server.Close()
+	slog.Info("Server stopped")
+	return exitOK
 }
 
-func setupRoutes(r *gin.Engine) {
+// setupRoutes registers all routes and starts background work, which stops
+// when ctx is done. The handler is returned to be shut down.
+func setupRoutes(ctx context.Context, r *gin.Engine, cfg *config.Config) *handlers.Handler {
 	// Initialize handlers
-	handler := handlers.NewHandler()
-
-	// Add request logging middleware
-	r.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	}))
+	handler := handlers.NewHandler(cfg)
+	handler.StartMailer(ctx)
+	handler.StartCollector(ctx)
+
+	// Request IDs and request logging
+	r.Use(logging.Middleware())
 
 	// Static files
 	r.Static("/static", "./web/static")
+	r.SetFuncMap(template.FuncMap{"t": i18n.T})
 	r.LoadHTMLGlob("web/templates/*")
 
+	// Liveness and readiness probes are public like static files
+	r.GET("/healthz", handler.Healthz)
+	r.GET("/readyz", handler.Readyz)
+
+	// Authentication, every route except static files, probes, login and version requires a permission
+	authenticator := loadAuthenticator(cfg)
+	r.Use(authenticator.Middleware())
+	read := authenticator.Require(auth.PermissionRead)
+	export := authenticator.Require(auth.PermissionExport)
+	refresh := authenticator.Require(auth.PermissionRefresh)
+	admin := authenticator.Require(auth.PermissionAdmin)
+
+	// Audited routes record caller, parameters and outcome, denied requests included
+	audited := handler.Audit()
+	if authenticator != nil {
+		authenticator.RegisterRoutes(r)
+		r.GET("/api/tokens", admin, authenticator.ListTokens)
+		r.POST("/api/tokens", audited, admin, authenticator.CreateToken)
+		r.DELETE("/api/tokens/:id", audited, admin, authenticator.RevokeToken)
+	}
+
 	// API routes
 	api := r.Group("/api")
 	{
-		api.GET("/resources", handler.GetResources)
-		api.POST("/refresh", handler.RefreshResources)
-		api.POST("/refresh/progress", handler.RefreshWithProgress)
-		api.GET("/progress", handler.GetProgress)
-		api.GET("/export/pdf", handler.ExportToPDF)
-		api.GET("/status", handler.GetReportStatus)
+		api.GET("/resources", audited, read, handler.GetResources)
+		api.POST("/refresh", audited, refresh, handler.RefreshResources)
+		api.POST("/refresh/progress", audited, refresh, handler.RefreshWithProgress)
+		api.DELETE("/refresh/:session_id", audited, refresh, handler.CancelRefresh)
+		api.GET("/progress", refresh, handler.GetProgress)
+		api.GET("/export/pdf", audited, export, handler.ExportToPDF)
+		api.GET("/export/dot", audited, export, handler.ExportTopologyDOT)
+		api.GET("/topology", read, handler.GetTopology)
+		api.GET("/ipam", read, handler.GetIPAM)
+		api.GET("/tags", read, handler.GetTags)
+		api.GET("/compliance", read, handler.GetCompliance)
+		api.GET("/rules", read, handler.GetRuleResults)
+		api.POST("/notifications/test", refresh, handler.TestNotifications)
+		api.POST("/mailer/send", audited, export, handler.SendReportMail)
+		api.GET("/audit", audited, admin, handler.GetAuditLog)
+		api.GET("/config", admin, handler.GetConfig)
+		api.GET("/status", read, handler.GetReportStatus)
 		api.GET("/version", getVersion)
-		api.GET("/docs", getAPIDocs)
+		api.GET("/docs", read, getAPIDocs)
 	}
 
-	log.Println("Routes registered:")
-	log.Println("  GET  /api/resources")
-	log.Println("  POST /api/refresh")
-	log.Println("  POST /api/refresh/progress")
-	log.Println("  GET  /api/progress")
-	log.Println("  GET  /api/export/pdf")
-	log.Println("  GET  /api/status")
-	log.Println("  GET  /api/version")
-	log.Println("  GET  /api/docs")
-
 	// Web routes
-	r.GET("/", indexHandler)
-	r.GET("/docs", docsHandler)
+	r.GET("/", read, indexHandler)
+	r.GET("/docs", read, docsHandler)
+
+	for _, route := range r.Routes() {
+		slog.Debug("Route registered", "method", route.Method, "path", route.Path)
+	}
+	slog.Info("Routes registered", "count", len(r.Routes()))
+	return handler
+}
+
+// loadAuthenticator returns nil when authentication is disabled by the
+// configuration. A missing or invalid auth config stops the server rather
+// than leaving it open.
+func loadAuthenticator(cfg *config.Config) *auth.Authenticator {
+	if cfg.Auth.Disabled {
+		slog.Warn("Authentication disabled by auth.disabled, every route is open")
+		return nil
+	}
+	authConfig, err := auth.LoadConfig(cfg.Files.Auth, auth.KeystoneConfig{
+		AuthURL:        cfg.OpenStack.AuthURL,
+		UserDomainName: cfg.OpenStack.UserDomainName,
+		Insecure:       &cfg.OpenStack.Insecure,
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Error("Auth config not found, create it, point AUTH_CONFIG_FILE at it or set AUTH_DISABLED=true to serve without authentication", "file", cfg.Files.Auth)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Error("Failed to load auth config", "error", err)
+		os.Exit(1)
+	}
+
+	authenticator, err := auth.NewAuthenticator(authConfig, storage.NewStorage(cfg.Storage))
+	if err != nil {
+		slog.Error("Failed to initialize authentication", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Authentication enabled", "methods", strings.Join(authenticator.Methods(), ", "))
+	return authenticator
 }
 
 func indexHandler(c *gin.Context) {
+	lang := i18n.FromRequest(c.Request)
+	// Remember the language chosen with the switcher
+	if i18n.Match(c.Query("lang")) != "" {
+		c.SetCookie(i18n.CookieName, lang, 365*24*3600, "/", "", false, false)
+	}
+
+	// Without authentication every visitor may refresh and export
+	user := auth.PrincipalFrom(c)
 	c.HTML(200, "index.html", gin.H{
-		"title":   "OpenStack Resources Report",
-		"version": version.GetVersionString(),
+		"title":      i18n.T(lang, "pdf.title"),
+		"version":    version.GetVersionString(),
+		"lang":       lang,
+		"languages":  i18n.Languages(),
+		"messages":   i18n.Messages(lang, "ui.", "type."),
+		"user":       user,
+		"canExport":  user == nil || user.Can(auth.PermissionExport),
+		"canRefresh": user == nil || user.Can(auth.PermissionRefresh),
 	})
 }
 
@@ -126,22 +275,26 @@ func getAPIDocs(c *gin.Context) {
 			{
 				"method":      "GET",
 				"path":        "/api/resources",
-				"description": "Get all OpenStack resources from cache or fetch from API",
+				"description": "Get all OpenStack resources from cache. Without a saved report the resources are fetched from the API if the client has the refresh permission, otherwise 404 is returned",
 				"parameters": []map[string]string{
 					{"name": "force", "type": "query", "description": "Force refresh from OpenStack API (optional)"},
+					{"name": "project", "type": "query", "description": "Filter by project ID or name, repeatable or comma separated (optional)"},
+					{"name": "type", "type": "query", "description": "Filter by resource type, e.g. server, repeatable or comma separated (optional)"},
+					{"name": "status", "type": "query", "description": "Filter by status, e.g. ACTIVE, repeatable or comma separated (optional)"},
+					{"name": "tag", "type": "query", "description": "Filter by tag: key or key=value, repeatable (optional)"},
 				},
 				"response": map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
-						"projects":        map[string]string{"type": "array", "description": "List of projects"},
-						"servers":         map[string]string{"type": "array", "description": "List of virtual machines"},
-						"volumes":         map[string]string{"type": "array", "description": "List of storage volumes"},
-						"load_balancers":  map[string]string{"type": "array", "description": "List of load balancers"},
-						"floating_ips":    map[string]string{"type": "array", "description": "List of floating IP addresses"},
-						"routers":         map[string]string{"type": "array", "description": "List of network routers"},
-						"vpn_services":    map[string]string{"type": "array", "description": "List of VPN IPSec site connections"},
-						"summary":         map[string]string{"type": "object", "description": "Resource counts summary"},
-						"generated_at":    map[string]string{"type": "string", "description": "Report generation timestamp"},
+						"projects":       map[string]string{"type": "array", "description": "List of projects"},
+						"servers":        map[string]string{"type": "array", "description": "List of virtual machines"},
+						"volumes":        map[string]string{"type": "array", "description": "List of storage volumes"},
+						"load_balancers": map[string]string{"type": "array", "description": "List of load balancers"},
+						"floating_ips":   map[string]string{"type": "array", "description": "List of floating IP addresses"},
+						"routers":        map[string]string{"type": "array", "description": "List of network routers"},
+						"vpn_services":   map[string]string{"type": "array", "description": "List of VPN services and IPSec site connections with IKE/IPsec policies"},
+						"summary":        map[string]string{"type": "object", "description": "Resource counts summary"},
+						"generated_at":   map[string]string{"type": "string", "description": "Report generation timestamp"},
 					},
 				},
 			},
@@ -158,11 +311,33 @@ func getAPIDocs(c *gin.Context) {
 					},
 				},
 			},
+			{
+				"method":      "DELETE",
+				"path":        "/api/refresh/{session_id}",
+				"description": "Cancel a refresh started with /api/refresh/progress. Collection is aborted, its progress stream ends with a cancelled message and the saved report is kept. Only the principal that started the refresh and admins may cancel it, others get 403. Answers 409 once the refresh is saving its report or its collection failed",
+				"parameters": []map[string]string{
+					{"name": "session_id", "type": "path", "description": "Session ID returned by /api/refresh/progress"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"message":    map[string]string{"type": "string", "description": "Confirmation message"},
+						"session_id": map[string]string{"type": "string", "description": "The cancelled session"},
+					},
+				},
+			},
 			{
 				"method":      "GET",
 				"path":        "/api/export/pdf",
-				"description": "Export current report to PDF format",
-				"parameters":  []map[string]string{},
+				"description": "Export current report to PDF format, optionally scoped by filters with the summary recalculated for the subset",
+				"parameters": []map[string]string{
+					{"name": "project", "type": "query", "description": "Filter by project ID or name, repeatable or comma separated (optional)"},
+					{"name": "type", "type": "query", "description": "Filter by resource type, e.g. server, repeatable or comma separated (optional)"},
+					{"name": "status", "type": "query", "description": "Filter by status, e.g. ACTIVE, repeatable or comma separated (optional)"},
+					{"name": "tag", "type": "query", "description": "Filter by tag: key or key=value, repeatable (optional)"},
+					{"name": "group_by_tag", "type": "query", "description": "Group detailed resources by value of this tag key (optional)"},
+					{"name": "lang", "type": "query", "description": "Report language, e.g. en or ru (optional)"},
+				},
 				"response": map[string]interface{}{
 					"type":        "file",
 					"description": "PDF file download",
@@ -172,6 +347,240 @@ func getAPIDocs(c *gin.Context) {
 					},
 				},
 			},
+			{
+				"method":      "GET",
+				"path":        "/api/topology",
+				"description": "Get network topology graph (servers, ports, subnets, networks, routers, load balancers, floating IPs)",
+				"parameters": []map[string]string{
+					{"name": "project", "type": "query", "description": "Project ID or name to limit the graph (optional)"},
+					{"name": "type", "type": "query", "description": "Filter by resource type, repeatable or comma separated (optional)"},
+					{"name": "status", "type": "query", "description": "Filter by status, repeatable or comma separated (optional)"},
+					{"name": "tag", "type": "query", "description": "Filter by tag: key or key=value, repeatable (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"nodes": map[string]string{"type": "array", "description": "Graph nodes with id, type, label and project"},
+						"edges": map[string]string{"type": "array", "description": "Graph edges with source, target and type"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/tags",
+				"description": "Get tag keys with value counts (from Nova/Cinder metadata and Neutron/Octavia/Heat tags), or resources grouped by a tag key",
+				"parameters": []map[string]string{
+					{"name": "key", "type": "query", "description": "Tag key to group by, e.g. owner (optional)"},
+					{"name": "tag", "type": "query", "description": "Filter by tag: key or key=value, repeatable (optional)"},
+					{"name": "resources", "type": "query", "description": "Include resources in groups: true/false (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"keys":   map[string]string{"type": "object", "description": "Tag keys with resource count per value (without key)"},
+						"groups": map[string]string{"type": "array", "description": "Groups with value, count and per-type counts (with key)"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/compliance",
+				"description": "Evaluate resources against the tagging compliance policy (YAML file from COMPLIANCE_POLICY_FILE, default compliance-policy.yaml)",
+				"parameters": []map[string]string{
+					{"name": "tag", "type": "query", "description": "Filter by tag: key or key=value, repeatable (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"evaluated":        map[string]string{"type": "integer", "description": "Resources covered by policy rules"},
+						"non_compliant":    map[string]string{"type": "integer", "description": "Resources with at least one violation"},
+						"total_violations": map[string]string{"type": "integer", "description": "Number of violations"},
+						"projects":         map[string]string{"type": "array", "description": "Per project counts and violations (missing_key or invalid_value)"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/rules",
+				"description": "Get findings of expression rules (YAML file from RULES_FILE, default rules.yaml) evaluated after the last refresh",
+				"parameters": []map[string]string{
+					{"name": "reevaluate", "type": "query", "description": "Evaluate rules against the saved report now: true/false (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"rules":       map[string]string{"type": "array", "description": "Rules with name, severity, message, expr and number of matches"},
+						"by_severity": map[string]string{"type": "object", "description": "Number of findings per severity"},
+						"findings":    map[string]string{"type": "array", "description": "Matched resources, most severe first"},
+					},
+				},
+			},
+			{
+				"method":      "POST",
+				"path":        "/api/notifications/test",
+				"description": "Send a test message to every notification target (YAML file from NOTIFY_CONFIG_FILE, default notifications.yaml). After each refresh the delta from the previous report (new and deleted resources, transitions into ERROR, new rule findings) is sent to webhook, Slack and Mattermost targets with per-project routing and hourly rate limits",
+				"parameters":  []map[string]string{},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"deliveries": map[string]string{"type": "array", "description": "Per target result with error if delivery failed"},
+					},
+				},
+			},
+			{
+				"method":      "POST",
+				"path":        "/api/mailer/send",
+				"description": "Mail the current PDF report to every recipient list now, each scoped to its projects, lists whose projects have no resources are skipped (YAML file from MAILER_CONFIG_FILE, default mailer.yaml). Reports are also sent on the configured daily or weekly schedule",
+				"parameters":  []map[string]string{},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"deliveries": map[string]string{"type": "array", "description": "Per recipient list result with number of resources and error if sending failed"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/audit",
+				"description": "Query the audit log of /api/resources, /api/refresh, /api/export/*, /api/mailer/send, token management and audit queries, newest first. State-changing calls include their JSON body fields with credentials redacted. Records are appended to AUDIT_LOG_FILE (default data/audit.jsonl), rotated at AUDIT_MAX_SIZE_MB keeping AUDIT_MAX_FILES files. Requires the admin permission",
+				"parameters": []map[string]string{
+					{"name": "user", "type": "query", "description": "User or token name (optional)"},
+					{"name": "ip", "type": "query", "description": "Client IP (optional)"},
+					{"name": "path", "type": "query", "description": "Path prefix, e.g. /api/export (optional)"},
+					{"name": "outcome", "type": "query", "description": "success, denied, failed, completed or cancelled (optional)"},
+					{"name": "since", "type": "query", "description": "RFC 3339 start time (optional)"},
+					{"name": "until", "type": "query", "description": "RFC 3339 end time (optional)"},
+					{"name": "limit", "type": "query", "description": "Maximum number of records, default 100, up to 1000 (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"records": map[string]string{"type": "array", "description": "Records with time, user, auth_method, client_ip, method, path, params, status, outcome, error, session_id and duration_ms. Background refresh results have no status and carry the number of resources"},
+						"count":   map[string]string{"type": "integer", "description": "Number of returned records"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/tokens",
+				"description": "List managed API tokens, requires the admin permission. Secrets and their digests are never returned",
+				"parameters":  []map[string]string{},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"tokens": map[string]string{"type": "array", "description": "Tokens with id, name, permissions, projects, created_by, created_at and expires_at"},
+					},
+				},
+			},
+			{
+				"method":      "POST",
+				"path":        "/api/tokens",
+				"description": "Create a managed API token scoped to operations and projects, requires the admin permission. Tokens are stored as SHA-256 digests in the data directory",
+				"parameters": []map[string]string{
+					{"name": "name", "type": "body", "description": "Token name, e.g. the CI job using it"},
+					{"name": "permissions", "type": "body", "description": "Any of read, export and refresh, default read (optional)"},
+					{"name": "projects", "type": "body", "description": "Project IDs the token may see, all projects if empty (optional)"},
+					{"name": "expires_at", "type": "body", "description": "RFC 3339 expiry time (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"token":  map[string]string{"type": "object", "description": "The created token"},
+						"secret": map[string]string{"type": "string", "description": "Bearer secret, only returned once"},
+					},
+				},
+			},
+			{
+				"method":      "DELETE",
+				"path":        "/api/tokens/{id}",
+				"description": "Revoke a managed API token, requires the admin permission",
+				"parameters": []map[string]string{
+					{"name": "id", "type": "path", "description": "Token ID"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"token": map[string]string{"type": "object", "description": "The revoked token"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/config",
+				"description": "Get the effective configuration with the source of every setting, secrets redacted. Settings are read from the YAML file selected with --config or CONFIG_FILE (default config.yaml), environment variables and command line flags, in increasing precedence. Requires the admin permission",
+				"parameters":  []map[string]string{},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"file":     map[string]string{"type": "string", "description": "Configuration file in use, empty if none"},
+						"settings": map[string]string{"type": "array", "description": "Settings with name, value, source (default, file, env or flag), env and flag"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/healthz",
+				"description": "Liveness probe, answers 200 while the process serves requests. Does not require authentication",
+				"parameters":  []map[string]string{},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"status":         map[string]string{"type": "string", "description": "Always ok"},
+						"version":        map[string]string{"type": "string", "description": "Application version"},
+						"uptime_seconds": map[string]string{"type": "integer", "description": "Seconds since start"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/readyz",
+				"description": "Readiness probe, answers 503 when a check fails. Checks storage writability, report age against HEALTH_REPORT_MAX_AGE and, with HEALTH_CHECK_KEYSTONE, that Keystone answers. Does not require authentication",
+				"parameters":  []map[string]string{},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"status":     map[string]string{"type": "string", "description": "ok or fail"},
+						"checked_at": map[string]string{"type": "string", "description": "Time of the checks"},
+						"checks":     map[string]string{"type": "array", "description": "Checks with name, status (ok, fail or skipped), message, duration_ms and details"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/ipam",
+				"description": "Get per-subnet IP utilization, free IP addresses and subnets close to exhaustion",
+				"parameters": []map[string]string{
+					{"name": "project", "type": "query", "description": "Project ID or name (optional)"},
+					{"name": "subnet", "type": "query", "description": "Subnet ID (optional)"},
+					{"name": "threshold", "type": "query", "description": "Utilization percentage for near exhaustion, default 80 (optional)"},
+					{"name": "free", "type": "query", "description": "Number of free IPs to list per subnet, default 10 (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"subnets":         map[string]string{"type": "array", "description": "Subnets with total, used and free IP counts"},
+						"near_exhaustion": map[string]string{"type": "array", "description": "Subnets with utilization above threshold"},
+					},
+				},
+			},
+			{
+				"method":      "GET",
+				"path":        "/api/export/dot",
+				"description": "Export network topology graph in Graphviz DOT format",
+				"parameters": []map[string]string{
+					{"name": "project", "type": "query", "description": "Project ID or name to limit the graph (optional)"},
+					{"name": "type", "type": "query", "description": "Filter by resource type, repeatable or comma separated (optional)"},
+					{"name": "status", "type": "query", "description": "Filter by status, repeatable or comma separated (optional)"},
+					{"name": "tag", "type": "query", "description": "Filter by tag: key or key=value, repeatable (optional)"},
+				},
+				"response": map[string]interface{}{
+					"type":        "file",
+					"description": "DOT file download",
+					"headers": map[string]string{
+						"Content-Type": "text/vnd.graphviz",
+					},
+				},
+			},
 			{
 				"method":      "GET",
 				"path":        "/api/status",
@@ -227,6 +636,23 @@ func getAPIDocs(c *gin.Context) {
 				"OS_INSECURE",
 			},
 		},
+		"access_control": map[string]interface{}{
+			"description": "Configured by AUTH_CONFIG_FILE (auth.yaml), the server does not start without it unless AUTH_DISABLED is set. Clients send Authorization: Bearer <token>, HTTP basic credentials, a Keystone X-Auth-Token or the session cookie set by /login",
+			"methods":     []string{auth.MethodToken, auth.MethodAPIToken, auth.MethodBasic, auth.MethodOIDC, auth.MethodKeystone},
+			"projects":    "Keystone users and project scoped API tokens only see resources, topology, IPAM, tags, compliance, rules, exports and refresh progress of their projects, and cannot mail reports",
+			"public":      []string{"/healthz", "/readyz", "/api/version"},
+			"permissions": map[string][]string{
+				string(auth.PermissionRead):    {"/api/resources", "/api/topology", "/api/ipam", "/api/tags", "/api/compliance", "/api/rules", "/api/status", "/api/docs"},
+				string(auth.PermissionExport):  {"/api/export/pdf", "/api/export/dot", "/api/mailer/send"},
+				string(auth.PermissionRefresh): {"/api/refresh", "/api/refresh/progress", "/api/refresh/{session_id}", "/api/progress", "/api/notifications/test"},
+				string(auth.PermissionAdmin):   {"/api/tokens", "/api/audit", "/api/config"},
+			},
+		},
+		"localization": map[string]interface{}{
+			"languages":   i18n.Languages(),
+			"default":     i18n.Default(),
+			"description": "Messages, the web UI and PDF reports use the language from the lang query parameter, the lang cookie or the Accept-Language header, DEFAULT_LANGUAGE otherwise",
+		},
 		"supported_resources": []map[string]string{
 			{"name": "Projects", "description": "OpenStack projects/tenants"},
 			{"name": "Servers", "description": "Virtual machines with Flavor and network info (Nova)"},
@@ -234,7 +660,12 @@ func getAPIDocs(c *gin.Context) {
 			{"name": "Load Balancers", "description": "Load balancing services with IP addresses (Octavia)"},
 			{"name": "Floating IPs", "description": "Public IP addresses with attachment info (Neutron)"},
 			{"name": "Routers", "description": "Network routers (Neutron)"},
-			{"name": "VPN Connections", "description": "IPSec site-to-site connections with peer info (Neutron VPNaaS)"},
+			{"name": "Ports", "description": "Network ports with fixed IPs and attached devices (Neutron)"},
+			{"name": "VPN Services", "description": "VPN services with router and external IP (Neutron VPNaaS)"},
+			{"name": "VPN Connections", "description": "IPSec site-to-site connections with IKE/IPsec policies, endpoint groups and weak crypto flags (Neutron VPNaaS)"},
+			{"name": "Object Containers", "description": "Containers with object count, bytes and public/private ACL (Swift, optional)"},
+			{"name": "DNS Zones", "description": "DNS zones and recordsets linked to floating IPs with dangling record detection (Designate, optional)"},
+			{"name": "Heat Stacks", "description": "Orchestration stacks; resources they manage carry stack_id and stack_name (Heat, optional)"},
 		},
 	}
 
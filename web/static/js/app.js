@@ -6,9 +6,24 @@ class OpenStackReporter {
 		this.filteredData = [];
 		this.currentPage = 1;
 		this.itemsPerPage = 50;
+		this.lang = (window.I18N && window.I18N.lang) || 'en';
+		this.messages = (window.I18N && window.I18N.messages) || {};
+		this.pluralRules = new Intl.PluralRules(this.lang);
 		this.init();
 	}
 
+	// t returns the UI message of key with {name} placeholders replaced by vars
+	t(key, vars = {}) {
+		const message = this.messages[key] !== undefined ? this.messages[key] : key;
+		return message.replace(/\{(\w+)\}/g, (match, name) => (name in vars ? vars[name] : match));
+	}
+
+	// tn returns the plural form of key matching count
+	tn(key, count) {
+		const form = `${key}.${this.pluralRules.select(count)}`;
+		return this.t(this.messages[form] !== undefined ? form : `${key}.other`, { count });
+	}
+
 	init() {
 		this.bindEvents();
 		this.loadData();
@@ -17,9 +32,15 @@ class OpenStackReporter {
 	bindEvents() {
 		document.getElementById('refreshBtn').addEventListener('click', () => this.refreshData());
 		document.getElementById('exportPdfBtn').addEventListener('click', () => this.exportToPDF());
+		document.getElementById('topologyBtn').addEventListener('click', () => this.showTopology());
+		document.getElementById('rulesBtn').addEventListener('click', () => this.showRules());
+		document.getElementById('rulesReevaluateBtn').addEventListener('click', () => this.loadRules(true));
+		document.getElementById('topologyProject').addEventListener('change', () => this.loadTopology());
 		document.getElementById('groupBy').addEventListener('change', () => this.applyFiltersAndSort());
 		document.getElementById('sortBy').addEventListener('change', () => this.applyFiltersAndSort());
 		document.getElementById('filterType').addEventListener('change', () => this.applyFiltersAndSort());
+		document.getElementById('filterTag').addEventListener('input', () => this.applyFiltersAndSort());
+		document.getElementById('groupTagKey').addEventListener('change', () => this.applyFiltersAndSort());
 	}
 
 	async loadData() {
@@ -27,18 +48,24 @@ class OpenStackReporter {
 			this.showLoading(true);
 			const response = await fetch('/api/resources');
 
+			if (response.status === 401) {
+				this.redirectToLogin();
+				return;
+			}
 			if (!response.ok) {
-				throw new Error(`HTTP error! status: ${response.status}`);
+				const result = await response.json().catch(() => ({}));
+				throw new Error(result.details ? `${result.error}. ${result.details}` : result.error || `HTTP error! status: ${response.status}`);
 			}
 
 			this.data = await response.json();
 			this.updateSummary();
+			this.updateTagKeys();
 			this.applyFiltersAndSort();
 			this.showLastUpdate();
 			this.hideError();
 		} catch (error) {
 			console.error('Error loading data:', error);
-			this.showError('Ошибка загрузки данных: ' + error.message);
+			this.showError(this.t('ui.error.load_data', { error: error.message }));
 		} finally {
 			this.showLoading(false);
 		}
@@ -49,6 +76,10 @@ class OpenStackReporter {
 			// Start progress refresh
 			const response = await fetch('/api/refresh/progress', { method: 'POST' });
 
+			if (response.status === 401) {
+				this.redirectToLogin();
+				return;
+			}
 			if (!response.ok) {
 				throw new Error(`HTTP error! status: ${response.status}`);
 			}
@@ -64,7 +95,7 @@ class OpenStackReporter {
 
 		} catch (error) {
 			console.error('Error starting refresh:', error);
-			this.showError('Ошибка запуска обновления данных: ' + error.message);
+			this.showError(this.t('ui.error.start_refresh', { error: error.message }));
 		}
 	}
 
@@ -73,11 +104,14 @@ class OpenStackReporter {
 		modal.show();
 
 		// Reset progress
-		this.updateProgress(0, 'Инициализация...');
+		this.updateProgress(0, this.t('ui.progress.initializing'));
 		document.getElementById('projectsList').innerHTML = '';
 		document.getElementById('resourceSummary').style.display = 'none';
 		document.getElementById('progressDoneBtn').style.display = 'none';
-		document.getElementById('progressCancelBtn').style.display = 'block';
+		document.getElementById('currentStatus').className = 'alert alert-info';
+		const cancelBtn = document.getElementById('progressCancelBtn');
+		cancelBtn.style.display = 'block';
+		cancelBtn.disabled = false;
 	}
 
 	connectToProgress(sessionId) {
@@ -100,14 +134,31 @@ class OpenStackReporter {
 		// Store for potential cancellation
 		this.currentEventSource = eventSource;
 
-		// Cancel button handler
-		document.getElementById('progressCancelBtn').onclick = () => {
-			if (this.currentEventSource) {
-				this.currentEventSource.close();
+		// Cancel button stops the refresh, the stream then reports it cancelled
+		document.getElementById('progressCancelBtn').onclick = () => this.cancelRefresh(sessionId);
+	}
+
+	async cancelRefresh(sessionId) {
+		const cancelBtn = document.getElementById('progressCancelBtn');
+		cancelBtn.disabled = true;
+		document.getElementById('statusText').textContent = this.t('ui.progress.cancelling');
+
+		try {
+			const response = await fetch(`/api/refresh/${encodeURIComponent(sessionId)}`, { method: 'DELETE' });
+
+			if (response.status === 401) {
+				this.redirectToLogin();
+				return;
 			}
-			const modal = bootstrap.Modal.getInstance(document.getElementById('progressModal'));
-			modal.hide();
-		};
+			if (!response.ok) {
+				const result = await response.json().catch(() => ({}));
+				throw new Error(result.details || result.error || `HTTP error! status: ${response.status}`);
+			}
+		} catch (error) {
+			console.error('Error cancelling refresh:', error);
+			cancelBtn.disabled = false;
+			this.showError(this.t('ui.error.cancel_refresh', { error: error.message }));
+		}
 	}
 
 	handleProgressMessage(data) {
@@ -127,28 +178,28 @@ class OpenStackReporter {
 					Math.round((data.current_step / data.total_steps) * 80) + 10,
 					`[${data.current_step}/${data.total_steps}] ${data.message}`
 				);
-				this.addProjectToList(data.project, 'progress', 'Сбор данных...');
+				this.addProjectToList(data.project, 'progress', this.t('ui.progress.collecting'));
 				break;
 
 			case 'resource_start':
-				this.updateProjectResource(data.project, data.resource_type, 'progress', `Сбор ${data.resource_type}...`);
+				this.updateProjectResource(data.project, data.resource_type, 'progress', this.t('ui.progress.collecting_resource', { type: data.resource_type }));
 				break;
 
 			case 'resource_complete':
 				const count = data.count || 0;
-				this.updateProjectResource(data.project, data.resource_type, 'success', `${count} найдено`);
+				this.updateProjectResource(data.project, data.resource_type, 'success', this.t('ui.progress.found', { count }));
 				break;
 
 			case 'resource_error':
-				this.updateProjectResource(data.project, data.resource_type, 'danger', 'Ошибка');
+				this.updateProjectResource(data.project, data.resource_type, 'danger', this.t('ui.progress.error'));
 				break;
 
 			case 'project_complete':
-				this.updateProjectStatus(data.project, 'success', `${data.count} ресурсов`);
+				this.updateProjectStatus(data.project, 'success', this.tn('ui.resources', data.count));
 				break;
 
 			case 'project_error':
-				this.updateProjectStatus(data.project, 'danger', 'Ошибка');
+				this.updateProjectStatus(data.project, 'danger', this.t('ui.progress.error'));
 				break;
 
 			case 'summary':
@@ -157,7 +208,7 @@ class OpenStackReporter {
 				break;
 
 			case 'complete':
-				this.updateProgress(100, 'Обновление завершено!');
+				this.updateProgress(100, this.t('ui.progress.complete'));
 				this.showResourceSummary(data.summary);
 				document.getElementById('progressDoneBtn').style.display = 'block';
 				document.getElementById('progressCancelBtn').style.display = 'none';
@@ -174,11 +225,22 @@ class OpenStackReporter {
 				break;
 
 			case 'error':
-				this.updateProgress(100, 'Ошибка: ' + data.message);
+				this.updateProgress(100, this.t('ui.progress.failed', { error: data.message }));
 				document.getElementById('currentStatus').className = 'alert alert-danger';
 				document.getElementById('progressCancelBtn').style.display = 'none';
 				document.getElementById('progressDoneBtn').style.display = 'block';
 
+				if (this.currentEventSource) {
+					this.currentEventSource.close();
+				}
+				break;
+
+			case 'cancelled':
+				this.updateProgress(100, this.t('ui.progress.cancelled'));
+				document.getElementById('currentStatus').className = 'alert alert-warning';
+				document.getElementById('progressCancelBtn').style.display = 'none';
+				document.getElementById('progressDoneBtn').style.display = 'block';
+
 				if (this.currentEventSource) {
 					this.currentEventSource.close();
 				}
@@ -215,13 +277,16 @@ class OpenStackReporter {
 
 		projectItem.innerHTML = `
 			<div class="d-flex justify-content-between align-items-center">
-				<strong>${projectName}</strong>
-				<span class="badge bg-secondary" id="project-status-${projectName}">${message}</span>
-			</div>
-			<div class="mt-2" id="project-resources-${projectName}">
-				<!-- Resource status will be added here -->
+				<strong></strong>
+				<span class="badge bg-secondary"></span>
 			</div>
+			<div class="mt-2"></div>
 		`;
+		projectItem.querySelector('strong').textContent = projectName;
+		const statusBadge = projectItem.querySelector('.badge');
+		statusBadge.id = `project-status-${projectName}`;
+		statusBadge.textContent = message;
+		projectItem.querySelector('.mt-2').id = `project-resources-${projectName}`;
 
 		projectsList.appendChild(projectItem);
 	}
@@ -265,21 +330,12 @@ class OpenStackReporter {
 			status === 'danger' ? '❌' :
 				status === 'progress' ? '🔄' : '⏳';
 
-		resourceItem.innerHTML = `${statusIcon} ${this.getResourceTypeLabel(resourceType)}: ${message}`;
+		resourceItem.textContent = `${statusIcon} ${this.getResourceTypeLabel(resourceType)}: ${message}`;
 	}
 
 	getResourceTypeLabel(resourceType) {
-		const labels = {
-			'servers': 'Серверы',
-			'volumes': 'Диски',
-			'floating_ips': 'Floating IP',
-			'routers': 'Роутеры',
-			'networks': 'Сети',
-			'load_balancers': 'Load Balancers',
-			'vpn_connections': 'VPN',
-			'k8s_clusters': 'K8s кластеры'
-		};
-		return labels[resourceType] || resourceType;
+		const key = `ui.collect.${resourceType}`;
+		return this.messages[key] !== undefined ? this.t(key) : resourceType;
 	}
 
 	showResourceSummary(summary) {
@@ -296,7 +352,12 @@ class OpenStackReporter {
 			'floating_ip': 'fas fa-globe',
 			'router': 'fas fa-network-wired',
 			'load_balancer': 'fas fa-balance-scale',
-			'vpn_service': 'fas fa-shield-alt'
+			'vpn_service': 'fas fa-shield-alt',
+			'vpn_connection': 'fas fa-exchange-alt',
+			'object_container': 'fas fa-archive',
+			'stack': 'fas fa-cubes',
+			'dns_zone': 'fas fa-address-book',
+			'dns_recordset': 'fas fa-address-card'
 		};
 
 		Object.entries(summary).forEach(([type, count]) => {
@@ -320,8 +381,28 @@ class OpenStackReporter {
 
 	async exportToPDF() {
 		try {
-			const response = await fetch('/api/export/pdf');
+			const params = new URLSearchParams({ lang: this.lang });
+			const filterType = document.getElementById('filterType').value;
+			if (filterType) {
+				params.append('type', filterType);
+			}
+			const tag = document.getElementById('filterTag').value.trim();
+			if (tag) {
+				params.append('tag', tag);
+			}
+			if (document.getElementById('groupBy').value === 'tag') {
+				const tagKey = document.getElementById('groupTagKey').value;
+				if (tagKey) {
+					params.append('group_by_tag', tagKey);
+				}
+			}
+			const query = params.toString() ? `?${params.toString()}` : '';
+			const response = await fetch(`/api/export/pdf${query}`);
 
+			if (response.status === 401) {
+				this.redirectToLogin();
+				return;
+			}
 			if (!response.ok) {
 				throw new Error(`HTTP error! status: ${response.status}`);
 			}
@@ -337,10 +418,174 @@ class OpenStackReporter {
 			window.URL.revokeObjectURL(url);
 		} catch (error) {
 			console.error('Error exporting PDF:', error);
-			this.showError('Ошибка экспорта PDF: ' + error.message);
+			this.showError(this.t('ui.error.export_pdf', { error: error.message }));
 		}
 	}
 
+	showRules() {
+		const modal = new bootstrap.Modal(document.getElementById('rulesModal'));
+		modal.show();
+		this.loadRules(false);
+	}
+
+	async loadRules(reevaluate) {
+		const stats = document.getElementById('rulesStats');
+		const summary = document.getElementById('rulesSummary');
+		const tbody = document.getElementById('rulesTableBody');
+		tbody.innerHTML = '';
+		summary.innerHTML = '';
+
+		try {
+			const response = await fetch(`/api/rules${reevaluate ? '?reevaluate=true' : ''}`);
+			const result = await response.json();
+			if (!response.ok) {
+				stats.textContent = result.details ? `${result.error}. ${result.details}` : result.error;
+				return;
+			}
+
+			stats.textContent = this.t('ui.rules.stats', {
+				time: new Date(result.evaluated_at).toLocaleString(this.lang),
+				count: result.findings.length
+			});
+
+			summary.innerHTML = result.rules.map(rule => `
+				<span class="badge ${this.getSeverityClass(rule.severity)} me-1 mb-1" title="${this.escapeHtml(rule.expr)}">
+					${this.escapeHtml(rule.name)}: ${rule.matches}
+				</span>
+			`).join('');
+
+			if (result.findings.length === 0) {
+				tbody.innerHTML = `<tr><td colspan="5" class="text-center text-muted">${this.t('ui.rules.no_findings')}</td></tr>`;
+				return;
+			}
+
+			tbody.innerHTML = result.findings.map(finding => `
+				<tr>
+					<td><span class="badge ${this.getSeverityClass(finding.severity)}">${this.escapeHtml(finding.severity)}</span></td>
+					<td>${this.escapeHtml(finding.rule)}</td>
+					<td>
+						<a href="#" data-resource-id="${this.escapeHtml(finding.resource_id)}">${this.escapeHtml(finding.resource_name || finding.resource_id)}</a>
+						<div class="small text-muted">${this.escapeHtml(this.getTypeDisplayName(finding.resource_type))}</div>
+					</td>
+					<td>${this.escapeHtml(finding.project_name)}</td>
+					<td>${this.escapeHtml(finding.message)}</td>
+				</tr>
+			`).join('');
+			tbody.querySelectorAll('a[data-resource-id]').forEach(link => {
+				link.addEventListener('click', event => {
+					event.preventDefault();
+					this.showResourceDetails(link.dataset.resourceId);
+				});
+			});
+		} catch (error) {
+			console.error('Error loading rules:', error);
+			stats.textContent = this.t('ui.error.load_rules', { error: error.message });
+		}
+	}
+
+	getSeverityClass(severity) {
+		const classes = {
+			'critical': 'bg-danger',
+			'warning': 'bg-warning text-dark',
+			'info': 'bg-info text-dark'
+		};
+		return classes[severity] || 'bg-secondary';
+	}
+
+	showTopology() {
+		const select = document.getElementById('topologyProject');
+		const selected = select.value;
+		select.innerHTML = `<option value="">${this.t('ui.topology.all_projects')}</option>`;
+		if (this.data && this.data.projects) {
+			[...this.data.projects]
+				.sort((a, b) => a.name.localeCompare(b.name))
+				.forEach(project => {
+					const option = document.createElement('option');
+					option.value = project.id;
+					option.textContent = project.name;
+					select.appendChild(option);
+				});
+		}
+		select.value = selected;
+
+		const modal = new bootstrap.Modal(document.getElementById('topologyModal'));
+		modal.show();
+		this.loadTopology();
+	}
+
+	async loadTopology() {
+		const project = document.getElementById('topologyProject').value;
+		const query = project ? `?project=${encodeURIComponent(project)}` : '';
+		document.getElementById('topologyDotBtn').href = `/api/export/dot${query}`;
+
+		try {
+			const response = await fetch(`/api/topology${query}`);
+			if (!response.ok) {
+				throw new Error(`HTTP error! status: ${response.status}`);
+			}
+			const graph = await response.json();
+			this.renderTopology(graph);
+		} catch (error) {
+			console.error('Error loading topology:', error);
+			document.getElementById('topologyStats').textContent = this.t('ui.error.load_topology', { error: error.message });
+		}
+	}
+
+	renderTopology(graph) {
+		const styles = {
+			'server': { shape: 'box', color: '#cfe2ff' },
+			'port': { shape: 'dot', size: 6, color: '#adb5bd' },
+			'subnet': { shape: 'ellipse', color: '#d1e7dd' },
+			'network': { shape: 'ellipse', color: '#a3cfbb' },
+			'external_network': { shape: 'hexagon', color: '#f8d7da' },
+			'router': { shape: 'diamond', color: '#e2d9f3' },
+			'load_balancer': { shape: 'triangle', color: '#ffe5d0' },
+			'floating_ip': { shape: 'star', color: '#fff3cd' }
+		};
+
+		const nodes = new vis.DataSet(graph.nodes.map(node => {
+			const style = styles[node.type] || { shape: 'box' };
+			return {
+				id: node.id,
+				label: node.type === 'port' ? '' : node.label,
+				title: [node.label, node.type, node.project_name, node.status, node.details]
+					.filter(Boolean).join('\n'),
+				shape: style.shape,
+				size: style.size,
+				color: style.color,
+				group: node.type
+			};
+		}));
+
+		const edges = new vis.DataSet(graph.edges.map(edge => ({
+			from: edge.source,
+			to: edge.target,
+			label: edge.label || '',
+			title: edge.type,
+			arrows: 'to',
+			font: { size: 9, align: 'middle' },
+			dashes: edge.type === 'nat' || edge.type === 'allocated_from'
+		})));
+
+		const container = document.getElementById('topologyGraph');
+		if (this.topologyNetwork) {
+			this.topologyNetwork.destroy();
+		}
+		this.topologyNetwork = new vis.Network(container, { nodes, edges }, {
+			physics: { stabilization: { iterations: 200 } },
+			interaction: { hover: true, tooltipDelay: 100 }
+		});
+
+		this.topologyNetwork.on('doubleClick', params => {
+			if (params.nodes.length === 1 && this.data.resources.some(r => r.id === params.nodes[0])) {
+				this.showResourceDetails(params.nodes[0]);
+			}
+		});
+
+		document.getElementById('topologyStats').textContent =
+			this.t('ui.topology.stats', { nodes: graph.nodes.length, edges: graph.edges.length });
+	}
+
 	applyFiltersAndSort() {
 		if (!this.data || !this.data.resources) return;
 
@@ -352,6 +597,19 @@ class OpenStackReporter {
 			filtered = filtered.filter(resource => resource.type === filterType);
 		}
 
+		// Apply tag filter: "key" or "key=value"
+		const filterTag = document.getElementById('filterTag').value.trim();
+		if (filterTag) {
+			const separator = filterTag.indexOf('=');
+			const key = separator > 0 ? filterTag.slice(0, separator).trim() : filterTag;
+			const value = separator > 0 ? filterTag.slice(separator + 1).trim().toLowerCase() : null;
+			filtered = filtered.filter(resource => {
+				const metadata = resource.metadata || {};
+				if (!(key in metadata)) return false;
+				return value === null || String(metadata[key]).toLowerCase() === value;
+			});
+		}
+
 		// Apply sorting
 		const sortBy = document.getElementById('sortBy').value;
 		const isDesc = sortBy.endsWith('_desc');
@@ -362,6 +620,10 @@ class OpenStackReporter {
 			let bValue = b[sortField];
 
 			if (sortField === 'created_at') {
+				// Unknown creation time is always sorted last
+				if (!aValue || !bValue) {
+					return (aValue ? -1 : 0) + (bValue ? 1 : 0);
+				}
 				aValue = new Date(aValue);
 				bValue = new Date(bValue);
 			}
@@ -388,7 +650,7 @@ class OpenStackReporter {
 		// Update table header based on current filter
 		this.updateTableHeader();
 
-		if (groupBy === 'project' || groupBy === 'type' || groupBy === 'status') {
+		if (this.isGroupedView(groupBy)) {
 			this.renderGroupedTable(tbody, groupBy);
 		} else {
 			this.renderFlatTable(tbody);
@@ -398,7 +660,7 @@ class OpenStackReporter {
 	updateTableHeader() {
 		// Заголовок всегда остается "Тип"
 		const typeHeader = document.querySelector('#resourcesTable thead th:nth-child(2)');
-		typeHeader.textContent = 'Тип';
+		typeHeader.textContent = this.t('ui.column.type');
 	}
 
 	renderGroupedTable(tbody, groupBy) {
@@ -407,7 +669,7 @@ class OpenStackReporter {
 		// Group resources
 		const groups = {};
 		this.filteredData.forEach(resource => {
-			const key = groupBy === 'project' ? resource.project_name : resource[groupBy];
+			const key = this.getGroupKey(resource, groupBy);
 			if (!groups[key]) {
 				groups[key] = [];
 			}
@@ -448,7 +710,7 @@ class OpenStackReporter {
 					<td colspan="6">
 						<strong>
 							<i class="fas fa-${this.getGroupIcon(item.groupBy)} me-2"></i>
-							${item.groupName} (${item.count})
+							${this.escapeHtml(item.groupName)} (${item.count})
 						</strong>
 					</td>
 				`;
@@ -474,34 +736,35 @@ class OpenStackReporter {
 	createResourceRow(resource) {
 		const row = document.createElement('tr');
 
-		const createdDate = new Date(resource.created_at).toLocaleDateString('ru-RU');
+		const createdDate = resource.created_at ? new Date(resource.created_at).toLocaleDateString(this.lang) : this.t('ui.unknown');
 		const statusClass = this.getStatusClass(resource.status, resource.type);
 		const typeClass = this.getTypeClass(resource.type);
 
 		row.innerHTML = `
             <td>
-                <strong>${resource.name || 'Без имени'}</strong>
+                <strong>${this.escapeHtml(resource.name || this.t('ui.unnamed'))}</strong>
                 <br>
-                <small class="text-muted">${this.getResourceSubtitle(resource)}</small>
+                <small class="text-muted">${this.escapeHtml(this.getResourceSubtitle(resource))}</small>
             </td>
             <td>
                 <span class="resource-type-badge ${typeClass}">
-                    ${this.getTypeDisplayName(resource.type)}
+                    ${this.escapeHtml(this.getTypeDisplayName(resource.type))}
                 </span>
             </td>
-            <td>${resource.project_name || 'Неизвестно'}</td>
+            <td>${this.escapeHtml(resource.project_name || this.t('ui.unknown'))}</td>
             <td>
                 <span class="status-badge ${statusClass}">
-                    ${resource.status}
+                    ${this.escapeHtml(resource.status)}
                 </span>
             </td>
             <td>${createdDate}</td>
             <td>
-                <button class="btn btn-sm btn-outline-primary" onclick="app.showResourceDetails('${resource.id}')">
+                <button class="btn btn-sm btn-outline-primary">
                     <i class="fas fa-eye"></i>
                 </button>
             </td>
         `;
+		row.querySelector('button').addEventListener('click', () => this.showResourceDetails(resource.id));
 
 		return row;
 	}
@@ -511,10 +774,10 @@ class OpenStackReporter {
 		let totalItems = this.filteredData.length;
 
 		// For grouped data, we need to count group headers as well
-		if (groupBy === 'project' || groupBy === 'type' || groupBy === 'status') {
+		if (this.isGroupedView(groupBy)) {
 			const groups = {};
 			this.filteredData.forEach(resource => {
-				const key = groupBy === 'project' ? resource.project_name : resource[groupBy];
+				const key = this.getGroupKey(resource, groupBy);
 				if (!groups[key]) {
 					groups[key] = [];
 				}
@@ -575,10 +838,10 @@ class OpenStackReporter {
 		let totalItems = this.filteredData.length;
 
 		// For grouped data, we need to count group headers as well
-		if (groupBy === 'project' || groupBy === 'type' || groupBy === 'status') {
+		if (this.isGroupedView(groupBy)) {
 			const groups = {};
 			this.filteredData.forEach(resource => {
-				const key = groupBy === 'project' ? resource.project_name : resource[groupBy];
+				const key = this.getGroupKey(resource, groupBy);
 				if (!groups[key]) {
 					groups[key] = [];
 				}
@@ -605,43 +868,57 @@ class OpenStackReporter {
 		const modalTitle = document.getElementById('modalTitle');
 		const modalBody = document.getElementById('modalBody');
 
-		modalTitle.textContent = `${resource.name || 'Ресурс'} (${this.getTypeDisplayName(resource.type)})`;
+		modalTitle.textContent = `${resource.name || this.t('ui.resource')} (${this.getTypeDisplayName(resource.type)})`;
 
 		modalBody.innerHTML = `
             <div class="resource-details">
-                <h6>Основная информация</h6>
-                <p><strong>ID:</strong> ${resource.id}</p>
-                <p><strong>Имя:</strong> ${resource.name || 'Не указано'}</p>
-                <p><strong>Тип:</strong> ${this.getTypeDisplayName(resource.type)}</p>
-                <p><strong>Проект:</strong> ${resource.project_name}</p>
-                <p><strong>Статус:</strong> ${resource.status}</p>
-                <p><strong>Создан:</strong> ${new Date(resource.created_at).toLocaleString('ru-RU')}</p>
-                ${resource.updated_at ? `<p><strong>Обновлен:</strong> ${new Date(resource.updated_at).toLocaleString('ru-RU')}</p>` : ''}
+                <h6>${this.t('ui.details.main')}</h6>
+                <p><strong>ID:</strong> ${this.escapeHtml(resource.id)}</p>
+                <p><strong>${this.t('ui.column.name')}:</strong> ${this.escapeHtml(resource.name || this.t('ui.not_set'))}</p>
+                <p><strong>${this.t('ui.column.type')}:</strong> ${this.escapeHtml(this.getTypeDisplayName(resource.type))}</p>
+                <p><strong>${this.t('ui.column.project')}:</strong> ${this.escapeHtml(resource.project_name)}</p>
+                <p><strong>${this.t('ui.column.status')}:</strong> ${this.escapeHtml(resource.status)}</p>
+                <p><strong>${this.t('ui.column.created')}:</strong> ${resource.created_at ? new Date(resource.created_at).toLocaleString(this.lang) : this.t('ui.unknown')}</p>
+                ${resource.updated_at ? `<p><strong>${this.t('ui.details.updated')}:</strong> ${new Date(resource.updated_at).toLocaleString(this.lang)}</p>` : ''}
+                ${resource.stack_name ? `<p><strong>${this.t('ui.details.managed_by_stack')}:</strong> <i class="fas fa-cubes me-1"></i>${this.escapeHtml(resource.stack_name)}</p>` : ''}
             </div>
+            ${this.renderResourceMetadata(resource)}
             ${this.renderResourceProperties(resource)}
         `;
 
 		modal.show();
 	}
 
+	renderResourceMetadata(resource) {
+		const metadata = resource.metadata || {};
+		const keys = Object.keys(metadata).sort();
+		if (keys.length === 0) return '';
+
+		const tags = keys.map(key => {
+			const label = metadata[key] ? `${key}=${metadata[key]}` : key;
+			return `<span class="badge bg-light text-dark border me-1 mb-1"><i class="fas fa-tag me-1"></i>${this.escapeHtml(label)}</span>`;
+		}).join('');
+		return `<div class="resource-details"><h6>${this.t('ui.details.metadata')}</h6><p>${tags}</p></div>`;
+	}
+
 	renderResourceProperties(resource) {
 		if (!resource.properties) return '';
 
 		const props = resource.properties;
-		let html = '<div class="resource-details"><h6>Дополнительные свойства</h6>';
+		let html = `<div class="resource-details"><h6>${this.t('ui.details.properties')}</h6>`;
 
 		switch (resource.type) {
 			case 'server':
 				html += `
-                    <p><strong>Flavor:</strong> ${props.flavor_name || 'Unknown'}</p>
-                    ${props.flavor_id ? `<p><strong>Flavor ID:</strong> ${props.flavor_id}</p>` : ''}
+                    <p><strong>Flavor:</strong> ${this.escapeHtml(props.flavor_name || 'Unknown')}</p>
+                    ${props.flavor_id ? `<p><strong>Flavor ID:</strong> ${this.escapeHtml(props.flavor_id)}</p>` : ''}
 
-                    <p><strong>Сети:</strong></p>
+                    <p><strong>${this.t('ui.field.networks')}:</strong></p>
                     <ul>
                 `;
 				if (props.networks) {
 					Object.entries(props.networks).forEach(([network, ip]) => {
-						html += `<li>${network}: ${ip}</li>`;
+						html += `<li>${this.escapeHtml(network)}: ${this.escapeHtml(ip)}</li>`;
 					});
 				}
 				html += '</ul>';
@@ -649,18 +926,18 @@ class OpenStackReporter {
 
 			case 'volume':
 				html += `
-                    <p><strong>Размер:</strong> ${props.size} GB</p>
-                    <p><strong>Тип:</strong> ${props.volume_type || 'Неизвестно'}</p>
-                    <p><strong>Загрузочный:</strong> ${props.bootable ? 'Да' : 'Нет'}</p>
+                    <p><strong>${this.t('ui.field.size')}:</strong> ${this.escapeHtml(props.size)} GB</p>
+                    <p><strong>${this.t('ui.column.type')}:</strong> ${this.escapeHtml(props.volume_type || this.t('ui.unknown'))}</p>
+                    <p><strong>${this.t('ui.field.bootable')}:</strong> ${props.bootable ? this.t('ui.yes') : this.t('ui.no')}</p>
                 `;
 				if (props.attached_to) {
-					html += `<p><strong>Подключен к:</strong> ${props.attached_to}</p>`;
+					html += `<p><strong>${this.t('ui.field.attached_to')}:</strong> ${this.escapeHtml(props.attached_to)}</p>`;
 				}
 				if (props.attachments && props.attachments.length > 0) {
-					html += `<p><strong>Детали подключения:</strong></p><ul>`;
+					html += `<p><strong>${this.t('ui.field.attachments')}:</strong></p><ul>`;
 					props.attachments.forEach(attachment => {
-						html += `<li>Сервер: ${attachment.server_name || attachment.server_id}`;
-						if (attachment.device) html += ` (${attachment.device})`;
+						html += `<li>${this.t('ui.field.server')}: ${this.escapeHtml(attachment.server_name || attachment.server_id)}`;
+						if (attachment.device) html += ` (${this.escapeHtml(attachment.device)})`;
 						html += `</li>`;
 					});
 					html += '</ul>';
@@ -669,47 +946,210 @@ class OpenStackReporter {
 
 			case 'floating_ip':
 				html += `
-                    <p><strong>IP адрес:</strong> ${props.floating_ip}</p>
-                    <p><strong>Сеть:</strong> ${props.floating_network_id}</p>
+                    <p><strong>${this.t('ui.field.ip_address')}:</strong> ${this.escapeHtml(props.floating_ip)}</p>
+                    <p><strong>${this.t('ui.field.network')}:</strong> ${this.escapeHtml(props.floating_network_id)}</p>
                 `;
 				if (props.fixed_ip) {
-					html += `<p><strong>Привязан к IP:</strong> ${props.fixed_ip}</p>`;
+					html += `<p><strong>${this.t('ui.field.fixed_ip')}:</strong> ${this.escapeHtml(props.fixed_ip)}</p>`;
 				}
 				if (props.attached_resource_name) {
-					html += `<p><strong>Привязан к ресурсу:</strong> ${props.attached_resource_name}</p>`;
+					html += `<p><strong>${this.t('ui.field.attached_resource')}:</strong> ${this.escapeHtml(props.attached_resource_name)}</p>`;
+				}
+				if (props.dns_names && props.dns_names.length > 0) {
+					html += `<p><strong>${this.t('ui.field.dns_names')}:</strong> ${this.escapeHtml(props.dns_names.join(', '))}</p>`;
+				}
+				break;
+
+			case 'stack':
+				html += `
+                    <p><strong>${this.t('ui.field.description')}:</strong> ${this.escapeHtml(props.description || this.t('ui.not_set'))}</p>
+                `;
+				if (props.status_reason) {
+					html += `<p><strong>${this.t('ui.field.status_reason')}:</strong> ${this.escapeHtml(props.status_reason)}</p>`;
+				}
+				if (props.tags && props.tags.length > 0) {
+					html += `<p><strong>${this.t('ui.field.tags')}:</strong> ${this.escapeHtml(props.tags.join(', '))}</p>`;
+				}
+				if (props.resources && props.resources.length > 0) {
+					html += `<p><strong>${this.t('ui.field.stack_resources', { count: props.resources.length })}:</strong></p><ul>`;
+					props.resources.forEach(item => {
+						html += `<li>${this.escapeHtml(item.logical_id)}: ${this.escapeHtml(item.type)} (${this.escapeHtml(item.status)})</li>`;
+					});
+					html += '</ul>';
+				}
+				break;
+
+			case 'object_container':
+				html += `
+                    <p><strong>${this.t('ui.field.objects')}:</strong> ${this.escapeHtml(props.object_count)}</p>
+                    <p><strong>${this.t('ui.field.size')}:</strong> ${this.formatBytes(props.bytes)}</p>
+                    <p><strong>${this.t('ui.field.access')}:</strong> ${props.public ? '🌐 ' + this.t('ui.field.public') : '🔒 ' + this.t('ui.field.private')}</p>
+                `;
+				if (props.read_acl && props.read_acl.length > 0) {
+					html += `<p><strong>Read ACL:</strong> ${this.escapeHtml(props.read_acl.join(', '))}</p>`;
+				}
+				if (props.write_acl && props.write_acl.length > 0) {
+					html += `<p><strong>Write ACL:</strong> ${this.escapeHtml(props.write_acl.join(', '))}</p>`;
+				}
+				if (props.storage_policy) {
+					html += `<p><strong>${this.t('ui.field.storage_policy')}:</strong> ${this.escapeHtml(props.storage_policy)}</p>`;
+				}
+				break;
+
+			case 'dns_zone':
+				html += `
+                    <p><strong>${this.t('ui.field.zone_type')}:</strong> ${this.escapeHtml(props.type)}</p>
+                    <p><strong>Email:</strong> ${this.escapeHtml(props.email)}</p>
+                    <p><strong>TTL:</strong> ${this.escapeHtml(props.ttl)}</p>
+                    <p><strong>Serial:</strong> ${this.escapeHtml(props.serial)}</p>
+                `;
+				break;
+
+			case 'dns_recordset':
+				html += `
+                    <p><strong>${this.t('ui.field.zone')}:</strong> ${this.escapeHtml(props.zone_name)}</p>
+                    <p><strong>${this.t('ui.field.record_type')}:</strong> ${this.escapeHtml(props.type)}</p>
+                    <p><strong>TTL:</strong> ${this.escapeHtml(props.ttl || this.t('ui.field.ttl_default'))}</p>
+                    <p><strong>${this.t('ui.field.records')}:</strong> ${this.escapeHtml((props.records || []).join(', '))}</p>
+                `;
+				if (props.floating_ips && props.floating_ips.length > 0) {
+					html += `<p><strong>Floating IP:</strong> ${this.escapeHtml(props.floating_ips.join(', '))}</p>`;
+				}
+				if (props.dangling && props.dangling.length > 0) {
+					html += `
+                        <div class="alert alert-danger">
+                            <i class="fas fa-exclamation-triangle me-2"></i>
+                            ${this.escapeHtml(this.t('ui.field.dangling', { addresses: props.dangling.join(', ') }))}
+                        </div>
+                    `;
 				}
 				break;
 
 			case 'vpn_service':
 				html += `
-                    <p><strong>Описание:</strong> ${props.description || 'Не указано'}</p>
-                    <p><strong>Router ID:</strong> ${props.router_id}</p>
+                    <p><strong>${this.t('ui.field.description')}:</strong> ${this.escapeHtml(props.description || this.t('ui.not_set'))}</p>
+                    <p><strong>Router ID:</strong> ${this.escapeHtml(props.router_id)}</p>
                 `;
 				if (props.subnet_id) {
-					html += `<p><strong>Subnet ID:</strong> ${props.subnet_id}</p>`;
+					html += `<p><strong>Subnet ID:</strong> ${this.escapeHtml(props.subnet_id)}</p>`;
 				}
 				if (props.peer_id) {
-					html += `<p><strong>Peer ID:</strong> ${props.peer_id}</p>`;
+					html += `<p><strong>Peer ID:</strong> ${this.escapeHtml(props.peer_id)}</p>`;
 				}
 				if (props.peer_address) {
-					html += `<p><strong>Peer Address:</strong> ${props.peer_address}</p>`;
+					html += `<p><strong>Peer Address:</strong> ${this.escapeHtml(props.peer_address)}</p>`;
 				}
 				if (props.auth_mode) {
-					html += `<p><strong>Auth Mode:</strong> ${props.auth_mode}</p>`;
+					html += `<p><strong>Auth Mode:</strong> ${this.escapeHtml(props.auth_mode)}</p>`;
 				}
 				if (props.ike_version) {
-					html += `<p><strong>IKE Version:</strong> ${props.ike_version}</p>`;
+					html += `<p><strong>IKE Version:</strong> ${this.escapeHtml(props.ike_version)}</p>`;
 				}
 				if (props.mtu && props.mtu > 0) {
-					html += `<p><strong>MTU:</strong> ${props.mtu}</p>`;
+					html += `<p><strong>MTU:</strong> ${this.escapeHtml(props.mtu)}</p>`;
+				}
+				if (props.external_ip) {
+					html += `<p><strong>${this.t('ui.field.external_ip')}:</strong> ${this.escapeHtml(props.external_ip)}</p>`;
+				}
+				if (props.connections && props.connections.length > 0) {
+					html += `<p><strong>${this.t('ui.field.connections')}:</strong> ${props.connections.length}</p>`;
+				}
+				break;
+
+			case 'vpn_connection':
+				if (props.weak_crypto && props.weak_crypto.length > 0) {
+					html += `<div class="alert alert-danger py-2"><i class="fas fa-exclamation-triangle me-2"></i>
+						<strong>${this.t('ui.field.weak_crypto')}:</strong> ${this.escapeHtml(props.weak_crypto.join(', '))}</div>`;
+				}
+				html += `
+                    <p><strong>${this.t('ui.field.description')}:</strong> ${this.escapeHtml(props.description || this.t('ui.not_set'))}</p>
+                    <p><strong>${this.t('ui.field.vpn_service')}:</strong> ${this.escapeHtml(props.vpn_service_name || props.vpn_service_id)}</p>
+                `;
+				if (props.router_id) {
+					html += `<p><strong>Router ID:</strong> ${this.escapeHtml(props.router_id)}</p>`;
+				}
+				if (props.peer_address) {
+					html += `<p><strong>Peer Address:</strong> ${this.escapeHtml(props.peer_address)}</p>`;
+				}
+				if (props.peer_id) {
+					html += `<p><strong>Peer ID:</strong> ${this.escapeHtml(props.peer_id)}</p>`;
+				}
+				if (props.auth_mode) {
+					html += `<p><strong>Auth Mode:</strong> ${this.escapeHtml(props.auth_mode)}</p>`;
+				}
+				if (props.ike_version) {
+					html += `<p><strong>IKE Version:</strong> ${this.escapeHtml(props.ike_version)}</p>`;
+				}
+				if (props.mtu && props.mtu > 0) {
+					html += `<p><strong>MTU:</strong> ${this.escapeHtml(props.mtu)}</p>`;
+				}
+				if (props.local_cidrs && props.local_cidrs.length > 0) {
+					html += `<p><strong>${this.t('ui.field.local_cidrs')}:</strong> ${this.escapeHtml(props.local_cidrs.join(', '))}</p>`;
+				}
+				if (props.peer_cidrs && props.peer_cidrs.length > 0) {
+					html += `<p><strong>${this.t('ui.field.peer_cidrs')}:</strong> ${this.escapeHtml(props.peer_cidrs.join(', '))}</p>`;
+				}
+				if (props.ike_policy) {
+					const ike = props.ike_policy;
+					html += `<p><strong>${this.t('ui.field.ike_policy')}:</strong> ${this.escapeHtml(ike.name || ike.id)}</p>
+						<ul>
+							<li>${this.t('ui.field.encryption')}: ${this.escapeHtml(ike.encryption_algorithm)}</li>
+							<li>${this.t('ui.field.authentication')}: ${this.escapeHtml(ike.auth_algorithm)}</li>
+							<li>PFS: ${this.escapeHtml(ike.pfs)}</li>
+							${ike.lifetime ? `<li>Lifetime: ${this.escapeHtml(ike.lifetime)}</li>` : ''}
+						</ul>`;
+				}
+				if (props.ipsec_policy) {
+					const ipsec = props.ipsec_policy;
+					html += `<p><strong>${this.t('ui.field.ipsec_policy')}:</strong> ${this.escapeHtml(ipsec.name || ipsec.id)}</p>
+						<ul>
+							<li>${this.t('ui.field.encryption')}: ${this.escapeHtml(ipsec.encryption_algorithm)}</li>
+							<li>${this.t('ui.field.authentication')}: ${this.escapeHtml(ipsec.auth_algorithm)}</li>
+							<li>PFS: ${this.escapeHtml(ipsec.pfs)}</li>
+							${ipsec.transform_protocol ? `<li>${this.t('ui.field.protocol')}: ${this.escapeHtml(ipsec.transform_protocol)}</li>` : ''}
+							${ipsec.lifetime ? `<li>Lifetime: ${this.escapeHtml(ipsec.lifetime)}</li>` : ''}
+						</ul>`;
+				}
+				break;
+
+			case 'port':
+				html += `
+                    <p><strong>MAC:</strong> ${this.escapeHtml(props.mac_address || this.t('ui.unknown'))}</p>
+                    <p><strong>${this.t('ui.field.network')}:</strong> ${this.escapeHtml(props.network_id)}</p>
+                    <p><strong>${this.t('ui.field.owner')}:</strong> ${this.escapeHtml(props.device_owner || this.t('ui.field.not_attached'))}</p>
+                `;
+				if (props.device_id) {
+					html += `<p><strong>${this.t('ui.field.device')}:</strong> ${this.escapeHtml(props.device_id)}</p>`;
+				}
+				if (props.fixed_ips && props.fixed_ips.length > 0) {
+					html += '<p><strong>Fixed IP:</strong></p><ul>';
+					props.fixed_ips.forEach(ip => {
+						html += `<li>${this.escapeHtml(ip.ip_address)} (subnet ${this.escapeHtml(ip.subnet_id)})</li>`;
+					});
+					html += '</ul>';
+				}
+				if (props.security_groups && props.security_groups.length > 0) {
+					html += `<p><strong>${this.t('ui.field.security_groups')}:</strong> ${this.escapeHtml(props.security_groups.join(', '))}</p>`;
+				}
+				break;
+
+			case 'network':
+				if (props.subnets && props.subnets.length > 0) {
+					html += `<p><strong>${this.t('ui.field.subnets')}:</strong></p><ul>`;
+					props.subnets.forEach(subnet => {
+						const usage = subnet.total_ips > 0 ?
+							this.t('ui.field.subnet_usage', { used: subnet.used_ips, total: subnet.total_ips, percent: subnet.utilization }) : '';
+						html += `<li>${this.escapeHtml(subnet.name || subnet.id)}: ${this.escapeHtml(subnet.cidr)}${usage}</li>`;
+					});
+					html += '</ul>';
 				}
 				break;
 
 			case 'load_balancer':
 				html += `
-                    <p><strong>VIP адрес:</strong> ${props.vip_address}</p>
-                    <p><strong>Статус провизионирования:</strong> ${props.provisioning_status}</p>
-                    <p><strong>Операционный статус:</strong> ${props.operating_status}</p>
+                    <p><strong>${this.t('ui.field.vip')}:</strong> ${this.escapeHtml(props.vip_address)}</p>
+                    <p><strong>${this.t('ui.field.provisioning_status')}:</strong> ${this.escapeHtml(props.provisioning_status)}</p>
+                    <p><strong>${this.t('ui.field.operating_status')}:</strong> ${this.escapeHtml(props.operating_status)}</p>
                 `;
 				break;
 		}
@@ -732,7 +1172,8 @@ class OpenStackReporter {
 			(summary.total_floating_ips || 0) +
 			(summary.total_routers || 0) +
 			(summary.total_load_balancers || 0) +
-			(summary.total_vpn_services || 0);
+			(summary.total_vpn_services || 0) +
+			(summary.total_vpn_connections || 0);
 		document.getElementById('totalNetwork').textContent = networkTotal;
 	}
 
@@ -742,11 +1183,27 @@ class OpenStackReporter {
 			const lastUpdateInfo = document.getElementById('lastUpdateInfo');
 			const lastUpdateText = document.getElementById('lastUpdateText');
 
-			lastUpdateText.textContent = `Последнее обновление: ${lastUpdate.toLocaleString('ru-RU')}`;
+			let text = this.t('ui.last_update', { time: lastUpdate.toLocaleString(this.lang) });
+			const unresolved = this.data.unresolved_owners || [];
+			if (unresolved.length > 0) {
+				text += ' | ' + this.t('ui.unresolved_owners', { count: unresolved.length });
+			}
+			lastUpdateText.textContent = text;
 			lastUpdateInfo.style.display = 'block';
 		}
 	}
 
+	// escapeHtml makes text from OpenStack, which tenants control, safe to interpolate into HTML
+	escapeHtml(value) {
+		const entities = { '&': '&amp;', '<': '&lt;', '>': '&gt;', '"': '&quot;', "'": '&#39;' };
+		return String(value ?? '').replace(/[&<>"']/g, ch => entities[ch]);
+	}
+
+	// redirectToLogin sends the browser to the login page once the session has expired
+	redirectToLogin() {
+		window.location.href = `/login?next=${encodeURIComponent(window.location.pathname + window.location.search)}`;
+	}
+
 	showLoading(show) {
 		const spinner = document.getElementById('loadingSpinner');
 		spinner.style.display = show ? 'block' : 'none';
@@ -774,31 +1231,88 @@ class OpenStackReporter {
 		return 'status-active';
 	}
 
+	formatBytes(bytes) {
+		if (!bytes) return '0 B';
+		const units = ['B', 'KB', 'MB', 'GB', 'TB'];
+		let value = bytes;
+		let unit = 0;
+		while (value >= 1024 && unit < units.length - 1) {
+			value /= 1024;
+			unit++;
+		}
+		return `${value.toFixed(unit === 0 ? 0 : 1)} ${units[unit]}`;
+	}
+
 	getTypeClass(type) {
 		return `type-${type}`;
 	}
 
 	getTypeDisplayName(type) {
-		const types = {
-			'server': 'Виртуальная машина',
-			'volume': 'Диск',
-			'floating_ip': 'Floating IP',
-			'router': 'Роутер',
-			'network': 'Сеть',
-			'load_balancer': 'Балансировщик',
-			'vpn_service': 'VPN сервис',
-			'cluster': 'Kubernetes кластер'
-		};
-		return types[type] || type;
+		// Type names are shared with PDF reports
+		const key = `type.${type}`;
+		return this.messages[key] !== undefined ? this.t(key) : type;
 	}
 
 
 
+	isGroupedView(groupBy) {
+		return ['project', 'type', 'status', 'stack', 'tag'].includes(groupBy);
+	}
+
+	updateTagKeys() {
+		const select = document.getElementById('groupTagKey');
+		const selected = select.value;
+		const keys = new Set();
+		(this.data.resources || []).forEach(resource => {
+			Object.keys(resource.metadata || {}).forEach(key => keys.add(key));
+		});
+
+		select.innerHTML = '';
+		if (keys.size === 0) {
+			select.innerHTML = `<option value="">${this.t('ui.no_tags')}</option>`;
+			return;
+		}
+		[...keys].sort((a, b) => a.localeCompare(b)).forEach(key => {
+			const option = document.createElement('option');
+			option.value = key;
+			option.textContent = key;
+			select.appendChild(option);
+		});
+		if (keys.has(selected)) {
+			select.value = selected;
+		}
+	}
+
+	getGroupKey(resource, groupBy) {
+		switch (groupBy) {
+			case 'project':
+				return resource.project_name;
+			case 'stack':
+				// Стеки группируются вместе со своими ресурсами
+				if (resource.type === 'stack') {
+					return this.t('ui.group_key.stack', { name: resource.name });
+				}
+				return resource.stack_name ? this.t('ui.group_key.stack', { name: resource.stack_name }) : this.t('ui.group_key.no_stack');
+			case 'tag': {
+				const key = document.getElementById('groupTagKey').value;
+				const metadata = resource.metadata || {};
+				if (!key || !(key in metadata)) {
+					return this.t('ui.group_key.no_tag');
+				}
+				return `${key}: ${metadata[key] || key}`;
+			}
+			default:
+				return resource[groupBy];
+		}
+	}
+
 	getGroupIcon(groupBy) {
 		const icons = {
 			'project': 'folder',
 			'type': 'layer-group',
-			'status': 'circle'
+			'status': 'circle',
+			'stack': 'cubes',
+			'tag': 'tag'
 		};
 		return icons[groupBy] || 'list';
 	}
@@ -836,9 +1350,9 @@ class OpenStackReporter {
 			case 'floating_ip':
 				// Показываем к чему подключен
 				if (props.attached_resource_name) {
-					return `Подключен к: ${props.attached_resource_name}`;
+					return this.t('ui.subtitle.attached_to', { name: props.attached_resource_name });
 				}
-				return 'Не подключен';
+				return this.t('ui.field.not_attached');
 
 			case 'load_balancer':
 				// Показываем внутренний IP (и внешний если есть)
@@ -849,7 +1363,7 @@ class OpenStackReporter {
 				if (props.floating_ip && props.floating_ip !== props.vip_address) {
 					ips.push(props.floating_ip);
 				}
-				return ips.length > 0 ? ips.join(', ') : 'Нет IP';
+				return ips.length > 0 ? ips.join(', ') : this.t('ui.subtitle.no_ip');
 
 			case 'network':
 				// Показываем подсети и статус shared/external
@@ -869,7 +1383,40 @@ class OpenStackReporter {
 
 			case 'vpn_service':
 				// Показываем Peer Address
-				return props.peer_address || 'Нет Peer Address';
+				return props.peer_address || this.t('ui.subtitle.no_peer');
+
+			case 'port':
+				// Показываем IP адреса и владельца порта
+				let port_ips = (props.fixed_ips || []).map(ip => ip.ip_address).join(', ');
+				return `${port_ips || this.t('ui.subtitle.no_ip')}, ${props.device_owner || this.t('ui.subtitle.not_attached')}`;
+
+			case 'vpn_connection':
+				// Показываем Peer Address и предупреждение о слабой криптографии
+				let vpn_subtitle = props.peer_address || this.t('ui.subtitle.no_peer');
+				if (props.ike_version) {
+					vpn_subtitle += `, ${props.ike_version}`;
+				}
+				if (props.weak_crypto && props.weak_crypto.length > 0) {
+					vpn_subtitle += ' ⚠️ ' + this.t('ui.subtitle.weak_crypto');
+				}
+				return vpn_subtitle;
+
+			case 'stack':
+				// Показываем количество ресурсов стека
+				return this.tn('ui.resources', (props.resources || []).length);
+
+			case 'object_container':
+				// Показываем количество объектов, размер и доступ
+				let container_access = props.public ? '🌐' : '🔒';
+				return `${props.object_count} objects, ${this.formatBytes(props.bytes)} ${container_access}`;
+
+			case 'dns_recordset':
+				// Показываем тип записи и значения
+				let dns_subtitle = `${props.type}: ${(props.records || []).join(', ')}`;
+				if (props.dangling && props.dangling.length > 0) {
+					dns_subtitle += ' ⚠️ ' + this.t('ui.subtitle.dangling');
+				}
+				return dns_subtitle;
 
 			default:
 				// Для остальных типов показываем ID